@@ -0,0 +1,60 @@
+#ifndef _PARAMS_H_
+#define _PARAMS_H_
+
+#include "common.h"
+
+typedef struct Params {
+    unsigned int   input_size;
+    float   repetitions;
+    int   n_warmup;
+    int   n_reps;
+    int  exp;
+}Params;
+
+static void usage() {
+    fprintf(stderr,
+        "\nUsage:  ./program [options]"
+        "\n"
+        "\nGeneral options:"
+        "\n    -h        help"
+        "\n    -w <W>    # of untimed warmup iterations (default=2)"
+        "\n    -e <E>    # of timed repetition iterations (default=5)"
+        "\n    -x <X>    Weak (0) or strong (1) scaling (default=0)"
+        "\n"
+        "\nBenchmark-specific options:"
+        "\n    -i <I>    input size (default=8K elements)"
+        "\n    -p <P>    # of compute repetitions (default=2)"
+        "\n");
+}
+
+struct Params input_params(int argc, char **argv) {
+    struct Params p;
+    p.input_size    = 8 << 10;
+    p.repetitions    = 1.0;
+    p.n_warmup      = 2;
+    p.n_reps        = 5;
+    p.exp           = 0;
+
+    int opt;
+    while((opt = getopt(argc, argv, "hi:p:w:e:")) >= 0) {
+        switch(opt) {
+        case 'h':
+        usage();
+        exit(0);
+        break;
+        case 'i': p.input_size    = atoi(optarg); break;
+        case 'p': p.repetitions   = atof(optarg); break;
+        case 'w': p.n_warmup      = atoi(optarg); break;
+        case 'e': p.n_reps        = atoi(optarg); break;
+        case 'x': p.exp           = atoi(optarg); break;
+        default:
+            fprintf(stderr, "\nUnrecognized option!\n");
+            usage();
+            exit(0);
+        }
+    }
+    assert(NR_DPUS > 0 && "Invalid # of dpus!");
+
+    return p;
+}
+#endif
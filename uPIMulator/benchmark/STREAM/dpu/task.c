@@ -0,0 +1,17 @@
+/*
+* STREAM kernels
+*
+* NOTE: the uPIMulator build compiles this single source, which includes the kernel that OP
+* selects like the ${OP}.c source of the Makefile.
+*/
+#if defined(copyw)
+#include "copyw.c"
+#elif defined(add)
+#include "add.c"
+#elif defined(scale)
+#include "scale.c"
+#elif defined(triad)
+#include "triad.c"
+#else // copy
+#include "copy.c"
+#endif
@@ -0,0 +1,61 @@
+#ifndef _PARAMS_H_
+#define _PARAMS_H_
+
+#include "common.h"
+
+typedef struct Params {
+    unsigned int   input_size;
+    unsigned int   stride;
+    int   n_warmup;
+    int   n_reps;
+    int  exp;
+}Params;
+
+static void usage() {
+    fprintf(stderr,
+        "\nUsage:  ./program [options]"
+        "\n"
+        "\nGeneral options:"
+        "\n    -h        help"
+        "\n    -w <W>    # of untimed warmup iterations (default=1)"
+        "\n    -e <E>    # of timed repetition iterations (default=3)"
+        "\n    -x <X>    Weak (0) or strong (1) scaling (default=0)"
+        "\n"
+        "\nBenchmark-specific options:"
+        "\n    -i <I>    input size (default=8K elements)"
+        "\n    -s <S>    stride (default=2)"
+        "\n");
+}
+
+struct Params input_params(int argc, char **argv) {
+    struct Params p;
+    p.input_size    = 8 << 10;
+    p.stride        = 2;
+    p.n_warmup      = 1;
+    p.n_reps        = 3;
+    p.exp           = 0;
+
+    int opt;
+    while((opt = getopt(argc, argv, "hi:w:e:x:s:")) >= 0) {
+        switch(opt) {
+        case 'h':
+        usage();
+        exit(0);
+        break;
+        case 'i': p.input_size    = atoi(optarg); break;
+        case 'w': p.n_warmup      = atoi(optarg); break;
+        case 'e': p.n_reps        = atoi(optarg); break;
+        case 'x': p.exp           = atoi(optarg); break;
+        case 's': p.stride        = atoi(optarg); break;
+        default:
+            fprintf(stderr, "\nUnrecognized option!\n");
+            usage();
+            exit(0);
+        }
+    }
+    assert(NR_DPUS > 0 && "Invalid # of dpus!");
+    assert((NR_TASKLETS & (NR_TASKLETS - 1)) == 0 && "Use a power-of-two number of tasklets!");
+
+    return p;
+}
+#endif
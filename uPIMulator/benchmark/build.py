@@ -8,6 +8,8 @@ if __name__ == "__main__":
     parser = argparse.ArgumentParser()
     parser.add_argument("--num_dpus", type=int, default=1)
     parser.add_argument("--num_tasklets", type=int, default=1)
+    parser.add_argument("--benchmark", type=str, default=None)
+    parser.add_argument("--define", type=str, action="append", default=[])
     args = parser.parse_args()
 
     benchmark_dir_path = os.path.dirname(__file__)
@@ -18,11 +20,17 @@ if __name__ == "__main__":
         shutil.rmtree(build_dir_path)
     os.makedirs(build_dir_path)
 
+    # NOTE: compile-time knobs of the benchmark, such as OP=ADD or BL=10
+    defines = []
+    for define in args.define:
+        defines += ["-D", define]
+
     subprocess.run(
     [
         "cmake",
         "-D", f"NR_DPUS={args.num_dpus}",
         "-D", f"NR_TASKLETS={args.num_tasklets}",
+        *defines,
         "-D", "CMAKE_C_FLAGS=-O3 -fno-tree-dce -fno-toplevel-reorder -g1",
         "-D", "CMAKE_CXX_FLAGS=-O3 -fno-tree-dce -fno-toplevel-reorder -g1",
         "-D", "CMAKE_BUILD_TYPE=Release",
@@ -33,4 +41,9 @@ if __name__ == "__main__":
     check=True
 )
 
-    subprocess.run(["ninja", "-C", build_dir_path])
+    # NOTE: the knobs of one benchmark may not compile others, so only its target is built if given
+    targets = []
+    if args.benchmark is not None:
+        targets = [f"{args.benchmark}_device"]
+
+    subprocess.run(["ninja", "-C", build_dir_path, *targets])
@@ -12,7 +12,7 @@ NUM_CHANNELS=1
 NUM_RANKS_PER_CHANNEL=1
 NUM_DPUS_PER_RANK=1
 NUM_TASKLETS=1
-DATA_PREP_PARAMS=16
+DATA_PREP_PARAMS="size=16"
 LOAD_LOCAL=0
 
 mkdir "${BIN_DIRPATH}"
@@ -8,6 +8,9 @@ import (
 type Assemblable interface {
 	Init(command_line_parser *misc.CommandLineParser)
 
+	// Params declares the runtime parameters of the benchmark, without Init.
+	Params() *misc.BenchmarkParams
+
 	InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream
 	OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream
 
@@ -16,3 +19,12 @@ type Assemblable interface {
 
 	NumExecutions() int
 }
+
+// LayoutAssemblable is an Assemblable that describes the elements of its outputs, so that the
+// simulator reports a mismatching output as an element rather than a byte.
+type LayoutAssemblable interface {
+	// OutputLayout returns the size of the elements of an output, named like the keys of
+	// OutputDpuHost or DPU_MRAM_HEAP_POINTER_NAME, and the bytes [begin, end) of every element that
+	// the DPU measures rather than computes (e.g., cycles) and that are not compared.
+	OutputLayout(name string) (int64, int64, int64)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"uPIMulator/src/assembler/prim"
 	"uPIMulator/src/global"
 	"uPIMulator/src/misc"
@@ -33,22 +34,12 @@ func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
-	this.assemblables = make(map[string]Assemblable, 0)
-
-	this.assemblables["BS"] = new(prim.Bs)
-	this.assemblables["GEMV"] = new(prim.Gemv)
-	this.assemblables["HST-L"] = new(prim.HstL)
-	this.assemblables["HST-S"] = new(prim.HstS)
-	this.assemblables["MLP"] = new(prim.Mlp)
-	this.assemblables["RED"] = new(prim.Red)
-	this.assemblables["SCAN-RSS"] = new(prim.ScanRss)
-	this.assemblables["SCAN-SSA"] = new(prim.ScanSsa)
-	this.assemblables["SEL"] = new(prim.Sel)
-	this.assemblables["TRNS"] = new(prim.Trns)
-	this.assemblables["TS"] = new(prim.Ts)
-	this.assemblables["UNI"] = new(prim.Uni)
-	this.assemblables["VAS"] = new(prim.Vas)
-	this.assemblables["VA"] = new(prim.Va)
+	this.assemblables = Assemblables()
+
+	// NOTE: a manifest describes the data of any DPU program, so it takes over the benchmark name
+	if command_line_parser.StringParameter("manifest") != "" {
+		this.assemblables[this.benchmark] = new(Manifest)
+	}
 
 	if assemblable, found := this.assemblables[this.benchmark]; found {
 		assemblable.Init(command_line_parser)
@@ -59,12 +50,65 @@ func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
 	}
 }
 
+// Assemblables returns a new assemblable of every benchmark, by benchmark name.
+func Assemblables() map[string]Assemblable {
+	assemblables := make(map[string]Assemblable, 0)
+
+	assemblables["Arithmetic-Throughput"] = new(prim.ArithmeticThroughput)
+	assemblables["BFS"] = new(prim.Bfs)
+	assemblables["BS"] = new(prim.Bs)
+	assemblables["CPU-DPU"] = new(prim.CpuDpu)
+	assemblables["GEMV"] = new(prim.Gemv)
+	assemblables["HST-L"] = new(prim.HstL)
+	assemblables["HST-S"] = new(prim.HstS)
+	assemblables["MLP"] = new(prim.Mlp)
+	assemblables["MRAM-Latency"] = new(prim.MramLatency)
+	assemblables["NW"] = new(prim.Nw)
+	assemblables["Operational-Intensity"] = new(prim.OperationalIntensity)
+	assemblables["RED"] = new(prim.Red)
+	assemblables["Random-GUPS"] = new(prim.RandomGups)
+	assemblables["SCAN-RSS"] = new(prim.ScanRss)
+	assemblables["SCAN-SSA"] = new(prim.ScanSsa)
+	assemblables["SEL"] = new(prim.Sel)
+	assemblables["STREAM"] = new(prim.Stream)
+	assemblables["STRIDED"] = new(prim.Strided)
+	assemblables["SpMV"] = new(prim.Spmv)
+	assemblables["TRNS"] = new(prim.Trns)
+	assemblables["TS"] = new(prim.Ts)
+	assemblables["UNI"] = new(prim.Uni)
+	assemblables["VAS"] = new(prim.Vas)
+	assemblables["VA"] = new(prim.Va)
+	assemblables["WRAM"] = new(prim.Wram)
+
+	return assemblables
+}
+
+// StringifyParamHelpMsgs returns the runtime parameters of every benchmark.
+func StringifyParamHelpMsgs() string {
+	assemblables := Assemblables()
+
+	benchmarks := make([]string, 0)
+	for benchmark := range assemblables {
+		benchmarks = append(benchmarks, benchmark)
+	}
+
+	slices.Sort(benchmarks)
+
+	str := "BENCHMARK_PARAMS\n"
+	for _, benchmark := range benchmarks {
+		str += assemblables[benchmark].Params().StringifyHelpMsgs()
+	}
+
+	return str
+}
+
 func (this *Assembler) Assemble() {
 	this.AssembleInputDpuHost()
 	this.AssembleOutputDpuHost()
 	this.AssembleInputDpuMramHeapPointerName()
 	this.AssembleOutputDpuMramHeapPointerName()
 	this.AssembleNumExecutions()
+	this.AssembleOutputLayouts()
 }
 
 func (this *Assembler) AssembleInputDpuHost() {
@@ -195,3 +239,46 @@ func (this *Assembler) AssembleNumExecutions() {
 
 	file_dumper.WriteLines(lines)
 }
+
+// AssembleOutputLayouts writes the layout of every output as "name: element_size begin end". An
+// output of a benchmark without a layout is compared byte by byte.
+func (this *Assembler) AssembleOutputLayouts() {
+	assemblable := this.assemblables[this.benchmark]
+
+	names := make([]string, 0)
+	for execution := 0; execution < assemblable.NumExecutions(); execution++ {
+		for name := range assemblable.OutputDpuHost(execution, 0) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	names = append(names, "DPU_MRAM_HEAP_POINTER_NAME")
+
+	lines := make([]string, 0)
+	for _, name := range names {
+		element_size, begin, end := int64(1), int64(0), int64(0)
+		if layout_assemblable, ok := assemblable.(LayoutAssemblable); ok {
+			element_size, begin, end = layout_assemblable.OutputLayout(name)
+		}
+
+		if element_size <= 0 || begin < 0 || begin > end || end > element_size {
+			err_msg := fmt.Sprintf(
+				"layout of %s (%d %d %d) is not valid",
+				name,
+				element_size,
+				begin,
+				end,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %d %d %d", name, element_size, begin, end))
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "output_layouts.txt"))
+	file_dumper.WriteLines(lines)
+}
@@ -0,0 +1,380 @@
+package assembler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+type ManifestRegion struct {
+	Offset_ int64  `json:"offset"`
+	File_   string `json:"file"`
+}
+
+// ManifestDpu lists the files of a DPU in an execution. An entry without a DPU ID applies to every
+// DPU that has no entry of its own.
+type ManifestDpu struct {
+	DpuId_        *int              `json:"dpu"`
+	Host_         map[string]string `json:"host"`
+	Mram_         []ManifestRegion  `json:"mram"`
+	ExpectedHost_ map[string]string `json:"expected_host"`
+	ExpectedMram_ *ManifestRegion   `json:"expected_mram"`
+}
+
+type ManifestExecution struct {
+	Dpus_ []ManifestDpu `json:"dpus"`
+}
+
+type ManifestFile struct {
+	Executions_ []ManifestExecution `json:"executions"`
+}
+
+// Manifest is an assemblable driven by a JSON manifest instead of Go code. For every execution
+// and every DPU, the manifest names the binary files that fill host symbols (InputDpuHost), the
+// MRAM heap (InputDpuMramHeapPointerName) and the expected outputs. File paths are relative to the
+// manifest and may contain {execution} and {dpu}, which are replaced by the execution and the DPU
+// ID.
+type Manifest struct {
+	num_dpus int
+
+	dirpath    string
+	executions []ManifestExecution
+}
+
+func (this *Manifest) Init(command_line_parser *misc.CommandLineParser) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	path := command_line_parser.StringParameter("manifest")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	manifest_file := new(ManifestFile)
+	if err := json.Unmarshal(data, manifest_file); err != nil {
+		panic(err)
+	}
+
+	if len(manifest_file.Executions_) == 0 {
+		err := errors.New("manifest has no execution")
+		panic(err)
+	}
+
+	this.dirpath = filepath.Dir(path)
+	this.executions = manifest_file.Executions_
+
+	for execution := range this.executions {
+		this.Validate(execution)
+	}
+}
+
+// Params declares no parameter, since the manifest names all the data.
+func (this *Manifest) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("manifest")
+	return params
+}
+
+// Validate checks that every DPU of an execution transfers the same host symbols and MRAM region
+// with the same sizes, since a rank-parallel transfer moves one buffer size to all its DPUs.
+func (this *Manifest) Validate(execution int) {
+	has_default := false
+	dpu_ids := make(map[int]bool, 0)
+	for _, manifest_dpu := range this.executions[execution].Dpus_ {
+		if manifest_dpu.DpuId_ == nil {
+			if has_default {
+				err_msg := fmt.Sprintf(
+					"execution %d has more than one entry without a DPU ID",
+					execution,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			has_default = true
+		} else {
+			dpu_id := *manifest_dpu.DpuId_
+
+			if dpu_id < 0 || dpu_id >= this.num_dpus {
+				err_msg := fmt.Sprintf(
+					"execution %d has an entry for DPU %d out of %d DPUs",
+					execution,
+					dpu_id,
+					this.num_dpus,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			} else if dpu_ids[dpu_id] {
+				err_msg := fmt.Sprintf(
+					"execution %d has more than one entry for DPU %d",
+					execution,
+					dpu_id,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			dpu_ids[dpu_id] = true
+		}
+	}
+
+	signature := ""
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		dpu_signature := this.Signature(execution, dpu_id)
+
+		if dpu_id == 0 {
+			signature = dpu_signature
+		} else if dpu_signature != signature {
+			err_msg := fmt.Sprintf(
+				"execution %d transfers %s to DPU %d but %s to DPU 0",
+				execution,
+				dpu_signature,
+				dpu_id,
+				signature,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+}
+
+// Signature describes the symbols and MRAM regions a DPU transfers in an execution with their
+// sizes. The sizes are taken from the file system, so that the data files are only read once, when
+// the assembler writes them.
+func (this *Manifest) Signature(execution int, dpu_id int) string {
+	words := make([]string, 0)
+
+	manifest_dpu := this.FindManifestDpu(execution, dpu_id)
+
+	for _, direction := range []string{"input", "output"} {
+		var files map[string]string
+		var regions []ManifestRegion
+		if direction == "input" {
+			files = manifest_dpu.Host_
+			regions = manifest_dpu.Mram_
+		} else {
+			files = manifest_dpu.ExpectedHost_
+			if manifest_dpu.ExpectedMram_ != nil {
+				regions = []ManifestRegion{*manifest_dpu.ExpectedMram_}
+			}
+		}
+
+		for name, file := range files {
+			words = append(words, fmt.Sprintf(
+				"%s %s (%d bytes)",
+				direction,
+				name,
+				this.FileSize(execution, dpu_id, file),
+			))
+		}
+
+		if len(regions) > 0 {
+			offset, size := this.MramSpan(execution, dpu_id, regions)
+			words = append(words, fmt.Sprintf(
+				"%s MRAM heap offset %d (%d bytes)",
+				direction,
+				offset,
+				size,
+			))
+		}
+	}
+
+	slices.Sort(words)
+
+	if len(words) == 0 {
+		return "nothing"
+	}
+
+	return strings.Join(words, ", ")
+}
+
+// MramSpan returns the lowest offset of the MRAM regions and the size of the byte stream that
+// merges them.
+func (this *Manifest) MramSpan(
+	execution int,
+	dpu_id int,
+	regions []ManifestRegion,
+) (int64, int64) {
+	begin := int64(-1)
+	end := int64(0)
+	for _, region := range regions {
+		if begin == -1 || region.Offset_ < begin {
+			begin = region.Offset_
+		}
+
+		end = max(end, region.Offset_+this.FileSize(execution, dpu_id, region.File_))
+	}
+
+	return begin, end - begin
+}
+
+func (this *Manifest) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	return this.ReadSymbols(execution, dpu_id, this.FindManifestDpu(execution, dpu_id).Host_)
+}
+
+func (this *Manifest) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	return this.ReadSymbols(execution, dpu_id, this.FindManifestDpu(execution, dpu_id).ExpectedHost_)
+}
+
+// InputDpuMramHeapPointerName merges the MRAM regions of a DPU into a single byte stream starting
+// at the lowest offset. The gaps between regions are filled with zeros.
+func (this *Manifest) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	regions := slices.Clone(this.FindManifestDpu(execution, dpu_id).Mram_)
+
+	slices.SortFunc(regions, func(x ManifestRegion, y ManifestRegion) int {
+		return int(x.Offset_ - y.Offset_)
+	})
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	if len(regions) == 0 {
+		return 0, byte_stream
+	}
+
+	offset := regions[0].Offset_
+	for _, region := range regions {
+		if region.Offset_ < 0 {
+			err_msg := fmt.Sprintf("MRAM heap offset %d < 0", region.Offset_)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if region.Offset_ < offset+byte_stream.Size() {
+			err_msg := fmt.Sprintf(
+				"MRAM region at offset %d overlaps the previous region",
+				region.Offset_,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		for byte_stream.Size() < region.Offset_-offset {
+			byte_stream.Append(0)
+		}
+
+		byte_stream.Merge(this.ReadFile(execution, dpu_id, region.File_))
+	}
+
+	return offset, byte_stream
+}
+
+func (this *Manifest) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	region := this.FindManifestDpu(execution, dpu_id).ExpectedMram_
+
+	if region == nil {
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+
+		return 0, byte_stream
+	}
+
+	if region.Offset_ < 0 {
+		err_msg := fmt.Sprintf("MRAM heap offset %d < 0", region.Offset_)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return region.Offset_, this.ReadFile(execution, dpu_id, region.File_)
+}
+
+func (this *Manifest) NumExecutions() int {
+	return len(this.executions)
+}
+
+// FindManifestDpu returns the entry of a DPU in an execution, the entry without a DPU ID if it has
+// none, or an empty entry.
+func (this *Manifest) FindManifestDpu(execution int, dpu_id int) *ManifestDpu {
+	if execution >= len(this.executions) {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	var default_manifest_dpu *ManifestDpu
+	for i, manifest_dpu := range this.executions[execution].Dpus_ {
+		if manifest_dpu.DpuId_ == nil {
+			default_manifest_dpu = &this.executions[execution].Dpus_[i]
+		} else if *manifest_dpu.DpuId_ == dpu_id {
+			return &this.executions[execution].Dpus_[i]
+		}
+	}
+
+	if default_manifest_dpu != nil {
+		return default_manifest_dpu
+	}
+
+	return new(ManifestDpu)
+}
+
+func (this *Manifest) ReadSymbols(
+	execution int,
+	dpu_id int,
+	files map[string]string,
+) map[string]*encoding.ByteStream {
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+
+	for name, file := range files {
+		dpu_host[name] = this.ReadFile(execution, dpu_id, file)
+	}
+
+	return dpu_host
+}
+
+// FilePath returns the path of a file of a DPU in an execution.
+func (this *Manifest) FilePath(execution int, dpu_id int, file string) string {
+	file = strings.ReplaceAll(file, "{execution}", strconv.Itoa(execution))
+	file = strings.ReplaceAll(file, "{dpu}", strconv.Itoa(dpu_id))
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(this.dirpath, file)
+	}
+
+	return file
+}
+
+func (this *Manifest) FileSize(execution int, dpu_id int, file string) int64 {
+	file_info, err := os.Stat(this.FilePath(execution, dpu_id, file))
+	if err != nil {
+		panic(err)
+	}
+
+	return file_info.Size()
+}
+
+func (this *Manifest) ReadFile(execution int, dpu_id int, file string) *encoding.ByteStream {
+	data, err := os.ReadFile(this.FilePath(execution, dpu_id, file))
+	if err != nil {
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, value := range data {
+		byte_stream.Append(value)
+	}
+
+	return byte_stream
+}
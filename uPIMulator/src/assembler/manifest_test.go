@@ -0,0 +1,232 @@
+package assembler_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/assembler"
+	"uPIMulator/src/misc"
+)
+
+// NewManifest writes the manifest and its data files to a temporary directory and loads it for two
+// DPUs.
+func NewManifest(t *testing.T, manifest_json string, files map[string][]byte) *assembler.Manifest {
+	dirpath := t.TempDir()
+
+	path := filepath.Join(dirpath, "manifest.json")
+	if err := os.WriteFile(path, []byte(manifest_json), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dirpath, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.INT, "num_channels", "1", "number of channels")
+	command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "number of ranks")
+	command_line_parser.AddOption(misc.INT, "num_dpus_per_rank", "2", "number of DPUs")
+	command_line_parser.AddOption(misc.STRING, "data_prep_params", "", "benchmark parameters")
+	command_line_parser.AddOption(misc.STRING, "manifest", path, "manifest")
+
+	manifest := new(assembler.Manifest)
+	manifest.Init(command_line_parser)
+	return manifest
+}
+
+func Bytes(byte_stream *encoding.ByteStream) []byte {
+	bytes := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		bytes = append(bytes, byte_stream.Get(int(i)))
+	}
+
+	return bytes
+}
+
+func TestManifest(t *testing.T) {
+	manifest_json := `{"executions": [
+		{"dpus": [
+			{
+				"host": {"DPU_INPUT_ARGUMENTS": "args.bin"},
+				"mram": [
+					{"offset": 4, "file": "b_{dpu}.bin"}, {"offset": 0, "file": "a_{dpu}.bin"}
+				],
+				"expected_host": {"DPU_RESULTS": "results_{execution}_{dpu}.bin"},
+				"expected_mram": {"offset": 8, "file": "c_{dpu}.bin"}
+			},
+			{"dpu": 1, "host": {"DPU_INPUT_ARGUMENTS": "args_1.bin"}, "mram": [
+				{"offset": 0, "file": "a_{dpu}.bin"}, {"offset": 4, "file": "b_{dpu}.bin"}
+			], "expected_host": {"DPU_RESULTS": "results_{execution}_{dpu}.bin"},
+			"expected_mram": {"offset": 8, "file": "c_{dpu}.bin"}}
+		]},
+		{"dpus": [{}]}
+	]}`
+
+	files := map[string][]byte{
+		"args.bin":        {1, 0, 0, 0},
+		"args_1.bin":      {2, 0, 0, 0},
+		"a_0.bin":         {10, 11},
+		"a_1.bin":         {20, 21},
+		"b_0.bin":         {12, 13},
+		"b_1.bin":         {22, 23},
+		"results_0_0.bin": {30},
+		"results_0_1.bin": {40},
+		"c_0.bin":         {50, 51},
+		"c_1.bin":         {60, 61},
+	}
+
+	manifest := NewManifest(t, manifest_json, files)
+
+	if manifest.NumExecutions() != 2 {
+		t.Fatalf("NumExecutions() = %d, want 2", manifest.NumExecutions())
+	}
+
+	tests := []struct {
+		name         string
+		dpu_id       int
+		want_args    []byte
+		want_results []byte
+		want_mram    []byte
+		want_output  []byte
+	}{
+		{
+			"entry without a DPU ID",
+			0,
+			[]byte{1, 0, 0, 0},
+			[]byte{30},
+			[]byte{10, 11, 0, 0, 12, 13},
+			[]byte{50, 51},
+		},
+		{
+			"entry of the DPU",
+			1,
+			[]byte{2, 0, 0, 0},
+			[]byte{40},
+			[]byte{20, 21, 0, 0, 22, 23},
+			[]byte{60, 61},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := Bytes(manifest.InputDpuHost(0, test.dpu_id)["DPU_INPUT_ARGUMENTS"])
+			if !slices.Equal(args, test.want_args) {
+				t.Errorf("DPU_INPUT_ARGUMENTS = %v, want %v", args, test.want_args)
+			}
+
+			results := Bytes(manifest.OutputDpuHost(0, test.dpu_id)["DPU_RESULTS"])
+			if !slices.Equal(results, test.want_results) {
+				t.Errorf("DPU_RESULTS = %v, want %v", results, test.want_results)
+			}
+
+			offset, mram := manifest.InputDpuMramHeapPointerName(0, test.dpu_id)
+			if offset != 0 || !slices.Equal(Bytes(mram), test.want_mram) {
+				t.Errorf("MRAM heap = %d %v, want 0 %v", offset, Bytes(mram), test.want_mram)
+			}
+
+			offset, output := manifest.OutputDpuMramHeapPointerName(0, test.dpu_id)
+			if offset != 8 || !slices.Equal(Bytes(output), test.want_output) {
+				t.Errorf(
+					"expected MRAM = %d %v, want 8 %v",
+					offset,
+					Bytes(output),
+					test.want_output,
+				)
+			}
+
+			if len(manifest.InputDpuHost(1, test.dpu_id)) != 0 {
+				t.Errorf("execution 1 transfers %v", manifest.InputDpuHost(1, test.dpu_id))
+			}
+		})
+	}
+}
+
+func TestManifestInit(t *testing.T) {
+	files := map[string][]byte{"a.bin": {1, 2}, "b.bin": {3, 4}, "c.bin": {5, 6, 7}}
+
+	tests := []struct {
+		name       string
+		manifest   string
+		want_panic bool
+	}{
+		{"valid", `{"executions": [{"dpus": [{"host": {"A": "a.bin"}}]}]}`, false},
+		{
+			"same sizes for every DPU",
+			`{"executions": [{"dpus": [{"host": {"A": "a.bin"}}, ` +
+				`{"dpu": 1, "host": {"A": "b.bin"}}]}]}`,
+			false,
+		},
+		{"malformed JSON", `{"executions": [`, true},
+		{"no execution", `{"executions": []}`, true},
+		{"two entries without a DPU ID", `{"executions": [{"dpus": [{}, {}]}]}`, true},
+		{"DPU out of range", `{"executions": [{"dpus": [{"dpu": 2}]}]}`, true},
+		{"negative DPU", `{"executions": [{"dpus": [{"dpu": -1}]}]}`, true},
+		{"DPU twice", `{"executions": [{"dpus": [{"dpu": 0}, {"dpu": 0}]}]}`, true},
+		{
+			"different sizes",
+			`{"executions": [{"dpus": [{"host": {"A": "a.bin"}}, ` +
+				`{"dpu": 1, "host": {"A": "c.bin"}}]}]}`,
+			true,
+		},
+		{
+			"different symbols",
+			`{"executions": [{"dpus": [{"host": {"A": "a.bin"}}, ` +
+				`{"dpu": 1, "host": {"B": "a.bin"}}]}]}`,
+			true,
+		},
+		{"missing file", `{"executions": [{"dpus": [{"host": {"A": "d.bin"}}]}]}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			NewManifest(t, test.manifest, files)
+		})
+	}
+}
+
+func TestManifestMram(t *testing.T) {
+	files := map[string][]byte{"a.bin": {1, 2, 3, 4}}
+
+	tests := []struct {
+		name       string
+		mram       string
+		want_panic bool
+	}{
+		{
+			"adjacent regions",
+			`[{"offset": 0, "file": "a.bin"}, {"offset": 4, "file": "a.bin"}]`,
+			false,
+		},
+		{
+			"overlapping regions",
+			`[{"offset": 0, "file": "a.bin"}, {"offset": 2, "file": "a.bin"}]`,
+			true,
+		},
+		{"negative offset", `[{"offset": -4, "file": "a.bin"}]`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			manifest_json := `{"executions": [{"dpus": [{"mram": ` + test.mram + `}]}]}`
+			manifest := NewManifest(t, manifest_json, files)
+			manifest.InputDpuMramHeapPointerName(0, 0)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package prim
+
+import (
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// ArithmeticThroughput prepares the data of the PrIM Arithmetic-Throughput microbenchmark. Each
+// DPU receives its elements A at the start of its MRAM heap and writes B = A op n after them, where
+// n is the number of elements per DPU. OP (ADD, SUB, MUL, DIV) and TYPE (INT32, UINT32, INT64,
+// UINT64, FLOAT, DOUBLE) are its knobs.
+type ArithmeticThroughput struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	buffer_a []int64
+	buffer_b []int64
+}
+
+func (this *ArithmeticThroughput) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"Arithmetic-Throughput",
+		map[string]string{"BL": "10", "OP": "ADD", "TYPE": "INT32"},
+		this.Params(),
+	)
+
+	op := this.microbenchmark.CheckedDefine("OP", []string{"ADD", "SUB", "MUL", "DIV"})
+
+	this.element_type = new(ElementType)
+	this.element_type.Init(
+		this.microbenchmark.CheckedDefine(
+			"TYPE",
+			[]string{"INT32", "UINT32", "INT64", "UINT64", "FLOAT", "DOUBLE"},
+		),
+	)
+
+	this.microbenchmark.CheckAlignment(this.element_type.Size())
+
+	this.num_executions = 1
+
+	scalar := this.element_type.FromInt64(this.microbenchmark.InputSizeDpu())
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_b = make([]int64, 0)
+	for _, a := range this.buffer_a {
+		this.buffer_b = append(this.buffer_b, this.element_type.Apply(op, a, scalar))
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *ArithmeticThroughput) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu() * this.element_type.Size(), 0},
+	)
+
+	return dpu_host
+}
+
+func (this *ArithmeticThroughput) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *ArithmeticThroughput) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+}
+
+func (this *ArithmeticThroughput) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * this.element_type.Size()
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id)
+}
+
+func (this *ArithmeticThroughput) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *ArithmeticThroughput) Params() *misc.BenchmarkParams {
+	return microbenchmarkParams("Arithmetic-Throughput")
+}
+
+func (this *ArithmeticThroughput) NumExecutions() int {
+	return this.num_executions
+}
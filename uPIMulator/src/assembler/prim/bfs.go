@@ -0,0 +1,531 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/misc"
+)
+
+// Bfs prepares the data of the PrIM BFS benchmark. Each DPU owns a contiguous range of the nodes
+// of a CSR graph. Every execution processes one BFS level: the host sends the union of the next
+// frontiers of all DPUs back to every DPU, and the DPUs mark the frontier visited and compute
+// their own next frontier. The executions stop at the first level whose next frontier is empty,
+// after which the host reads the node levels back.
+//
+// The graph is read from --dataset, or generated at random with as many nodes as the nodes
+// parameter if no dataset is given. The search starts from node 0.
+type Bfs struct {
+	num_dpus       int
+	num_tasklets   int
+	num_executions int
+
+	num_nodes       int64
+	node_ptrs       []int64
+	neighbor_idxs   []int64
+	num_nodes_dpu   int64
+	frontiers       [][]uint64
+	next_frontiers  [][][]uint64
+	node_levels     []int64
+	next_frontier_m int64
+	node_level_m    int64
+	node_ptrs_m     int64
+	neighbor_idxs_m int64
+	visited_m       int64
+	current_m       int64
+
+	input_generator *InputGenerator
+}
+
+func (this *Bfs) Init(command_line_parser *misc.CommandLineParser) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
+
+	// NOTE: the seed only draws the random graph when no dataset is given
+	this.input_generator = new(InputGenerator)
+	this.input_generator.Init(command_line_parser)
+	this.input_generator.CheckDefault("BFS")
+
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "BFS")
+
+	var node_idxs []int64
+	if dataset := command_line_parser.StringParameter("dataset"); dataset != "" {
+		node_idxs = this.ReadGraph(dataset)
+	} else {
+		node_idxs = this.GenerateGraph(params.IntParameter("nodes"))
+	}
+
+	// NOTE: frontiers and visited lists are bit vectors of 64-node tiles
+	this.num_nodes = this.RoundUp(this.num_nodes, 64)
+
+	this.Coo2Csr(node_idxs)
+
+	this.num_nodes_dpu = this.RoundUp((this.num_nodes-1)/int64(this.num_dpus)+1, 64)
+
+	this.Search()
+
+	this.num_executions = len(this.frontiers)
+
+	// NOTE: a rank-parallel transfer moves the same number of bytes to the same offset of every
+	// DPU, so every region is sized for the DPU with the most nodes and neighbors. The next
+	// frontier follows the parameters, so that the host sends both in a single transfer at every
+	// level, and the node levels follow the next frontier, so that the host reads both back in a
+	// single transfer after the last level.
+	max_num_neighbors := int64(0)
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		start_node, num_nodes := this.DpuNodes(dpu_id)
+
+		if num_nodes > 0 {
+			num_neighbors := this.node_ptrs[start_node+num_nodes] - this.node_ptrs[start_node]
+			max_num_neighbors = max(max_num_neighbors, num_neighbors)
+		}
+	}
+
+	this.next_frontier_m = this.RoundUp(11*4, 8)
+	this.node_level_m = this.next_frontier_m + this.num_nodes/8
+	this.node_ptrs_m = this.node_level_m + this.num_nodes_dpu*4
+	this.neighbor_idxs_m = this.node_ptrs_m + this.RoundUp((this.num_nodes_dpu+1)*4, 8)
+	this.visited_m = this.neighbor_idxs_m + this.RoundUp(max_num_neighbors*4, 8)
+	this.current_m = this.visited_m + this.num_nodes/8
+}
+
+// ReadGraph reads a graph as one edge per line and returns the source node of each edge. Lines
+// starting with # or % are comments, and the columns after the second (e.g., weights) are ignored.
+// The number of nodes is declared by the header of one of two formats:
+//
+//	Matrix Market: a %%MatrixMarket banner, then a "rows columns entries" line; indices start at 1
+//	PrIM:          a "rows columns edges" first line, then exactly that many two-column edges
+//
+// Otherwise, the number of nodes is the largest node index plus 1, as in SNAP edge lists such as
+// loc-gowalla_edges.txt, whether or not they carry a third column.
+func (this *Bfs) ReadGraph(path string) []int64 {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	lines := file_scanner.ReadLines()
+
+	is_matrix_market := len(lines) > 0 && strings.HasPrefix(lines[0], "%%MatrixMarket")
+
+	rows := make([][]string, 0)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "%") {
+			continue
+		} else if len(fields) < 2 {
+			err_msg := fmt.Sprintf("%s has a malformed line (%s)", path, line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		rows = append(rows, fields)
+	}
+
+	if len(rows) == 0 {
+		err_msg := fmt.Sprintf("%s has no edge", path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	// NOTE: a weighted edge list also has three numbers on its first line, so a PrIM header is
+	// only recognized when every other line is a two-column edge and their count matches it
+	has_header := is_matrix_market
+	if !has_header && len(rows[0]) == 3 {
+		num_edges, err := strconv.ParseInt(rows[0][2], 10, 64)
+
+		has_header = err == nil && num_edges == int64(len(rows)-1)
+		for _, row := range rows[1:] {
+			if len(row) != 2 {
+				has_header = false
+				break
+			}
+		}
+	}
+
+	base := int64(0)
+	if is_matrix_market {
+		base = 1
+	}
+
+	this.num_nodes = 0
+	if has_header {
+		if len(rows[0]) != 3 {
+			err_msg := fmt.Sprintf(
+				"%s has a malformed header (%s)",
+				path,
+				strings.Join(rows[0], " "),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.num_nodes = max(this.ParseNumber(path, rows[0][0]), this.ParseNumber(path, rows[0][1]))
+
+		num_edges := this.ParseNumber(path, rows[0][2])
+		rows = rows[1:]
+
+		if int64(len(rows)) != num_edges {
+			err_msg := fmt.Sprintf("%s has %d edges but declares %d", path, len(rows), num_edges)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	node_idxs := make([]int64, 0)
+	this.neighbor_idxs = make([]int64, 0)
+	for _, row := range rows {
+		node_idx := this.ParseNumber(path, row[0]) - base
+		neighbor_idx := this.ParseNumber(path, row[1]) - base
+
+		if node_idx < 0 || neighbor_idx < 0 {
+			err_msg := fmt.Sprintf(
+				"%s has an edge out of bounds (%s)",
+				path,
+				strings.Join(row, " "),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		node_idxs = append(node_idxs, node_idx)
+		this.neighbor_idxs = append(this.neighbor_idxs, neighbor_idx)
+
+		if !has_header {
+			this.num_nodes = max(this.num_nodes, node_idx+1, neighbor_idx+1)
+		} else if node_idx >= this.num_nodes || neighbor_idx >= this.num_nodes {
+			err_msg := fmt.Sprintf(
+				"%s has an edge out of bounds (%s)",
+				path,
+				strings.Join(row, " "),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	if len(node_idxs) == 0 {
+		err_msg := fmt.Sprintf("%s has no edge", path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return node_idxs
+}
+
+// ParseNumber parses a non-negative integer of a graph file.
+func (this *Bfs) ParseNumber(path string, field string) int64 {
+	number, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		panic(err)
+	} else if number < 0 {
+		err_msg := fmt.Sprintf("%s has a negative number (%s)", path, field)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return number
+}
+
+// GenerateGraph generates a graph with 1 to 8 edges per node to random nodes and returns the source
+// node of each edge.
+func (this *Bfs) GenerateGraph(num_nodes int64) []int64 {
+	if num_nodes <= 0 {
+		err := errors.New("num nodes <= 0")
+		panic(err)
+	}
+
+	this.num_nodes = num_nodes
+
+	node_idxs := make([]int64, 0)
+	this.neighbor_idxs = make([]int64, 0)
+	for node := int64(0); node < num_nodes; node++ {
+		num_edges := this.input_generator.Intn(8) + 1
+
+		for i := 0; i < num_edges; i++ {
+			node_idxs = append(node_idxs, node)
+			this.neighbor_idxs = append(this.neighbor_idxs, int64(this.input_generator.Intn(int(num_nodes))))
+		}
+	}
+
+	return node_idxs
+}
+
+// Coo2Csr bins the edges by source node, keeping the order of the edges of a node.
+func (this *Bfs) Coo2Csr(node_idxs []int64) {
+	this.node_ptrs = make([]int64, this.num_nodes+1)
+	for _, node := range node_idxs {
+		this.node_ptrs[node+1]++
+	}
+
+	for node := int64(0); node < this.num_nodes; node++ {
+		this.node_ptrs[node+1] += this.node_ptrs[node]
+	}
+
+	next_idxs := slices.Clone(this.node_ptrs)
+
+	neighbor_idxs := make([]int64, len(this.neighbor_idxs))
+	for i, node := range node_idxs {
+		neighbor_idxs[next_idxs[node]] = this.neighbor_idxs[i]
+
+		next_idxs[node]++
+	}
+
+	this.neighbor_idxs = neighbor_idxs
+}
+
+/*
+Run the search as the DPUs and the host do. For each level, record the frontier the host sends to
+the DPUs and the next frontier each DPU computes from the nodes it owns.
+*/
+func (this *Bfs) Search() {
+	num_tiles := this.num_nodes / 64
+
+	visited := make([]uint64, num_tiles)
+	this.node_levels = make([]int64, this.num_nodes)
+
+	frontier := make([]uint64, num_tiles)
+	frontier[0] = 1
+
+	this.frontiers = make([][]uint64, 0)
+	this.next_frontiers = make([][][]uint64, 0)
+	for level := int64(1); ; level++ {
+		for tile := int64(0); tile < num_tiles; tile++ {
+			visited[tile] |= frontier[tile]
+		}
+
+		for node := int64(0); node < this.num_nodes; node++ {
+			if frontier[node/64]&(1<<(node%64)) != 0 {
+				this.node_levels[node] = level
+			}
+		}
+
+		next_frontiers := make([][]uint64, 0)
+		union := make([]uint64, num_tiles)
+		is_empty := true
+		for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+			next_frontier := make([]uint64, num_tiles)
+
+			start_node, num_nodes := this.DpuNodes(dpu_id)
+			for node := start_node; node < start_node+num_nodes; node++ {
+				if frontier[node/64]&(1<<(node%64)) == 0 {
+					continue
+				}
+
+				for _, neighbor := range this.neighbor_idxs[this.node_ptrs[node]:this.node_ptrs[node+1]] {
+					if visited[neighbor/64]&(1<<(neighbor%64)) == 0 {
+						next_frontier[neighbor/64] |= 1 << (neighbor % 64)
+					}
+				}
+			}
+
+			for tile := int64(0); tile < num_tiles; tile++ {
+				union[tile] |= next_frontier[tile]
+				is_empty = is_empty && union[tile] == 0
+			}
+
+			next_frontiers = append(next_frontiers, next_frontier)
+		}
+
+		this.frontiers = append(this.frontiers, frontier)
+		this.next_frontiers = append(this.next_frontiers, next_frontiers)
+
+		if is_empty {
+			break
+		}
+
+		frontier = union
+	}
+}
+
+// DpuNodes returns the first node and the number of nodes assigned to a DPU.
+func (this *Bfs) DpuNodes(dpu_id int) (int64, int64) {
+	start_node := int64(dpu_id) * this.num_nodes_dpu
+
+	if start_node > this.num_nodes {
+		return start_node, 0
+	} else if start_node+this.num_nodes_dpu > this.num_nodes {
+		return start_node, this.num_nodes - start_node
+	} else {
+		return start_node, this.num_nodes_dpu
+	}
+}
+
+func (this *Bfs) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *Bfs) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+/*
+Generate the MRAM heap of a DPU. The first execution sends the DPUParams struct, the frontier of
+the first level, the node levels, the DPU's node pointers and neighbors, and the visited list.
+Every later execution only sends the DPUParams struct with the new level and the frontier.
+*/
+func (this *Bfs) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	start_node, num_nodes := this.DpuNodes(dpu_id)
+
+	node_ptrs_offset := int64(0)
+	if num_nodes > 0 {
+		node_ptrs_offset = this.node_ptrs[start_node]
+	}
+
+	params := []int64{
+		num_nodes,
+		this.num_nodes,
+		start_node,
+		node_ptrs_offset,
+		int64(execution + 1),
+		this.node_ptrs_m,
+		this.neighbor_idxs_m,
+		this.node_level_m,
+		this.visited_m,
+		this.current_m,
+		this.next_frontier_m,
+	}
+	for _, param := range params {
+		this.AppendWord(byte_stream, 32, param)
+	}
+	this.Pad(byte_stream, this.next_frontier_m)
+
+	for _, tile := range this.frontiers[execution] {
+		this.AppendWord(byte_stream, 64, int64(tile))
+	}
+
+	if execution > 0 {
+		return 0, byte_stream
+	}
+
+	this.Pad(byte_stream, this.node_ptrs_m)
+
+	if num_nodes > 0 {
+		for _, node_ptr := range this.node_ptrs[start_node : start_node+num_nodes+1] {
+			this.AppendWord(byte_stream, 32, node_ptr)
+		}
+
+		this.Pad(byte_stream, this.neighbor_idxs_m)
+
+		for _, neighbor := range this.neighbor_idxs[this.node_ptrs[start_node]:this.node_ptrs[start_node+num_nodes]] {
+			this.AppendWord(byte_stream, 32, neighbor)
+		}
+	}
+
+	this.Pad(byte_stream, this.current_m)
+
+	return 0, byte_stream
+}
+
+/*
+Generate the expected next frontier of a DPU. After the last level, the node levels of the DPU's
+nodes follow it.
+*/
+func (this *Bfs) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	start_node, num_nodes := this.DpuNodes(dpu_id)
+
+	// NOTE: a DPU without nodes leaves the frontier the host has sent untouched
+	next_frontier := this.next_frontiers[execution][dpu_id]
+	if num_nodes == 0 {
+		next_frontier = this.frontiers[execution]
+	}
+
+	for _, tile := range next_frontier {
+		this.AppendWord(byte_stream, 64, int64(tile))
+	}
+
+	if execution == this.num_executions-1 {
+		for i := int64(0); i < this.num_nodes_dpu; i++ {
+			if i < num_nodes {
+				this.AppendWord(byte_stream, 32, this.node_levels[start_node+i])
+			} else {
+				this.AppendWord(byte_stream, 32, 0)
+			}
+		}
+	}
+
+	return this.next_frontier_m, byte_stream
+}
+
+func (this *Bfs) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("BFS")
+	params.AddIntParam("nodes", 8192, 1, "number of nodes of the random graph without a dataset")
+	return params
+}
+
+func (this *Bfs) NumExecutions() int {
+	return this.num_executions
+}
+
+func (this *Bfs) AppendWord(byte_stream *encoding.ByteStream, width int, value int64) {
+	element_word := new(word.Word)
+	element_word.Init(width)
+	element_word.SetValue(value)
+	byte_stream.Merge(element_word.ToByteStream())
+}
+
+// Pad appends zeros to a byte stream until it reaches a size.
+func (this *Bfs) Pad(byte_stream *encoding.ByteStream, size int64) {
+	for byte_stream.Size() < size {
+		byte_stream.Append(0)
+	}
+}
+
+func (this *Bfs) RoundUp(value int64, multiple int64) int64 {
+	return (value + multiple - 1) / multiple * multiple
+}
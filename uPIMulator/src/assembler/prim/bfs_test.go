@@ -0,0 +1,60 @@
+package prim_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"uPIMulator/src/assembler/prim"
+)
+
+func TestBfsReadGraph(t *testing.T) {
+	tests := []struct {
+		name           string
+		graph          string
+		want_panic     bool
+		want_node_idxs []int64
+	}{
+		{
+			"Matrix Market",
+			"%%MatrixMarket matrix coordinate pattern general\n% comment\n4 4 3\n1 2\n2 3\n4 1\n",
+			false,
+			[]int64{0, 1, 3},
+		},
+		{"PrIM", "4 4 3\n0 1\n1 2\n3 0\n", false, []int64{0, 1, 3}},
+		{"SNAP", "# comment\n0 1\n1 2\n3 0\n", false, []int64{0, 1, 3}},
+		{"weighted edge list", "4 4 3\n0 1 7\n1 2 7\n", false, []int64{4, 0, 1}},
+		{"PrIM count mismatch is an edge list", "4 4 5\n0 1\n1 2\n", false, []int64{4, 0, 1}},
+		{"Matrix Market without entries", "%%MatrixMarket matrix\n4 4\n1 2\n", true, nil},
+		{"Matrix Market index 0", "%%MatrixMarket matrix\n4 4 1\n0 1\n", true, nil},
+		{"Matrix Market out of bounds", "%%MatrixMarket matrix\n4 4 1\n5 1\n", true, nil},
+		{"Matrix Market count mismatch", "%%MatrixMarket matrix\n4 4 2\n1 2\n", true, nil},
+		{"PrIM out of bounds", "4 4 1\n0 4\n", true, nil},
+		{"one column", "0 1\n2\n", true, nil},
+		{"not a number", "0 one\n", true, nil},
+		{"negative node", "0 -1\n", true, nil},
+		{"no edge", "# comment\n\n", true, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			path := filepath.Join(t.TempDir(), "graph.txt")
+			if err := os.WriteFile(path, []byte(test.graph), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			bfs := new(prim.Bfs)
+			node_idxs := bfs.ReadGraph(path)
+
+			if !slices.Equal(node_idxs, test.want_node_idxs) {
+				t.Errorf("ReadGraph() = %v, want %v", node_idxs, test.want_node_idxs)
+			}
+		})
+	}
+}
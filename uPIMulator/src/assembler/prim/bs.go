@@ -29,10 +29,20 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	// NOTE: the input of BS must be sorted and its queries are every value in turn
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+	input_generator.CheckDefault("BS")
+
 	this.num_executions = 1
 
-	this.size = int64(command_line_parser.DataPrepParams()[0])
-	num_queries := int64(command_line_parser.DataPrepParams()[0] / 8)
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "BS")
+
+	this.size = params.IntParameter("size")
+	num_queries := this.size / 8
 
 	if num_queries%int64(this.num_dpus*this.num_tasklets) != 0 {
 		num_queries += int64(this.num_dpus*this.num_tasklets) - num_queries%int64(this.num_dpus*this.num_tasklets)
@@ -68,7 +78,8 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 	for i := 0; i < this.num_dpus; i++ {
 		for j := 0; j < this.num_tasklets; j++ {
 			for k := 0; k < int(this.query_per_tasklet); k++ {
-				is_found := false
+				// NOTE: a tasklet writes its result only when it finds a query, so DPU_RESULTS keeps
+				// the position of its last query found, or 0
 				l := int64(0)
 				r := this.size - 1
 
@@ -77,7 +88,6 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 
 					if this.input_buffer[m] == this.query_buffer[k+j*int(this.query_per_tasklet)+i*int(this.slice_per_dpu)] {
 						this.results[i][j] = m
-						is_found = true
 						break
 					}
 
@@ -87,10 +97,6 @@ func (this *Bs) Init(command_line_parser *misc.CommandLineParser) {
 						r = m - 1
 					}
 				}
-
-				if !is_found {
-					this.results[i][j] = -1
-				}
 			}
 		}
 	}
@@ -208,6 +214,17 @@ func (this *Bs) OutputDpuMramHeapPointerName(
 	return 0, byte_stream
 }
 
+func (this *Bs) OutputLayout(name string) (int64, int64, int64) {
+	return 8, 0, 0
+}
+
+func (this *Bs) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("BS")
+	params.AddIntParam("size", 8192, 1, "number of elements, and 8 times the number of queries")
+	return params
+}
+
 func (this *Bs) NumExecutions() int {
 	return this.num_executions
 }
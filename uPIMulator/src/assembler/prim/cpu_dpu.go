@@ -0,0 +1,105 @@
+package prim
+
+import (
+	"errors"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// CpuDpu prepares the data of the PrIM CPU-DPU microbenchmark, which measures the bandwidth of the
+// transfers between the host and the DPUs. Each DPU receives its elements at the start of its MRAM
+// heap and the host reads them back. The DPU kernel is empty.
+//
+// Its knob TRANSFER chooses how the host transfers: PUSH (dpu_push_xfer) and SERIAL (dpu_copy_to
+// for one DPU after another) send each DPU its own elements, and BROADCAST (dpu_broadcast_to)
+// sends every DPU the elements of the first DPU. SERIAL needs host_transfer_mode serial, and the
+// host detects a broadcast from the identical buffers.
+type CpuDpu struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	transfer string
+	buffer_a []int64
+}
+
+func (this *CpuDpu) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"CPU-DPU",
+		map[string]string{"BL": "8", "TRANSFER": "PUSH"},
+		this.Params(),
+	)
+
+	this.transfer = this.microbenchmark.CheckedDefine(
+		"TRANSFER",
+		[]string{"PUSH", "SERIAL", "BROADCAST"},
+	)
+
+	host_transfer_mode := command_line_parser.StringParameter("host_transfer_mode")
+	if (this.transfer == "SERIAL") != (host_transfer_mode == "serial") {
+		err := errors.New("TRANSFER=SERIAL goes with host_transfer_mode serial and only with it")
+		panic(err)
+	}
+
+	this.element_type = new(ElementType)
+	this.element_type.Init("UINT64")
+
+	this.num_executions = 1
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *CpuDpu) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *CpuDpu) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *CpuDpu) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, this.SourceDpu(dpu_id))
+}
+
+func (this *CpuDpu) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, this.SourceDpu(dpu_id))
+}
+
+func (this *CpuDpu) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *CpuDpu) Params() *misc.BenchmarkParams {
+	return microbenchmarkParams("CPU-DPU")
+}
+
+func (this *CpuDpu) NumExecutions() int {
+	return this.num_executions
+}
+
+// SourceDpu returns the DPU whose elements a DPU receives.
+func (this *CpuDpu) SourceDpu(dpu_id int) int {
+	if this.transfer == "BROADCAST" {
+		return 0
+	}
+
+	return dpu_id
+}
@@ -0,0 +1,232 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// ElementType is the C type T of the elements of a benchmark, named like the TYPE knob of the PrIM
+// Makefiles. A value of any type is kept in an int64 as the bits of its C representation, sign
+// extended for the signed integers, so that the arithmetic wraps and rounds as it does on a DPU.
+//
+// NOTE: char is signed, as it is for the DPU toolchain
+type ElementType struct {
+	name string
+}
+
+func (this *ElementType) Init(name string) {
+	switch name {
+	case "CHAR", "SHORT", "INT32", "UINT32", "INT64", "UINT64", "FLOAT", "DOUBLE":
+		this.name = name
+	default:
+		err_msg := fmt.Sprintf("element type (%s) is not supported", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// DefinedElementType returns the type given by the TYPE knob of benchmark_defines, or default_name,
+// which must be the default of TYPE of the DPU program of the benchmark.
+func DefinedElementType(
+	command_line_parser *misc.CommandLineParser,
+	default_name string,
+) *ElementType {
+	name := default_name
+	if value, found := command_line_parser.BenchmarkDefines()["TYPE"]; found {
+		name = value
+	}
+
+	switch name {
+	case "INT32", "UINT32", "INT64", "UINT64", "FLOAT", "DOUBLE":
+	default:
+		err_msg := fmt.Sprintf(
+			"TYPE=%s is not one of INT32, UINT32, INT64, UINT64, FLOAT, DOUBLE",
+			name,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	element_type := new(ElementType)
+	element_type.Init(name)
+	return element_type
+}
+
+// RejectElementType panics if the TYPE knob is given to a benchmark whose DPU program and data
+// have a fixed element type, since the knob would change the DPU program but not the data.
+func RejectElementType(command_line_parser *misc.CommandLineParser, benchmark string) {
+	if value, found := command_line_parser.BenchmarkDefines()["TYPE"]; found {
+		err_msg := fmt.Sprintf(
+			"%s does not support TYPE=%s; only VA, VAS, RED, SCAN-SSA, SCAN-RSS and the "+
+				"microbenchmarks take TYPE",
+			benchmark,
+			value,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *ElementType) Name() string {
+	return this.name
+}
+
+func (this *ElementType) Size() int64 {
+	switch this.name {
+	case "CHAR":
+		return 1
+	case "SHORT":
+		return 2
+	case "INT32", "UINT32", "FLOAT":
+		return 4
+	default:
+		return 8
+	}
+}
+
+// ExactBound lowers the bound of random values so that the sum of num_elements of them is exact
+// in a floating-point type, whatever the order of the additions. Integer sums wrap the same way in
+// any order, so their bound is kept.
+func (this *ElementType) ExactBound(bound int64, num_elements int64) int64 {
+	var mantissa int64
+	switch this.name {
+	case "FLOAT":
+		mantissa = 1 << 24
+	case "DOUBLE":
+		mantissa = 1 << 53
+	default:
+		return bound
+	}
+
+	return max(2, min(bound, mantissa/max(1, num_elements)))
+}
+
+// FromInt64 returns (T)value.
+func (this *ElementType) FromInt64(value int64) int64 {
+	switch this.name {
+	case "CHAR":
+		return int64(int8(value))
+	case "SHORT":
+		return int64(int16(value))
+	case "INT32":
+		return int64(int32(value))
+	case "UINT32":
+		return int64(uint32(value))
+	case "FLOAT":
+		return int64(math.Float32bits(float32(value)))
+	case "DOUBLE":
+		return int64(math.Float64bits(float64(value)))
+	default:
+		return value
+	}
+}
+
+// FromBits returns the value whose C representation has the given low Size() bytes.
+func (this *ElementType) FromBits(bits uint64) int64 {
+	switch this.name {
+	case "FLOAT":
+		return int64(uint32(bits))
+	case "DOUBLE":
+		return int64(bits)
+	default:
+		return this.FromInt64(int64(bits))
+	}
+}
+
+// Apply returns x op y for an op of the PrIM microbenchmarks (ADD, SUB, MUL, DIV).
+func (this *ElementType) Apply(op string, x int64, y int64) int64 {
+	if op == "DIV" && this.name != "FLOAT" && this.name != "DOUBLE" && y == 0 {
+		err := errors.New("integer division by zero")
+		panic(err)
+	}
+
+	switch this.name {
+	case "FLOAT":
+		a := math.Float32frombits(uint32(x))
+		b := math.Float32frombits(uint32(y))
+
+		var c float32
+		switch op {
+		case "ADD":
+			c = a + b
+		case "SUB":
+			c = a - b
+		case "MUL":
+			c = a * b
+		case "DIV":
+			c = a / b
+		default:
+			this.PanicOp(op)
+		}
+		return int64(math.Float32bits(c))
+	case "DOUBLE":
+		a := math.Float64frombits(uint64(x))
+		b := math.Float64frombits(uint64(y))
+
+		var c float64
+		switch op {
+		case "ADD":
+			c = a + b
+		case "SUB":
+			c = a - b
+		case "MUL":
+			c = a * b
+		case "DIV":
+			c = a / b
+		default:
+			this.PanicOp(op)
+		}
+		return int64(math.Float64bits(c))
+	case "UINT64":
+		a := uint64(x)
+		b := uint64(y)
+
+		var c uint64
+		switch op {
+		case "ADD":
+			c = a + b
+		case "SUB":
+			c = a - b
+		case "MUL":
+			c = a * b
+		case "DIV":
+			c = a / b
+		default:
+			this.PanicOp(op)
+		}
+		return int64(c)
+	default:
+		// NOTE: the narrower integers are sign or zero extended, so int64 arithmetic truncated
+		// back to T gives the C result
+		var c int64
+		switch op {
+		case "ADD":
+			c = x + y
+		case "SUB":
+			c = x - y
+		case "MUL":
+			c = x * y
+		case "DIV":
+			c = x / y
+		default:
+			this.PanicOp(op)
+		}
+		return this.FromInt64(c)
+	}
+}
+
+func (this *ElementType) PanicOp(op string) {
+	err_msg := fmt.Sprintf("op (%s) is not supported", op)
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+// Append appends a value to a byte stream in the little-endian layout of the DPU.
+func (this *ElementType) Append(byte_stream *encoding.ByteStream, value int64) {
+	for i := int64(0); i < this.Size(); i++ {
+		byte_stream.Append(uint8(value >> (8 * i)))
+	}
+}
@@ -2,7 +2,6 @@ package prim
 
 import (
 	"errors"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -31,8 +30,13 @@ func (this *Gemv) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
-	this.m_size = int64(command_line_parser.DataPrepParams()[0])
-	this.n_size = 64
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "GEMV")
+
+	this.m_size = params.IntParameter("rows")
+	this.n_size = params.IntParameter("cols")
 
 	this.num_executions = 1
 
@@ -64,19 +68,18 @@ func (this *Gemv) Init(command_line_parser *misc.CommandLineParser) {
 		}
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	element_type := new(ElementType)
+	element_type.Init("UINT32")
+
 	this.buffer_a = make([][]int64, 0)
 	for i := int64(0); i < this.m_size; i++ {
-		this.buffer_a = append(this.buffer_a, make([]int64, 0))
-
-		for j := int64(0); j < this.n_size; j++ {
-			this.buffer_a[i] = append(this.buffer_a[i], int64(rand.Intn(50)))
-		}
+		this.buffer_a = append(this.buffer_a, input_generator.Elements(this.n_size, 50, element_type))
 	}
 
-	this.buffer_b = make([]int64, 0)
-	for i := int64(0); i < this.n_size; i++ {
-		this.buffer_b = append(this.buffer_b, int64(rand.Intn(50)))
-	}
+	this.buffer_b = input_generator.Elements(this.n_size, 50, element_type)
 
 	this.buffer_c = make([][]int64, 0)
 	for i := 0; i < this.num_dpus; i++ {
@@ -217,6 +220,14 @@ func (this *Gemv) OutputDpuMramHeapPointerName(
 	return offset, byte_stream
 }
 
+func (this *Gemv) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("GEMV")
+	params.AddIntParam("rows", 8192, 1, "number of rows of the matrix")
+	params.AddIntParam("cols", 64, 1, "number of columns of the matrix")
+	return params
+}
+
 func (this *Gemv) NumExecutions() int {
 	return this.num_executions
 }
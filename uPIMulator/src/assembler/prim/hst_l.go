@@ -3,7 +3,6 @@ package prim
 import (
 	"errors"
 	"math"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -34,12 +33,23 @@ func (this *HstL) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 1
 
-	size := int64(command_line_parser.DataPrepParams()[0])
-	this.num_bins = 256
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "HST-L")
+
+	size := params.IntParameter("size")
+	this.num_bins = params.IntParameter("bins")
+
+	// NOTE: the DPUs write their histogram to MRAM in one transfer or in 2048-byte chunks
+	if this.num_bins*4 > 2048 && (this.num_bins*4)%2048 != 0 {
+		err := errors.New("histogram is larger than 2048 bytes but not a multiple of 2048 bytes")
+		panic(err)
+	}
 
 	elem_size := int64(4)
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -63,9 +73,19 @@ func (this *HstL) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_8bytes = int64(math.Ceil(float64(input_size_dpu)/float64(8)) * 8)
 	}
 
-	this.buffer_a = make([]int64, 0)
-	for i := int64(0); i < input_size; i++ {
-		this.buffer_a = append(this.buffer_a, int64(rand.Intn(4096)))
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	element_type := new(ElementType)
+	element_type.Init("UINT32")
+
+	// NOTE: the elements are 12-bit pixels, as the kernel scales them to the bins by their depth
+	this.buffer_a = input_generator.Elements(input_size, 4096, element_type)
+	for _, a := range this.buffer_a {
+		if a >= 4096 {
+			err := errors.New("input element is not a 12-bit pixel")
+			panic(err)
+		}
 	}
 
 	depth := int64(12)
@@ -204,6 +224,20 @@ func (this *HstL) OutputDpuMramHeapPointerName(
 	return this.input_size_dpu_8bytes * 4, byte_stream
 }
 
+func (this *HstL) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("HST-L")
+	params.AddIntParam("size", 8192, 1, "number of pixels")
+	params.AddIntParam("bins", 256, 1, "number of bins of the histogram")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
+}
+
 func (this *HstL) NumExecutions() int {
 	return this.num_executions
 }
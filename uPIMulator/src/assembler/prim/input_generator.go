@@ -0,0 +1,140 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"uPIMulator/src/misc"
+)
+
+// InputGenerator draws the input data of a benchmark from a random source seeded with seed, so
+// that two runs with the same options see the same data and the same cycle counts.
+//
+// data_distribution chooses the values of the input arrays of a benchmark:
+//
+//	default: the pattern of the benchmark, which is uniform for the benchmarks with random inputs
+//	uniform: uniform in [0, bound)
+//	zipf:    Zipf-distributed in [0, bound), so that small values are the most frequent
+//	sorted:  uniform in [0, bound), sorted in ascending order
+//	equal:   one uniform value in [0, bound) for every element
+//
+// where bound is the range of the random values of the benchmark. input_file instead reads the
+// input arrays one after the other from a binary file of little-endian elements of the element
+// type of the benchmark, used as they are.
+type InputGenerator struct {
+	random       *rand.Rand
+	distribution string
+
+	input_file string
+	input      []byte
+	offset     int
+}
+
+// NOTE: the exponent of the Zipf distribution, which must be > 1
+const input_generator_zipf_exponent = 1.2
+
+func (this *InputGenerator) Init(command_line_parser *misc.CommandLineParser) {
+	this.random = rand.New(rand.NewSource(command_line_parser.IntParameter("seed")))
+	this.distribution = command_line_parser.StringParameter("data_distribution")
+
+	this.input_file = command_line_parser.StringParameter("input_file")
+	this.offset = 0
+	if this.input_file != "" {
+		input, err := os.ReadFile(this.input_file)
+		if err != nil {
+			panic(err)
+		}
+
+		this.input = input
+	}
+}
+
+// Intn returns a uniform random number in [0, n), whatever the distribution, for the data that
+// gives a benchmark its structure rather than its input values (e.g., the edges of a graph).
+func (this *InputGenerator) Intn(n int) int {
+	return this.random.Intn(n)
+}
+
+// IsDefault returns whether the benchmark generates its input arrays with its own pattern.
+func (this *InputGenerator) IsDefault() bool {
+	return this.distribution == "default" && this.input_file == ""
+}
+
+// CheckDefault panics when data_distribution or input_file is set for a benchmark whose inputs
+// cannot be chosen freely.
+func (this *InputGenerator) CheckDefault(benchmark string) {
+	if !this.IsDefault() {
+		err_msg := fmt.Sprintf("%s does not support data_distribution and input_file", benchmark)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// Elements returns num_elements elements of an input array as values of element_type.
+func (this *InputGenerator) Elements(
+	num_elements int64,
+	bound int64,
+	element_type *ElementType,
+) []int64 {
+	if this.input_file != "" {
+		return this.ReadElements(num_elements, element_type)
+	}
+
+	elements := make([]int64, 0)
+	switch this.distribution {
+	case "default", "uniform", "sorted":
+		for i := int64(0); i < num_elements; i++ {
+			elements = append(elements, this.random.Int63n(bound))
+		}
+
+		if this.distribution == "sorted" {
+			slices.Sort(elements)
+		}
+	case "zipf":
+		zipf := rand.NewZipf(this.random, input_generator_zipf_exponent, 1, uint64(bound-1))
+		for i := int64(0); i < num_elements; i++ {
+			elements = append(elements, int64(zipf.Uint64()))
+		}
+	case "equal":
+		value := this.random.Int63n(bound)
+		for i := int64(0); i < num_elements; i++ {
+			elements = append(elements, value)
+		}
+	default:
+		err_msg := fmt.Sprintf("data distribution (%s) is not supported", this.distribution)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	for i, element := range elements {
+		elements[i] = element_type.FromInt64(element)
+	}
+
+	return elements
+}
+
+// ReadElements reads the next num_elements elements of input_file.
+func (this *InputGenerator) ReadElements(num_elements int64, element_type *ElementType) []int64 {
+	size := int(element_type.Size())
+	if this.offset+int(num_elements)*size > len(this.input) {
+		fmt.Printf("%s has %d bytes\n", this.input_file, len(this.input))
+
+		err := errors.New("input file is too small")
+		panic(err)
+	}
+
+	elements := make([]int64, 0)
+	for i := int64(0); i < num_elements; i++ {
+		bits := uint64(0)
+		for j := 0; j < size; j++ {
+			bits |= uint64(this.input[this.offset+j]) << (8 * j)
+		}
+		this.offset += size
+
+		elements = append(elements, element_type.FromBits(bits))
+	}
+
+	return elements
+}
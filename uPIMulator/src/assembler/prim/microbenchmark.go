@@ -0,0 +1,209 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// Microbenchmark holds what the ports of the PrIM microbenchmarks share.
+//
+// Their compile-time knobs are the -D options of the PrIM Makefiles, given with benchmark_defines
+// and defaulting like the Makefiles and the dpu/CMakeLists.txt of the benchmarks. The runtime
+// parameters are declared by Params() of each microbenchmark: size is the number of elements per DPU
+// like the -i option of the PrIM hosts, which default to weak scaling, and the others are
+// benchmark-specific.
+type Microbenchmark struct {
+	name         string
+	num_dpus     int
+	num_tasklets int
+
+	defines map[string]string
+	params  *misc.BenchmarkParams
+
+	block_size     int64
+	input_size_dpu int64
+
+	input_generator *InputGenerator
+}
+
+func (this *Microbenchmark) Init(
+	command_line_parser *misc.CommandLineParser,
+	name string,
+	default_defines map[string]string,
+	params *misc.BenchmarkParams,
+) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.name = name
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
+
+	this.defines = make(map[string]string, 0)
+	for define_name, value := range default_defines {
+		this.defines[define_name] = value
+	}
+
+	for define_name, value := range command_line_parser.BenchmarkDefines() {
+		if _, found := this.defines[define_name]; !found {
+			err_msg := fmt.Sprintf("%s has no knob %s", name, define_name)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.defines[define_name] = value
+	}
+
+	bl, err := strconv.Atoi(this.Define("BL"))
+	if err != nil {
+		panic(err)
+	} else if bl < 3 || bl > 11 {
+		err := errors.New("BL is not between 3 (8 bytes) and 11 (2048 bytes)")
+		panic(err)
+	}
+	this.block_size = int64(1) << bl
+
+	this.params = params
+	this.params.Parse(command_line_parser)
+
+	this.input_size_dpu = this.params.IntParameter("size")
+
+	this.input_generator = new(InputGenerator)
+	this.input_generator.Init(command_line_parser)
+}
+
+func (this *Microbenchmark) NumDpus() int {
+	return this.num_dpus
+}
+
+func (this *Microbenchmark) NumTasklets() int {
+	return this.num_tasklets
+}
+
+func (this *Microbenchmark) BlockSize() int64 {
+	return this.block_size
+}
+
+func (this *Microbenchmark) InputSizeDpu() int64 {
+	return this.input_size_dpu
+}
+
+func (this *Microbenchmark) Define(name string) string {
+	if _, found := this.defines[name]; !found {
+		err_msg := fmt.Sprintf("%s has no knob %s", this.name, name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.defines[name]
+}
+
+// CheckedDefine returns a knob whose value must be one of values.
+func (this *Microbenchmark) CheckedDefine(name string, values []string) string {
+	value := this.Define(name)
+
+	if !slices.Contains(values, value) {
+		err_msg := fmt.Sprintf("%s=%s is not one of %v", name, value, values)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return value
+}
+
+func (this *Microbenchmark) Param(name string) int64 {
+	return this.params.IntParameter(name)
+}
+
+// CheckAlignment checks that the elements of a DPU fill whole 8-byte MRAM words, which the DMA
+// transfers of the host and of the DPUs require.
+func (this *Microbenchmark) CheckAlignment(element_size int64) {
+	if this.input_size_dpu*element_size%8 != 0 {
+		err_msg := fmt.Sprintf(
+			"%d elements of %d bytes are not a multiple of 8 bytes",
+			this.input_size_dpu,
+			element_size,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// PrintHeader prints the parameters the PrIM hosts print before their measurements.
+func (this *Microbenchmark) PrintHeader() {
+	fmt.Printf(
+		"nr_elements\t%d\tNR_TASKLETS\t%d\tBL\t%s\n",
+		this.input_size_dpu*int64(this.num_dpus),
+		this.num_tasklets,
+		this.Define("BL"),
+	)
+}
+
+func (this *Microbenchmark) InputGenerator() *InputGenerator {
+	return this.input_generator
+}
+
+// RandomElements returns (T)rand() for each element of every DPU, or the next elements of
+// input_file.
+func (this *Microbenchmark) RandomElements(element_type *ElementType) []int64 {
+	return this.input_generator.Elements(
+		this.input_size_dpu*int64(this.num_dpus),
+		1<<31,
+		element_type,
+	)
+}
+
+// DpuElements returns the elements of a DPU.
+func (this *Microbenchmark) DpuElements(
+	element_type *ElementType,
+	elements []int64,
+	dpu_id int,
+) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	start_elem := this.input_size_dpu * int64(dpu_id)
+	for i := int64(0); i < this.input_size_dpu; i++ {
+		element_type.Append(byte_stream, elements[start_elem+i])
+	}
+
+	return byte_stream
+}
+
+// Arguments returns a DPU_INPUT_ARGUMENTS made of 32-bit fields.
+func (this *Microbenchmark) Arguments(fields []int64) *encoding.ByteStream {
+	uint32_type := new(ElementType)
+	uint32_type.Init("UINT32")
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, field := range fields {
+		uint32_type.Append(byte_stream, field)
+	}
+
+	return byte_stream
+}
+
+func (this *Microbenchmark) Verify(execution int, num_executions int, dpu_id int) {
+	if execution >= num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+}
+
+// microbenchmarkParams declares the size parameter of a microbenchmark.
+func microbenchmarkParams(name string) *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init(name)
+	params.AddIntParam("size", 8192, 1, "number of elements per DPU")
+	return params
+}
@@ -31,8 +31,18 @@ func (this *Mlp) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
-	this.m_size = int64(command_line_parser.DataPrepParams()[0])
-	this.n_size = int64(command_line_parser.DataPrepParams()[0])
+	// NOTE: the weights and inputs of MLP follow a fixed pattern
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+	input_generator.CheckDefault("MLP")
+
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "MLP")
+
+	this.m_size = params.IntParameter("size")
+	this.n_size = this.m_size
 
 	this.num_layers = 3
 
@@ -258,6 +268,13 @@ func (this *Mlp) OutputDpuMramHeapPointerName(
 	return offset, byte_stream
 }
 
+func (this *Mlp) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("MLP")
+	params.AddIntParam("size", 8192, 1, "number of neurons of each layer")
+	return params
+}
+
 func (this *Mlp) NumExecutions() int {
 	return this.num_executions
 }
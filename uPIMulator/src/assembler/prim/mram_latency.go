@@ -0,0 +1,87 @@
+package prim
+
+import (
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// MramLatency prepares the data of the PrIM MRAM-Latency microbenchmark. Each DPU copies its
+// elements A from the start of its MRAM heap to B after them one BL-sized block at a time and
+// times either the reads or the writes, as its knob OP (READ, WRITE) chooses.
+type MramLatency struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	buffer_a []int64
+}
+
+func (this *MramLatency) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"MRAM-Latency",
+		map[string]string{"BL": "8", "OP": "READ", "MEM": "MRAM"},
+		this.Params(),
+	)
+
+	this.microbenchmark.CheckedDefine("OP", []string{"READ", "WRITE"})
+	this.microbenchmark.CheckedDefine("MEM", []string{"MRAM"})
+
+	this.element_type = new(ElementType)
+	this.element_type.Init("UINT64")
+
+	this.num_executions = 1
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *MramLatency) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu() * this.element_type.Size(), 0},
+	)
+
+	return dpu_host
+}
+
+func (this *MramLatency) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *MramLatency) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+}
+
+func (this *MramLatency) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * this.element_type.Size()
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+}
+
+func (this *MramLatency) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *MramLatency) Params() *misc.BenchmarkParams {
+	return microbenchmarkParams("MRAM-Latency")
+}
+
+func (this *MramLatency) NumExecutions() int {
+	return this.num_executions
+}
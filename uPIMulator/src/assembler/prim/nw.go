@@ -0,0 +1,488 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/misc"
+)
+
+// Nw prepares the data of the PrIM NW (Needleman-Wunsch) benchmark. The score matrix is split into
+// blocks of bl x bl cells, and every execution computes one anti-diagonal of blocks: first the
+// top-left half from the corner block, then the bottom-right half. The blocks of a diagonal are
+// distributed over the DPUs. Each block is sent with its boundary row and column from the score
+// matrix, computed on the DPU and read back into the score matrix by the host.
+//
+// The two sequences are read from --dataset, or generated at random with as many residues as the
+// length parameter if no dataset is given. The sequence length must be a multiple of bl.
+type Nw struct {
+	num_dpus       int
+	num_tasklets   int
+	num_executions int
+
+	bl         int64
+	max_cols   int64
+	num_blocks int64
+	penalty    int64
+	reference  []int64
+	itemsets   [][]int64
+
+	block_indices [][][]int64
+	blocks        [][][]int64
+	outputs       [][][]int64
+
+	input_generator *InputGenerator
+}
+
+// NOTE: must match BL in benchmark/NW/dpu/CMakeLists.txt
+const nw_bl = 64
+
+// NOTE: the residues of the BLOSUM62 matrix in the order of its rows and columns
+const nw_residues = "ARNDCQEGHILKMFPSTWYVBZX*"
+
+var nw_blosum62 = [24][24]int64{
+	{4, -1, -2, -2, 0, -1, -1, 0, -2, -1, -1, -1, -1, -2, -1, 1, 0, -3, -2, 0, -2, -1, 0, -4},
+	{-1, 5, 0, -2, -3, 1, 0, -2, 0, -3, -2, 2, -1, -3, -2, -1, -1, -3, -2, -3, -1, 0, -1, -4},
+	{-2, 0, 6, 1, -3, 0, 0, 0, 1, -3, -3, 0, -2, -3, -2, 1, 0, -4, -2, -3, 3, 0, -1, -4},
+	{-2, -2, 1, 6, -3, 0, 2, -1, -1, -3, -4, -1, -3, -3, -1, 0, -1, -4, -3, -3, 4, 1, -1, -4},
+	{0, -3, -3, -3, 9, -3, -4, -3, -3, -1, -1, -3, -1, -2, -3, -1, -1, -2, -2, -1, -3, -3, -2, -4},
+	{-1, 1, 0, 0, -3, 5, 2, -2, 0, -3, -2, 1, 0, -3, -1, 0, -1, -2, -1, -2, 0, 3, -1, -4},
+	{-1, 0, 0, 2, -4, 2, 5, -2, 0, -3, -3, 1, -2, -3, -1, 0, -1, -3, -2, -2, 1, 4, -1, -4},
+	{0, -2, 0, -1, -3, -2, -2, 6, -2, -4, -4, -2, -3, -3, -2, 0, -2, -2, -3, -3, -1, -2, -1, -4},
+	{-2, 0, 1, -1, -3, 0, 0, -2, 8, -3, -3, -1, -2, -1, -2, -1, -2, -2, 2, -3, 0, 0, -1, -4},
+	{-1, -3, -3, -3, -1, -3, -3, -4, -3, 4, 2, -3, 1, 0, -3, -2, -1, -3, -1, 3, -3, -3, -1, -4},
+	{-1, -2, -3, -4, -1, -2, -3, -4, -3, 2, 4, -2, 2, 0, -3, -2, -1, -2, -1, 1, -4, -3, -1, -4},
+	{-1, 2, 0, -1, -3, 1, 1, -2, -1, -3, -2, 5, -1, -3, -1, 0, -1, -3, -2, -2, 0, 1, -1, -4},
+	{-1, -1, -2, -3, -1, 0, -2, -3, -2, 1, 2, -1, 5, 0, -2, -1, -1, -1, -1, 1, -3, -1, -1, -4},
+	{-2, -3, -3, -3, -2, -3, -3, -3, -1, 0, 0, -3, 0, 6, -4, -2, -2, 1, 3, -1, -3, -3, -1, -4},
+	{-1, -2, -2, -1, -3, -1, -1, -2, -2, -3, -3, -1, -2, -4, 7, -1, -1, -4, -3, -2, -2, -1, -2, -4},
+	{1, -1, 1, 0, -1, 0, 0, 0, -1, -2, -2, 0, -1, -2, -1, 4, 1, -3, -2, -2, 0, 0, 0, -4},
+	{0, -1, 0, -1, -1, -1, -1, -2, -2, -1, -1, -1, -1, -2, -1, 1, 5, -2, -2, 0, -1, -1, 0, -4},
+	{-3, -3, -4, -4, -2, -2, -3, -2, -2, -3, -2, -3, -1, 1, -4, -3, -2, 11, 2, -3, -4, -3, -2, -4},
+	{-2, -2, -2, -3, -2, -1, -2, -3, 2, -1, -1, -2, -1, 3, -3, -2, -2, 2, 7, -1, -3, -2, -1, -4},
+	{0, -3, -3, -3, -1, -2, -2, -3, -3, 3, 1, -2, 1, -1, -2, -2, 0, -3, -1, 4, -3, -2, -1, -4},
+	{-2, -1, 3, 4, -3, 0, 1, -1, 0, -3, -4, 0, -3, -3, -2, 0, -1, -4, -3, -3, 4, 1, -1, -4},
+	{-1, 0, 0, 1, -3, 3, 4, -2, 0, -3, -3, 1, -1, -3, -1, 0, -1, -3, -2, -2, 1, 4, -1, -4},
+	{0, -1, -1, -1, -2, -1, -1, -1, -1, -1, -1, -1, -1, -1, -2, 0, 0, -2, -1, -1, -1, -1, -1, -4},
+	{-4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, -4, 1},
+}
+
+func (this *Nw) Init(command_line_parser *misc.CommandLineParser) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
+
+	// NOTE: the seed only draws the random sequences when no dataset is given
+	this.input_generator = new(InputGenerator)
+	this.input_generator.Init(command_line_parser)
+	this.input_generator.CheckDefault("NW")
+
+	this.bl = nw_bl
+	this.penalty = 1
+
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "NW")
+
+	var sequence_a []int64
+	var sequence_b []int64
+	if dataset := command_line_parser.StringParameter("dataset"); dataset != "" {
+		sequence_a, sequence_b = this.ReadSequences(dataset)
+	} else {
+		sequence_a, sequence_b = this.GenerateSequences(params.IntParameter("length"))
+	}
+
+	max_rows := int64(len(sequence_a)) + 1
+	this.max_cols = int64(len(sequence_b)) + 1
+
+	if max_rows != this.max_cols {
+		err := errors.New("sequences have different lengths")
+		panic(err)
+	} else if (this.max_cols-1)%this.bl != 0 {
+		err_msg := fmt.Sprintf(
+			"sequence length (%d) is not a multiple of %d",
+			this.max_cols-1,
+			this.bl,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.num_blocks = (this.max_cols - 1) / this.bl
+
+	this.reference = make([]int64, 0)
+	for i := int64(0); i < max_rows-1; i++ {
+		for j := int64(0); j < this.max_cols-1; j++ {
+			this.reference = append(this.reference, nw_blosum62[sequence_a[i]][sequence_b[j]])
+		}
+	}
+
+	// NOTE: like the PrIM host, the score matrix has an extra row and column so that the blocks
+	// of the last row and column can be sent bl + 2 columns wide
+	this.itemsets = make([][]int64, 0)
+	for i := int64(0); i <= max_rows; i++ {
+		this.itemsets = append(this.itemsets, make([]int64, this.max_cols+1))
+	}
+
+	for i := int64(1); i < max_rows; i++ {
+		this.itemsets[i][0] = -i * this.penalty
+	}
+	for j := int64(1); j < this.max_cols; j++ {
+		this.itemsets[0][j] = -j * this.penalty
+	}
+
+	this.num_executions = int(2*this.num_blocks - 1)
+
+	this.block_indices = make([][][]int64, 0)
+	this.blocks = make([][][]int64, 0)
+	this.outputs = make([][][]int64, 0)
+	for execution := 0; execution < this.num_executions; execution++ {
+		this.Diagonal(execution)
+	}
+}
+
+// ReadSequences reads two protein sequences, one per line, in the one-letter code of the BLOSUM62
+// matrix. Lines starting with > or # are skipped, so a FASTA file with two single-line records
+// works as well.
+func (this *Nw) ReadSequences(path string) ([]int64, []int64) {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	sequences := make([][]int64, 0)
+	for _, line := range file_scanner.ReadLines() {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, ">") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sequence := make([]int64, 0)
+		for _, residue := range strings.ToUpper(line) {
+			index := strings.IndexRune(nw_residues, residue)
+
+			if index < 0 {
+				err_msg := fmt.Sprintf("%s has an unknown residue (%c)", path, residue)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			sequence = append(sequence, int64(index))
+		}
+
+		sequences = append(sequences, sequence)
+	}
+
+	if len(sequences) != 2 {
+		err_msg := fmt.Sprintf("%s has %d sequences instead of 2", path, len(sequences))
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return sequences[0], sequences[1]
+}
+
+// GenerateSequences generates two random sequences drawn from the first 10 residues, as the PrIM
+// host does.
+func (this *Nw) GenerateSequences(length int64) ([]int64, []int64) {
+	if length <= 0 {
+		err := errors.New("length <= 0")
+		panic(err)
+	}
+
+	sequence_a := make([]int64, 0)
+	sequence_b := make([]int64, 0)
+	for i := int64(0); i < length; i++ {
+		sequence_a = append(sequence_a, int64(this.input_generator.Intn(10)+1))
+	}
+	for i := int64(0); i < length; i++ {
+		sequence_b = append(sequence_b, int64(this.input_generator.Intn(10)+1))
+	}
+
+	return sequence_a, sequence_b
+}
+
+/*
+Distribute the blocks of a diagonal over the DPUs as the PrIM host does, record the blocks the
+host sends from the score matrix, compute them, and write the results back into the score matrix.
+DPUs beyond the number of blocks of the diagonal receive no block.
+*/
+func (this *Nw) Diagonal(execution int) {
+	num_diagonal_blocks := this.num_blocks - int64(execution) + this.num_blocks - 1
+	if int64(execution) < this.num_blocks {
+		num_diagonal_blocks = int64(execution) + 1
+	}
+
+	chunks := num_diagonal_blocks / int64(this.num_dpus)
+	rest_blocks := num_diagonal_blocks % int64(this.num_dpus)
+
+	block_indices := make([][]int64, 0)
+	for dpu_id := int64(0); dpu_id < int64(this.num_dpus); dpu_id++ {
+		num_dpu_blocks := chunks
+		prev_block_index := rest_blocks*(chunks+1) + (dpu_id-rest_blocks)*chunks
+		if dpu_id < rest_blocks {
+			num_dpu_blocks++
+			prev_block_index = dpu_id * (chunks + 1)
+		}
+
+		indices := make([]int64, 0)
+		for i := int64(0); i < num_dpu_blocks; i++ {
+			indices = append(indices, prev_block_index+i)
+		}
+
+		block_indices = append(block_indices, indices)
+	}
+
+	blocks := make([][]int64, 0)
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		for _, index := range block_indices[dpu_id] {
+			b_index_x, b_index_y := this.BlockIndex(execution, index)
+			blocks = append(blocks, this.Block(b_index_x, b_index_y))
+		}
+	}
+
+	// NOTE: the host reads the blocks back only after every DPU has computed its blocks
+	outputs := make([][]int64, 0)
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		for _, index := range block_indices[dpu_id] {
+			b_index_x, b_index_y := this.BlockIndex(execution, index)
+
+			output := this.Compute(blocks[len(outputs)], b_index_x, b_index_y)
+			outputs = append(outputs, output)
+
+			for row := int64(1); row <= this.bl; row++ {
+				for col := int64(0); col < this.bl+2; col++ {
+					this.itemsets[b_index_y*this.bl+row][b_index_x*this.bl+col] = output[row*(this.bl+2)+col]
+				}
+			}
+		}
+	}
+
+	this.block_indices = append(this.block_indices, block_indices)
+	this.blocks = append(this.blocks, blocks)
+	this.outputs = append(this.outputs, outputs)
+}
+
+// Block returns the (bl + 1) x (bl + 2) window of the score matrix whose first row and column
+// are the boundary of a block.
+func (this *Nw) Block(b_index_x int64, b_index_y int64) []int64 {
+	block := make([]int64, 0)
+	for row := int64(0); row <= this.bl; row++ {
+		for col := int64(0); col < this.bl+2; col++ {
+			block = append(block, this.itemsets[b_index_y*this.bl+row][b_index_x*this.bl+col])
+		}
+	}
+
+	return block
+}
+
+/*
+Compute a block as the DPU leaves it in MRAM. The first row is never written. Every other row
+holds its boundary, the bl computed scores, and a zero in the last column, which the DPU kernel
+writes from a part of its WRAM cache that it never fills.
+*/
+func (this *Nw) Compute(block []int64, b_index_x int64, b_index_y int64) []int64 {
+	width := this.bl + 2
+
+	output := make([]int64, len(block))
+	copy(output, block)
+
+	for row := int64(1); row <= this.bl; row++ {
+		for col := int64(1); col <= this.bl; col++ {
+			reference := this.reference[(b_index_y*this.bl+row-1)*(this.max_cols-1)+b_index_x*this.bl+col-1]
+
+			output[row*width+col] = this.Maximum(
+				output[(row-1)*width+col-1]+reference,
+				output[row*width+col-1]-this.penalty,
+				output[(row-1)*width+col]-this.penalty,
+			)
+		}
+
+		output[row*width+this.bl+1] = 0
+	}
+
+	return output
+}
+
+func (this *Nw) Maximum(a int64, b int64, c int64) int64 {
+	return max(a, b, c)
+}
+
+// BlocksPerDpu returns the largest number of blocks a DPU receives in an execution.
+func (this *Nw) BlocksPerDpu(execution int) int64 {
+	return int64(len(this.block_indices[execution][0]))
+}
+
+/*
+Generate the DPU_INPUT_ARGUMENTS of a DPU: its number of blocks, the largest number of blocks of
+a DPU, which locates the reference blocks in MRAM, and the gap penalty.
+*/
+func (this *Nw) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	dpu_input_arguments_byte_stream := new(encoding.ByteStream)
+	dpu_input_arguments_byte_stream.Init()
+
+	arguments := []int64{
+		int64(len(this.block_indices[execution][dpu_id])),
+		this.BlocksPerDpu(execution),
+		this.penalty,
+		0,
+	}
+	for _, argument := range arguments {
+		this.AppendWord(dpu_input_arguments_byte_stream, argument)
+	}
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = dpu_input_arguments_byte_stream
+
+	return dpu_host
+}
+
+func (this *Nw) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+/*
+Generate the MRAM heap of a DPU: the windows of its blocks followed by their reference blocks.
+Like the dummy transfers of the PrIM host, a DPU with fewer blocks than the others receives zeros
+for the missing ones.
+*/
+func (this *Nw) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	first_block := 0
+	for i := 0; i < dpu_id; i++ {
+		first_block += len(this.block_indices[execution][i])
+	}
+	num_dpu_blocks := len(this.block_indices[execution][dpu_id])
+
+	for i := int64(0); i < this.BlocksPerDpu(execution); i++ {
+		for j := int64(0); j < (this.bl+1)*(this.bl+2); j++ {
+			if i < int64(num_dpu_blocks) {
+				this.AppendWord(byte_stream, this.blocks[execution][first_block+int(i)][j])
+			} else {
+				this.AppendWord(byte_stream, 0)
+			}
+		}
+	}
+
+	for i := int64(0); i < this.BlocksPerDpu(execution); i++ {
+		b_index_x, b_index_y := int64(0), int64(0)
+		if i < int64(num_dpu_blocks) {
+			b_index_x, b_index_y = this.BlockIndex(execution, this.block_indices[execution][dpu_id][i])
+		}
+
+		for row := int64(0); row < this.bl; row++ {
+			for col := int64(0); col < this.bl; col++ {
+				if i < int64(num_dpu_blocks) {
+					reference := this.reference[(b_index_y*this.bl+row)*(this.max_cols-1)+b_index_x*this.bl+col]
+					this.AppendWord(byte_stream, reference)
+				} else {
+					this.AppendWord(byte_stream, 0)
+				}
+			}
+		}
+	}
+
+	return 0, byte_stream
+}
+
+/*
+Generate the expected windows of the blocks of a DPU after the execution.
+*/
+func (this *Nw) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	first_block := 0
+	for i := 0; i < dpu_id; i++ {
+		first_block += len(this.block_indices[execution][i])
+	}
+	num_dpu_blocks := len(this.block_indices[execution][dpu_id])
+
+	for i := int64(0); i < this.BlocksPerDpu(execution); i++ {
+		for j := int64(0); j < (this.bl+1)*(this.bl+2); j++ {
+			if i < int64(num_dpu_blocks) {
+				this.AppendWord(byte_stream, this.outputs[execution][first_block+int(i)][j])
+			} else {
+				this.AppendWord(byte_stream, 0)
+			}
+		}
+	}
+
+	return 0, byte_stream
+}
+
+func (this *Nw) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("NW")
+	params.AddIntParam("length", 8192, 1, "length of the random sequences without a dataset")
+	return params
+}
+
+func (this *Nw) NumExecutions() int {
+	return this.num_executions
+}
+
+// BlockIndex returns the block column and row of the index-th block of the diagonal of an
+// execution.
+func (this *Nw) BlockIndex(execution int, index int64) (int64, int64) {
+	if int64(execution) < this.num_blocks {
+		diagonal := int64(execution) + 1
+		return index, diagonal - 1 - index
+	} else {
+		diagonal := int64(execution) - this.num_blocks + 2
+		b_index_x := diagonal - 1 + index
+		return b_index_x, this.num_blocks + diagonal - 2 - b_index_x
+	}
+}
+
+func (this *Nw) AppendWord(byte_stream *encoding.ByteStream, value int64) {
+	element_word := new(word.Word)
+	element_word.Init(32)
+	element_word.SetValue(value)
+	byte_stream.Merge(element_word.ToByteStream())
+}
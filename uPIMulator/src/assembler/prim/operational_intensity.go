@@ -0,0 +1,142 @@
+package prim
+
+import (
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// OperationalIntensity prepares the data of the PrIM Operational-Intensity microbenchmark. Each DPU
+// reads its elements A one BL-sized block at a time, applies op with a scalar repetitions times to
+// every stride-th element of the block and writes the block to B after A. OP (ADD, SUB, MUL, DIV)
+// and TYPE (CHAR, SHORT, INT32, UINT32, INT64, UINT64, FLOAT, DOUBLE) are its knobs.
+//
+// Its parameters repetitions and stride default to 1. The -p option of the PrIM host gives them as
+// one number: p >= 1 means p repetitions and p < 1 means a stride of 1/p.
+type OperationalIntensity struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	repetitions int64
+	stride      int64
+	scalar      int64
+
+	buffer_a []int64
+	buffer_b []int64
+}
+
+// NOTE: the PrIM host uses its number of timed repetitions, 5 by default, as the scalar
+const operational_intensity_scalar = 5
+
+func (this *OperationalIntensity) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"Operational-Intensity",
+		map[string]string{"BL": "10", "OP": "ADD", "TYPE": "INT32"},
+		this.Params(),
+	)
+
+	op := this.microbenchmark.CheckedDefine("OP", []string{"ADD", "SUB", "MUL", "DIV"})
+
+	this.element_type = new(ElementType)
+	this.element_type.Init(
+		this.microbenchmark.CheckedDefine(
+			"TYPE",
+			[]string{"CHAR", "SHORT", "INT32", "UINT32", "INT64", "UINT64", "FLOAT", "DOUBLE"},
+		),
+	)
+
+	this.microbenchmark.CheckAlignment(this.element_type.Size())
+
+	this.repetitions = this.microbenchmark.Param("repetitions")
+	this.stride = this.microbenchmark.Param("stride")
+
+	this.num_executions = 1
+
+	this.scalar = this.element_type.FromInt64(operational_intensity_scalar)
+
+	block_elems := this.microbenchmark.BlockSize() / this.element_type.Size()
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_b = make([]int64, 0)
+	for i, a := range this.buffer_a {
+		b := a
+
+		elem := int64(i) % this.microbenchmark.InputSizeDpu()
+		if (elem%block_elems)%this.stride == 0 {
+			for r := int64(0); r < this.repetitions; r++ {
+				b = this.element_type.Apply(op, b, this.scalar)
+			}
+		}
+
+		this.buffer_b = append(this.buffer_b, b)
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *OperationalIntensity) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_input_arguments := this.microbenchmark.Arguments(
+		[]int64{
+			this.microbenchmark.InputSizeDpu() * this.element_type.Size(),
+			0,
+			this.repetitions,
+			this.stride,
+		},
+	)
+	this.element_type.Append(dpu_input_arguments, this.scalar)
+
+	// NOTE: the struct is as aligned as the widest of its uint32_t fields and the scalar
+	alignment := max(4, this.element_type.Size())
+	for dpu_input_arguments.Size()%alignment != 0 {
+		dpu_input_arguments.Append(0)
+	}
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = dpu_input_arguments
+
+	return dpu_host
+}
+
+func (this *OperationalIntensity) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *OperationalIntensity) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+}
+
+func (this *OperationalIntensity) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * this.element_type.Size()
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id)
+}
+
+func (this *OperationalIntensity) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *OperationalIntensity) Params() *misc.BenchmarkParams {
+	params := microbenchmarkParams("Operational-Intensity")
+	params.AddIntParam("repetitions", 1, 1, "number of times op is applied to each element")
+	params.AddIntParam("stride", 1, 1, "distance between the elements op is applied to")
+	return params
+}
+
+func (this *OperationalIntensity) NumExecutions() int {
+	return this.num_executions
+}
@@ -0,0 +1,179 @@
+package prim
+
+import (
+	"errors"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// RandomGups prepares the data of the PrIM Random-GUPS microbenchmark. Each DPU updates random
+// entries of the table in its MRAM heap 16 times as many times as the table has entries, with the
+// 128 random streams of HPCC RandomAccess. The PrIM kernel stores its index in each updated
+// entry instead of XORing it, so the result does not depend on the order of the tasklets.
+//
+// The number of elements per DPU must be a power of two.
+type RandomGups struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	buffer_a []int64
+	buffer_b []int64
+}
+
+const (
+	random_gups_poly    = 7
+	random_gups_period  = 1317624576693539401
+	random_gups_streams = 128
+)
+
+func (this *RandomGups) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"Random-GUPS",
+		map[string]string{"BL": "8"},
+		this.Params(),
+	)
+
+	input_size_dpu := this.microbenchmark.InputSizeDpu()
+	if input_size_dpu&(input_size_dpu-1) != 0 {
+		err := errors.New("input size per DPU is not a power of two")
+		panic(err)
+	}
+
+	this.element_type = new(ElementType)
+	this.element_type.Init("UINT64")
+
+	this.num_executions = 1
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_b = make([]int64, len(this.buffer_a))
+	copy(this.buffer_b, this.buffer_a)
+
+	// NOTE: every DPU updates the same entries of its table
+	is_updated := make([]bool, input_size_dpu)
+
+	num_updates := 16 * input_size_dpu
+	for j := int64(0); j < random_gups_streams; j++ {
+		ran := this.Starts((num_updates / random_gups_streams) * j)
+
+		for i := int64(0); i < num_updates/random_gups_streams; i++ {
+			ran = this.Next(ran)
+			is_updated[ran&uint64(input_size_dpu-1)] = true
+		}
+	}
+
+	for dpu_id := 0; dpu_id < this.microbenchmark.NumDpus(); dpu_id++ {
+		start_elem := input_size_dpu * int64(dpu_id)
+
+		for index := int64(0); index < input_size_dpu; index++ {
+			if is_updated[index] {
+				this.buffer_b[start_elem+index] = index
+			}
+		}
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+// Next returns the next number of a random stream.
+func (this *RandomGups) Next(ran uint64) uint64 {
+	if int64(ran) < 0 {
+		return (ran << 1) ^ random_gups_poly
+	}
+
+	return ran << 1
+}
+
+// Starts returns the nth number of the random stream, like HPCC_starts.
+func (this *RandomGups) Starts(n int64) uint64 {
+	for n < 0 {
+		n += random_gups_period
+	}
+	for n > random_gups_period {
+		n -= random_gups_period
+	}
+	if n == 0 {
+		return 0x1
+	}
+
+	m2 := make([]uint64, 64)
+	temp := uint64(0x1)
+	for i := 0; i < 64; i++ {
+		m2[i] = temp
+		temp = this.Next(temp)
+		temp = this.Next(temp)
+	}
+
+	i := 62
+	for ; i >= 0; i-- {
+		if (n>>i)&1 != 0 {
+			break
+		}
+	}
+
+	ran := uint64(0x2)
+	for i > 0 {
+		temp = 0
+		for j := 0; j < 64; j++ {
+			if (ran>>j)&1 != 0 {
+				temp ^= m2[j]
+			}
+		}
+		ran = temp
+		i--
+		if (n>>i)&1 != 0 {
+			ran = this.Next(ran)
+		}
+	}
+
+	return ran
+}
+
+func (this *RandomGups) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu() * this.element_type.Size(), 0},
+	)
+
+	return dpu_host
+}
+
+func (this *RandomGups) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *RandomGups) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+}
+
+func (this *RandomGups) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return 0, this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id)
+}
+
+func (this *RandomGups) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *RandomGups) Params() *misc.BenchmarkParams {
+	return microbenchmarkParams("Random-GUPS")
+}
+
+func (this *RandomGups) NumExecutions() int {
+	return this.num_executions
+}
@@ -3,7 +3,6 @@ package prim
 import (
 	"errors"
 	"math"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -14,6 +13,8 @@ type Red struct {
 	num_tasklets   int
 	num_executions int
 
+	element_type *ElementType
+
 	input_size_dpu_8bytes int64
 	buffer_a              []int64
 	counts                []int64
@@ -34,11 +35,15 @@ func (this *Red) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 1
 
-	size := int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	size := params.IntParameter("size")
 
-	elem_size := int64(8)
+	this.element_type = DefinedElementType(command_line_parser, "INT64")
+	elem_size := this.element_type.Size()
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -62,9 +67,20 @@ func (this *Red) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_8bytes = int64(math.Ceil(float64(input_size_dpu)/float64(8)) * 8)
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	bound := this.element_type.ExactBound(int64(this.Pow2(31)), input_size)
+	elements := input_generator.Elements(input_size, bound, this.element_type)
+
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_8bytes*int64(this.num_dpus); i++ {
-		a := int64(rand.Intn(this.Pow2(31)))
+		var a int64
+		if i < input_size {
+			a = elements[i]
+		} else {
+			a = 0
+		}
 
 		this.buffer_a = append(this.buffer_a, a)
 	}
@@ -148,10 +164,7 @@ func (this *Red) InputDpuHost(execution int, dpu_id int) map[string]*encoding.By
 	kernel_word.SetValue(this.kernels[dpu_id])
 	dpu_input_arguments_byte_stream.Merge(kernel_word.ToByteStream())
 
-	input_t_count_word := new(word.Word)
-	input_t_count_word.Init(32)
-	input_t_count_word.SetValue(this.input_t_counts[dpu_id])
-	dpu_input_arguments_byte_stream.Merge(input_t_count_word.ToByteStream())
+	this.element_type.Append(dpu_input_arguments_byte_stream, this.input_t_counts[dpu_id])
 
 	dpu_host := make(map[string]*encoding.ByteStream, 0)
 	dpu_host["DPU_INPUT_ARGUMENTS"] = dpu_input_arguments_byte_stream
@@ -182,10 +195,11 @@ func (this *Red) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.B
 		cycle_word.SetValue(this.cycles[dpu_id][i])
 		dpu_results_byte_stream.Merge(cycle_word.ToByteStream())
 
-		t_count_word := new(word.Word)
-		t_count_word.Init(64)
-		t_count_word.SetValue(this.t_counts[dpu_id][i])
-		dpu_results_byte_stream.Merge(t_count_word.ToByteStream())
+		// NOTE: dpu_results_t is padded to the 8-byte alignment of cycles
+		this.element_type.Append(dpu_results_byte_stream, this.t_counts[dpu_id][i])
+		for j := this.element_type.Size(); j < 8; j++ {
+			dpu_results_byte_stream.Append(0)
+		}
 	}
 
 	dpu_host := make(map[string]*encoding.ByteStream, 0)
@@ -212,10 +226,7 @@ func (this *Red) InputDpuMramHeapPointerName(
 	start_elem := this.input_size_dpu_8bytes * int64(dpu_id)
 
 	for i := int64(0); i < this.input_size_dpu_8bytes; i++ {
-		element_word := new(word.Word)
-		element_word.Init(64)
-		element_word.SetValue(this.buffer_a[start_elem+i])
-		byte_stream.Merge(element_word.ToByteStream())
+		this.element_type.Append(byte_stream, this.buffer_a[start_elem+i])
 	}
 
 	return 0, byte_stream
@@ -239,14 +250,36 @@ func (this *Red) OutputDpuMramHeapPointerName(
 	return 0, byte_stream
 }
 
+func (this *Red) OutputLayout(name string) (int64, int64, int64) {
+	// NOTE: cycles is measured by the perfcounter of the DPU, and t_count is padded to 8 bytes
+	if name == "DPU_RESULTS" {
+		return 16, 0, 8
+	}
+
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *Red) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("RED")
+	params.AddIntParam("size", 8192, 1, "number of elements")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
+}
+
 func (this *Red) NumExecutions() int {
 	return this.num_executions
 }
 
 func (this *Red) Sum(s []int64) int64 {
-	sum := int64(0)
+	sum := this.element_type.FromInt64(0)
 	for _, element := range s {
-		sum += element
+		sum = this.element_type.Apply("ADD", sum, element)
 	}
 	return sum
 }
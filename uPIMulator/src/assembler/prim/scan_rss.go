@@ -3,7 +3,6 @@ package prim
 import (
 	"errors"
 	"math"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -14,6 +13,8 @@ type ScanRss struct {
 	num_tasklets   int
 	num_executions int
 
+	element_type *ElementType
+
 	input_size_dpu_round int64
 	buffer_a             []int64
 	buffer_c             []int64
@@ -34,13 +35,18 @@ func (this *ScanRss) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 2
 
-	size := int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	size := params.IntParameter("size")
 
-	elem_size := int64(8)
+	this.element_type = DefinedElementType(command_line_parser, "INT64")
+	elem_size := this.element_type.Size()
 
-	regs := int64(128)
+	// NOTE: REGS is BLOCK_SIZE >> DIV, where BLOCK_SIZE is 1024 bytes (BL is 10)
+	regs := int64(1024) / elem_size
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -57,11 +63,17 @@ func (this *ScanRss) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_round = int64(math.Ceil(float64(input_size_dpu)/float64(int64(this.num_tasklets)*regs))) * int64(this.num_tasklets) * regs
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	bound := this.element_type.ExactBound(100, input_size)
+	elements := input_generator.Elements(input_size, bound, this.element_type)
+
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
 		if i < input_size {
-			a = int64(rand.Intn(100))
+			a = elements[i]
 		} else {
 			a = 0
 		}
@@ -75,7 +87,7 @@ func (this *ScanRss) Init(command_line_parser *misc.CommandLineParser) {
 		if i == 0 {
 			c = this.buffer_a[i]
 		} else {
-			c = this.buffer_c[i-1] + this.buffer_a[i]
+			c = this.element_type.Apply("ADD", this.buffer_c[i-1], this.buffer_a[i])
 		}
 
 		this.buffer_c = append(this.buffer_c, c)
@@ -148,10 +160,7 @@ func (this *ScanRss) InputDpuHost(execution int, dpu_id int) map[string]*encodin
 	kernel_word.SetValue(this.kernels[execution])
 	dpu_input_arguments_byte_stream.Merge(kernel_word.ToByteStream())
 
-	t_count_word := new(word.Word)
-	t_count_word.Init(64)
-	t_count_word.SetValue(this.t_counts[dpu_id][execution])
-	dpu_input_arguments_byte_stream.Merge(t_count_word.ToByteStream())
+	this.element_type.Append(dpu_input_arguments_byte_stream, this.t_counts[dpu_id][execution])
 
 	dpu_host := make(map[string]*encoding.ByteStream, 0)
 	dpu_host["DPU_INPUT_ARGUMENTS"] = dpu_input_arguments_byte_stream
@@ -173,10 +182,7 @@ func (this *ScanRss) OutputDpuHost(execution int, dpu_id int) map[string]*encodi
 
 	if execution == 0 {
 		for _, result_t_count := range this.result_t_counts[dpu_id] {
-			result_t_count_word := new(word.Word)
-			result_t_count_word.Init(64)
-			result_t_count_word.SetValue(result_t_count)
-			dpu_results_byte_stream.Merge(result_t_count_word.ToByteStream())
+			this.element_type.Append(dpu_results_byte_stream, result_t_count)
 		}
 	}
 
@@ -206,10 +212,7 @@ func (this *ScanRss) InputDpuMramHeapPointerName(
 		end_elem := this.input_size_dpu_round * int64(dpu_id+1)
 
 		for _, element := range this.buffer_a[start_elem:end_elem] {
-			element_word := new(word.Word)
-			element_word.Init(64)
-			element_word.SetValue(element)
-			byte_stream.Merge(element_word.ToByteStream())
+			this.element_type.Append(byte_stream, element)
 		}
 	}
 
@@ -236,14 +239,28 @@ func (this *ScanRss) OutputDpuMramHeapPointerName(
 		end_elem := this.input_size_dpu_round * int64(dpu_id+1)
 
 		for _, element := range this.buffer_c[start_elem:end_elem] {
-			element_word := new(word.Word)
-			element_word.Init(64)
-			element_word.SetValue(element)
-			byte_stream.Merge(element_word.ToByteStream())
+			this.element_type.Append(byte_stream, element)
 		}
 	}
 
-	return int64(this.input_size_dpu_round * 8), byte_stream
+	return this.input_size_dpu_round * this.element_type.Size(), byte_stream
+}
+
+func (this *ScanRss) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *ScanRss) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("SCAN-RSS")
+	params.AddIntParam("size", 8192, 1, "number of elements")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
 }
 
 func (this *ScanRss) NumExecutions() int {
@@ -251,18 +268,18 @@ func (this *ScanRss) NumExecutions() int {
 }
 
 func (this *ScanRss) Sum1D(s []int64) int64 {
-	sum := int64(0)
+	sum := this.element_type.FromInt64(0)
 	for _, element := range s {
-		sum += element
+		sum = this.element_type.Apply("ADD", sum, element)
 	}
 	return sum
 }
 
 func (this *ScanRss) Sum2D(s [][]int64) int64 {
-	sum := int64(0)
+	sum := this.element_type.FromInt64(0)
 	for _, elements := range s {
 		for _, element := range elements {
-			sum += element
+			sum = this.element_type.Apply("ADD", sum, element)
 		}
 	}
 	return sum
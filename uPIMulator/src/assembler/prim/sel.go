@@ -32,13 +32,18 @@ func (this *Sel) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 1
 
-	size := int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "SEL")
+
+	size := params.IntParameter("size")
 
 	elem_size := int64(8)
 
 	regs := int64(128)
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -55,11 +60,22 @@ func (this *Sel) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_round = int64(math.Ceil(float64(input_size_dpu)/float64(int64(this.num_tasklets)*regs))) * int64(this.num_tasklets) * regs
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	element_type := new(ElementType)
+	element_type.Init("INT64")
+
+	// NOTE: by default, every other element is selected
+	elements := input_generator.Elements(input_size, input_size, element_type)
+
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
-		if i < input_size {
+		if i < input_size && input_generator.IsDefault() {
 			a = i + 1
+		} else if i < input_size {
+			a = elements[i]
 		} else {
 			a = 0
 		}
@@ -221,6 +237,27 @@ func (this *Sel) OutputDpuMramHeapPointerName(
 	return this.input_size_dpu_round * 8, byte_stream
 }
 
+func (this *Sel) OutputLayout(name string) (int64, int64, int64) {
+	if name == "DPU_RESULTS" {
+		return 4, 0, 0
+	}
+
+	return 8, 0, 0
+}
+
+func (this *Sel) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("SEL")
+	params.AddIntParam("size", 8192, 1, "number of elements")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
+}
+
 func (this *Sel) NumExecutions() int {
 	return this.num_executions
 }
@@ -0,0 +1,407 @@
+package prim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/misc"
+)
+
+// Spmv prepares the data of the PrIM SpMV benchmark. Each DPU receives a contiguous range of the
+// rows of a CSR matrix, the nonzeros of these rows and the whole input vector in its MRAM heap,
+// and writes its part of the output vector after them.
+//
+// The matrix is read from --dataset in the Matrix Market coordinate format, or generated at random
+// with as many rows as the rows parameter if no dataset is given. Like the PrIM host, every nonzero and every
+// element of the input vector is 1.0.
+type Spmv struct {
+	num_dpus       int
+	num_tasklets   int
+	num_executions int
+
+	num_rows     int64
+	num_cols     int64
+	row_ptrs     []int64
+	cols         []int64
+	values       []float32
+	in_vector    []float32
+	out_vector   []float32
+	num_rows_dpu int64
+
+	row_ptrs_m   int64
+	nonzeros_m   int64
+	in_vector_m  int64
+	out_vector_m int64
+
+	input_generator *InputGenerator
+}
+
+func (this *Spmv) Init(command_line_parser *misc.CommandLineParser) {
+	num_channels := int(command_line_parser.IntParameter("num_channels"))
+	num_ranks_per_channel := int(command_line_parser.IntParameter("num_ranks_per_channel"))
+	num_dpus_per_rank := int(command_line_parser.IntParameter("num_dpus_per_rank"))
+
+	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
+	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
+
+	// NOTE: the seed only draws the random matrix when no dataset is given
+	this.input_generator = new(InputGenerator)
+	this.input_generator.Init(command_line_parser)
+	this.input_generator.CheckDefault("SpMV")
+
+	this.num_executions = 1
+
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "SpMV")
+
+	var row_idxs []int64
+	if dataset := command_line_parser.StringParameter("dataset"); dataset != "" {
+		row_idxs = this.ReadMatrixMarket(dataset)
+	} else {
+		row_idxs = this.GenerateMatrix(params.IntParameter("rows"))
+	}
+
+	// NOTE: the DPUs write the output vector two rows (8 bytes) at a time
+	if this.num_rows%2 == 1 {
+		this.num_rows++
+	}
+
+	this.Coo2Csr(row_idxs)
+
+	this.in_vector = make([]float32, 0)
+	for i := int64(0); i < this.num_cols; i++ {
+		this.in_vector = append(this.in_vector, 1.0)
+	}
+
+	this.out_vector = make([]float32, 0)
+	for row := int64(0); row < this.num_rows; row++ {
+		sum := float32(0.0)
+		for i := this.row_ptrs[row]; i < this.row_ptrs[row+1]; i++ {
+			sum += float32(this.values[i] * this.in_vector[this.cols[i]])
+		}
+
+		this.out_vector = append(this.out_vector, sum)
+	}
+
+	this.num_rows_dpu = this.RoundUp((this.num_rows-1)/int64(this.num_dpus)+1, 2)
+
+	// NOTE: a rank-parallel transfer moves the same number of bytes to the same offset of every
+	// DPU, so every region is sized for the DPU with the most rows and nonzeros
+	max_num_nonzeros := int64(0)
+	for dpu_id := 0; dpu_id < this.num_dpus; dpu_id++ {
+		start_row, num_rows := this.DpuRows(dpu_id)
+
+		if num_rows > 0 {
+			num_nonzeros := this.row_ptrs[start_row+num_rows] - this.row_ptrs[start_row]
+			max_num_nonzeros = max(max_num_nonzeros, num_nonzeros)
+		}
+	}
+
+	this.row_ptrs_m = this.RoundUp(6*4, 8)
+	this.nonzeros_m = this.row_ptrs_m + this.RoundUp((this.num_rows_dpu+1)*4, 8)
+	this.in_vector_m = this.nonzeros_m + max_num_nonzeros*8
+	this.out_vector_m = this.in_vector_m + this.RoundUp(this.num_cols*4, 8)
+}
+
+// ReadMatrixMarket reads a matrix in the Matrix Market coordinate format and returns the row of
+// each nonzero. Indices in the file begin at 1. Values in the file are ignored.
+func (this *Spmv) ReadMatrixMarket(path string) []int64 {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	row_idxs := make([]int64, 0)
+	this.cols = make([]int64, 0)
+	this.values = make([]float32, 0)
+
+	has_size := false
+	num_nonzeros := int64(0)
+	for _, line := range file_scanner.ReadLines() {
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") {
+			continue
+		} else if len(fields) < 2 || (!has_size && len(fields) < 3) {
+			err_msg := fmt.Sprintf("%s has a malformed line (%s)", path, line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		// NOTE: the size line has three numbers and an entry has a row and a column, optionally
+		// followed by a value
+		num_numbers := 2
+		if !has_size {
+			num_numbers = 3
+		}
+
+		numbers := make([]int64, 0)
+		for _, field := range fields[:num_numbers] {
+			number, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				panic(err)
+			}
+
+			numbers = append(numbers, number)
+		}
+
+		if !has_size {
+			this.num_rows = numbers[0]
+			this.num_cols = numbers[1]
+			num_nonzeros = numbers[2]
+
+			has_size = true
+		} else {
+			if numbers[0] < 1 || numbers[0] > this.num_rows || numbers[1] < 1 || numbers[1] > this.num_cols {
+				err_msg := fmt.Sprintf("%s has a nonzero out of bounds (%s)", path, line)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			row_idxs = append(row_idxs, numbers[0]-1)
+			this.cols = append(this.cols, numbers[1]-1)
+			this.values = append(this.values, 1.0)
+		}
+	}
+
+	if !has_size {
+		err_msg := fmt.Sprintf("%s has no size line", path)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if int64(len(row_idxs)) != num_nonzeros {
+		err_msg := fmt.Sprintf(
+			"%s has %d nonzeros but declares %d",
+			path,
+			len(row_idxs),
+			num_nonzeros,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return row_idxs
+}
+
+// GenerateMatrix generates a square matrix with 1 to 16 nonzeros per row at random columns and
+// returns the row of each nonzero.
+func (this *Spmv) GenerateMatrix(num_rows int64) []int64 {
+	if num_rows <= 0 {
+		err := errors.New("num rows <= 0")
+		panic(err)
+	}
+
+	this.num_rows = num_rows
+	this.num_cols = num_rows
+
+	row_idxs := make([]int64, 0)
+	this.cols = make([]int64, 0)
+	this.values = make([]float32, 0)
+	for row := int64(0); row < num_rows; row++ {
+		num_nonzeros := this.input_generator.Intn(16) + 1
+
+		row_cols := make([]int64, 0)
+		for i := 0; i < num_nonzeros; i++ {
+			row_cols = append(row_cols, int64(this.input_generator.Intn(int(num_rows))))
+		}
+
+		slices.Sort(row_cols)
+		row_cols = slices.Compact(row_cols)
+
+		for _, col := range row_cols {
+			row_idxs = append(row_idxs, row)
+			this.cols = append(this.cols, col)
+			this.values = append(this.values, 1.0)
+		}
+	}
+
+	return row_idxs
+}
+
+// Coo2Csr bins the nonzeros by row, keeping the order of the nonzeros within a row.
+func (this *Spmv) Coo2Csr(row_idxs []int64) {
+	this.row_ptrs = make([]int64, this.num_rows+1)
+	for _, row := range row_idxs {
+		this.row_ptrs[row+1]++
+	}
+
+	for row := int64(0); row < this.num_rows; row++ {
+		this.row_ptrs[row+1] += this.row_ptrs[row]
+	}
+
+	next_idxs := slices.Clone(this.row_ptrs)
+
+	cols := make([]int64, len(this.cols))
+	values := make([]float32, len(this.values))
+	for i, row := range row_idxs {
+		cols[next_idxs[row]] = this.cols[i]
+		values[next_idxs[row]] = this.values[i]
+
+		next_idxs[row]++
+	}
+
+	this.cols = cols
+	this.values = values
+}
+
+// DpuRows returns the first row and the number of rows assigned to a DPU.
+func (this *Spmv) DpuRows(dpu_id int) (int64, int64) {
+	start_row := int64(dpu_id) * this.num_rows_dpu
+
+	if start_row > this.num_rows {
+		return start_row, 0
+	} else if start_row+this.num_rows_dpu > this.num_rows {
+		return start_row, this.num_rows - start_row
+	} else {
+		return start_row, this.num_rows_dpu
+	}
+}
+
+func (this *Spmv) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *Spmv) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+/*
+Generate the MRAM heap of a DPU: the DPUParams struct, the DPU's row pointers, its nonzeros as
+(column, value) pairs and the input vector, each region padded to its size on every DPU.
+*/
+func (this *Spmv) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	start_row, num_rows := this.DpuRows(dpu_id)
+
+	row_ptrs_offset := int64(0)
+	if num_rows > 0 {
+		row_ptrs_offset = this.row_ptrs[start_row]
+	}
+
+	params := []int64{
+		num_rows,
+		row_ptrs_offset,
+		this.row_ptrs_m,
+		this.nonzeros_m,
+		this.in_vector_m,
+		this.out_vector_m,
+	}
+	for _, param := range params {
+		this.AppendWord(byte_stream, 32, param)
+	}
+
+	if num_rows > 0 {
+		for _, row_ptr := range this.row_ptrs[start_row : start_row+num_rows+1] {
+			this.AppendWord(byte_stream, 32, row_ptr)
+		}
+	}
+	this.Pad(byte_stream, this.nonzeros_m)
+
+	if num_rows > 0 {
+		for i := this.row_ptrs[start_row]; i < this.row_ptrs[start_row+num_rows]; i++ {
+			this.AppendWord(byte_stream, 32, this.cols[i])
+			this.AppendWord(byte_stream, 32, int64(math.Float32bits(this.values[i])))
+		}
+	}
+	this.Pad(byte_stream, this.in_vector_m)
+
+	for _, element := range this.in_vector {
+		this.AppendWord(byte_stream, 32, int64(math.Float32bits(element)))
+	}
+	this.Pad(byte_stream, this.out_vector_m)
+
+	return 0, byte_stream
+}
+
+/*
+Generate the expected output vector of a DPU. The rows past the DPU's last row are never written.
+*/
+func (this *Spmv) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	if execution >= this.num_executions {
+		err := errors.New("execution >= num executions")
+		panic(err)
+	} else if dpu_id >= this.num_dpus {
+		err := errors.New("DPU ID >= num DPUs")
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	start_row, num_rows := this.DpuRows(dpu_id)
+
+	for i := int64(0); i < this.num_rows_dpu; i++ {
+		if i < num_rows {
+			this.AppendWord(byte_stream, 32, int64(math.Float32bits(this.out_vector[start_row+i])))
+		} else {
+			this.AppendWord(byte_stream, 32, 0)
+		}
+	}
+
+	return this.out_vector_m, byte_stream
+}
+
+func (this *Spmv) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("SpMV")
+	params.AddIntParam("rows", 8192, 1, "number of rows of the random matrix without a dataset")
+	return params
+}
+
+func (this *Spmv) NumExecutions() int {
+	return this.num_executions
+}
+
+func (this *Spmv) AppendWord(byte_stream *encoding.ByteStream, width int, value int64) {
+	element_word := new(word.Word)
+	element_word.Init(width)
+	element_word.SetValue(value)
+	byte_stream.Merge(element_word.ToByteStream())
+}
+
+// Pad appends zeros to a byte stream until it reaches a size.
+func (this *Spmv) Pad(byte_stream *encoding.ByteStream, size int64) {
+	for byte_stream.Size() < size {
+		byte_stream.Append(0)
+	}
+}
+
+func (this *Spmv) RoundUp(value int64, multiple int64) int64 {
+	return (value + multiple - 1) / multiple * multiple
+}
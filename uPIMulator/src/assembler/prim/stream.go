@@ -0,0 +1,137 @@
+package prim
+
+import (
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// Stream prepares the data of the PrIM STREAM microbenchmark. Each DPU streams its elements A, and
+// B after them, one BL-sized block at a time, as its knob OP chooses:
+//
+//	copy:  B = A (MRAM to MRAM)
+//	copyw: B = A (through a second WRAM buffer)
+//	scale: B = n * A
+//	add:   C = A + B
+//	triad: C = A + n * B
+//
+// where n is the number of elements per DPU and C follows B. Its knob MEM (MRAM, WRAM) chooses
+// whether the DPUs time the whole kernel or only the computation in WRAM.
+type Stream struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	op string
+
+	buffer_a []int64
+	buffer_b []int64
+	buffer_c []int64
+}
+
+func (this *Stream) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"STREAM",
+		map[string]string{"BL": "10", "OP": "copy", "MEM": "MRAM"},
+		this.Params(),
+	)
+
+	this.op = this.microbenchmark.CheckedDefine(
+		"OP",
+		[]string{"copy", "copyw", "add", "scale", "triad"},
+	)
+	this.microbenchmark.CheckedDefine("MEM", []string{"MRAM", "WRAM"})
+
+	this.element_type = new(ElementType)
+	this.element_type.Init("UINT64")
+
+	this.num_executions = 1
+
+	scalar := this.element_type.FromInt64(this.microbenchmark.InputSizeDpu())
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_b = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_c = make([]int64, 0)
+	for i, a := range this.buffer_a {
+		b := this.buffer_b[i]
+
+		var c int64
+		switch this.op {
+		case "scale":
+			c = this.element_type.Apply("MUL", scalar, a)
+		case "add":
+			c = this.element_type.Apply("ADD", a, b)
+		case "triad":
+			c = this.element_type.Apply("ADD", a, this.element_type.Apply("MUL", scalar, b))
+		default:
+			c = a
+		}
+
+		this.buffer_c = append(this.buffer_c, c)
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+// HasB returns whether B is an input rather than the output.
+func (this *Stream) HasB() bool {
+	return this.op == "add" || this.op == "triad"
+}
+
+func (this *Stream) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu() * this.element_type.Size(), 0},
+	)
+
+	return dpu_host
+}
+
+func (this *Stream) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *Stream) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	byte_stream := this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+	if this.HasB() {
+		byte_stream.Merge(this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id))
+	}
+
+	return 0, byte_stream
+}
+
+func (this *Stream) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * this.element_type.Size()
+	if this.HasB() {
+		offset *= 2
+	}
+
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_c, dpu_id)
+}
+
+func (this *Stream) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *Stream) Params() *misc.BenchmarkParams {
+	return microbenchmarkParams("STREAM")
+}
+
+func (this *Stream) NumExecutions() int {
+	return this.num_executions
+}
@@ -0,0 +1,130 @@
+package prim
+
+import (
+	"errors"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// Strided prepares the data of the PrIM STRIDED microbenchmark. Each tasklet of a DPU copies every
+// stride-th element of its part of A, at the start of the MRAM heap, to B after A. Its knob OP
+// chooses how: COARSECOARSE reads and writes whole BL-sized blocks of A and B, which starts as a
+// copy of A, and FINEFINE reads and writes single elements, so the other elements of B keep what
+// the MRAM heap held before, zeros in a fresh simulation.
+//
+// Its parameter stride defaults to 2 and must be a power of two for COARSECOARSE. The number of
+// elements per DPU must be a multiple of the number of tasklets.
+type Strided struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	num_executions int
+
+	op     string
+	stride int64
+
+	buffer_a []int64
+	buffer_b []int64
+}
+
+func (this *Strided) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"STRIDED",
+		map[string]string{"BL": "8", "OP": "COARSECOARSE"},
+		this.Params(),
+	)
+
+	this.op = this.microbenchmark.CheckedDefine("OP", []string{"COARSECOARSE", "FINEFINE"})
+
+	this.stride = this.microbenchmark.Param("stride")
+	if this.op == "COARSECOARSE" && this.stride&(this.stride-1) != 0 {
+		err := errors.New("stride is not a power of two")
+		panic(err)
+	}
+
+	input_size_dpu := this.microbenchmark.InputSizeDpu()
+	num_tasklets := int64(this.microbenchmark.NumTasklets())
+	if input_size_dpu%num_tasklets != 0 {
+		err := errors.New("input size per DPU is not a multiple of the number of tasklets")
+		panic(err)
+	}
+
+	this.element_type = new(ElementType)
+	this.element_type.Init("UINT64")
+
+	this.num_executions = 1
+
+	this.buffer_a = this.microbenchmark.RandomElements(this.element_type)
+
+	if this.op == "COARSECOARSE" {
+		this.buffer_b = this.buffer_a
+	} else {
+		this.buffer_b = make([]int64, len(this.buffer_a))
+
+		// NOTE: each tasklet strides over its own part, from the start of the part
+		tasklet_size := input_size_dpu / num_tasklets
+		for i := range this.buffer_b {
+			if (int64(i)%input_size_dpu%tasklet_size)%this.stride == 0 {
+				this.buffer_b[i] = this.buffer_a[i]
+			}
+		}
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *Strided) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu() * this.element_type.Size(), this.stride, 0},
+	)
+
+	return dpu_host
+}
+
+func (this *Strided) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *Strided) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	byte_stream := this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id)
+	if this.op == "COARSECOARSE" {
+		byte_stream.Merge(this.microbenchmark.DpuElements(this.element_type, this.buffer_a, dpu_id))
+	}
+
+	return 0, byte_stream
+}
+
+func (this *Strided) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * this.element_type.Size()
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id)
+}
+
+func (this *Strided) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *Strided) Params() *misc.BenchmarkParams {
+	params := microbenchmarkParams("STRIDED")
+	params.AddIntParam("stride", 2, 1, "distance between the copied elements")
+	return params
+}
+
+func (this *Strided) NumExecutions() int {
+	return this.num_executions
+}
@@ -2,7 +2,6 @@ package prim
 
 import (
 	"errors"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/abi/word"
 	"uPIMulator/src/misc"
@@ -34,20 +33,25 @@ func (this *Trns) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "TRNS")
+
 	//NOTE(dongjae.lee@kaist.ac.kr): different parameter given if single dpu is simulated
 	if num_channels == 1 && num_ranks_per_channel == 1 && num_dpus_per_rank == 1 {
 		this.N = 1
 		this.n = 4
-		this.M = int64(command_line_parser.DataPrepParams()[0])
+		this.M = params.IntParameter("size")
 		this.m = 16
 	} else {
 		this.N = 64
 		this.n = 8
-		this.M = int64(command_line_parser.DataPrepParams()[0])
+		this.M = params.IntParameter("size")
 		this.m = 4
 	}
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 	if !is_strong_scaling {
 		this.N *= int64(this.num_dpus)
 	}
@@ -64,16 +68,21 @@ func (this *Trns) Init(command_line_parser *misc.CommandLineParser) {
 		this.num_executions = int(2 * this.N)
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	element_type := new(ElementType)
+	element_type.Init("INT64")
+
 	this.buffer_a = make([][][]int64, 0)
 	for i := int64(0); i < this.N; i++ {
 		this.buffer_a = append(this.buffer_a, make([][]int64, 0))
 
 		for j := int64(0); j < this.M*this.m; j++ {
-			this.buffer_a[i] = append(this.buffer_a[i], make([]int64, 0))
-
-			for k := int64(0); k < this.n; k++ {
-				this.buffer_a[i][j] = append(this.buffer_a[i][j], int64(rand.Intn(100)))
-			}
+			this.buffer_a[i] = append(
+				this.buffer_a[i],
+				input_generator.Elements(this.n, 100, element_type),
+			)
 		}
 	}
 
@@ -211,6 +220,19 @@ func (this *Trns) OutputDpuMramHeapPointerName(
 	return 0, byte_stream
 }
 
+func (this *Trns) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("TRNS")
+	params.AddIntParam("size", 8192, 1, "number of tiles (M) along the rows of each matrix")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether the number of matrices (N) is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
+}
+
 func (this *Trns) NumExecutions() int {
 	return this.num_executions
 }
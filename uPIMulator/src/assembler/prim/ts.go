@@ -44,9 +44,19 @@ func (this *Ts) Init(command_line_parser *misc.CommandLineParser) {
 	this.num_dpus = num_channels * num_ranks_per_channel * num_dpus_per_rank
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	// NOTE: the time series and the query of TS follow fixed patterns
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+	input_generator.CheckDefault("TS")
+
 	this.num_executions = 1
 
-	this.ts_size = int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "TS")
+
+	this.ts_size = params.IntParameter("size")
 	this.query_length = 64
 
 	if this.ts_size%(int64(this.num_dpus)*int64(this.num_tasklets)*this.query_length) != 0 {
@@ -437,6 +447,13 @@ func (this *Ts) OutputDpuMramHeapPointerName(
 	return 0, byte_stream
 }
 
+func (this *Ts) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("TS")
+	params.AddIntParam("size", 8192, 1, "length of the time series")
+	return params
+}
+
 func (this *Ts) NumExecutions() int {
 	return this.num_executions
 }
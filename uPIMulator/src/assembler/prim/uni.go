@@ -34,13 +34,18 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 1
 
-	size := int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	RejectElementType(command_line_parser, "UNI")
+
+	size := params.IntParameter("size")
 
 	elem_size := int64(8)
 
 	regs := int64(128)
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -57,15 +62,26 @@ func (this *Uni) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_round = int64(math.Ceil(float64(input_size_dpu)/float64(int64(this.num_tasklets)*regs))) * int64(this.num_tasklets) * regs
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	element_type := new(ElementType)
+	element_type.Init("INT64")
+
+	// NOTE: by default, every other element repeats the previous one
+	elements := input_generator.Elements(input_size, input_size, element_type)
+
 	this.buffer_a = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_round*int64(this.num_dpus); i++ {
 		var a int64
-		if i < input_size {
+		if i < input_size && input_generator.IsDefault() {
 			if i%2 == 0 {
 				a = i
 			} else {
 				a = i + 1
 			}
+		} else if i < input_size {
+			a = elements[i]
 		} else {
 			a = this.buffer_a[input_size-1]
 		}
@@ -269,6 +285,28 @@ func (this *Uni) OutputDpuMramHeapPointerName(
 	return this.input_size_dpu_round * 8, byte_stream
 }
 
+func (this *Uni) OutputLayout(name string) (int64, int64, int64) {
+	// NOTE: the 4 bytes after t_count pad first to its 8-byte alignment
+	if name == "DPU_RESULTS" {
+		return 24, 4, 8
+	}
+
+	return 8, 0, 0
+}
+
+func (this *Uni) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("UNI")
+	params.AddIntParam("size", 8192, 1, "number of elements")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
+}
+
 func (this *Uni) NumExecutions() int {
 	return this.num_executions
 }
@@ -3,7 +3,6 @@ package prim
 import (
 	"errors"
 	"math"
-	"math/rand"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/misc"
 )
@@ -13,6 +12,8 @@ type Vas struct {
 	num_tasklets   int
 	num_executions int
 
+	element_type *ElementType
+
 	input_size_dpu_8bytes int64
 	buffer_a              []int64
 	buffer_c              []int64
@@ -38,11 +39,15 @@ func (this *Vas) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_executions = 1
 
-	buffer_size := int64(command_line_parser.DataPrepParams()[0])
+	params := this.Params()
+	params.Parse(command_line_parser)
+
+	buffer_size := params.IntParameter("size")
 
-	elem_size := int64(4)
+	this.element_type = DefinedElementType(command_line_parser, "INT32")
+	elem_size := this.element_type.Size()
 
-	is_strong_scaling := true
+	is_strong_scaling := params.StringParameter("scaling") == "strong"
 
 	var input_size int64
 	if is_strong_scaling {
@@ -66,12 +71,24 @@ func (this *Vas) Init(command_line_parser *misc.CommandLineParser) {
 		this.input_size_dpu_8bytes = int64(math.Ceil(float64(input_size_dpu)/float64(8)) * 8)
 	}
 
+	input_generator := new(InputGenerator)
+	input_generator.Init(command_line_parser)
+
+	// NOTE: a + 1 stays exact for FLOAT and DOUBLE when a is below the bound for a single element
+	bound := this.element_type.ExactBound(int64(this.Pow2(31)), 1)
+	elements := input_generator.Elements(input_size, bound, this.element_type)
+
 	this.buffer_a = make([]int64, 0)
 	this.buffer_c = make([]int64, 0)
 	for i := int64(0); i < this.input_size_dpu_8bytes*int64(this.num_dpus); i++ {
-		a := int64(rand.Intn(this.Pow2(31)))
+		var a int64
+		if i < input_size {
+			a = elements[i]
+		} else {
+			a = 0
+		}
 
-		c := a + 1
+		c := this.element_type.Apply("ADD", a, this.element_type.FromInt64(1))
 
 		this.buffer_a = append(this.buffer_a, a)
 		this.buffer_c = append(this.buffer_c, c)
@@ -133,7 +150,24 @@ func (this *Vas) OutputDpuMramHeapPointerName(
 	byte_stream := new(encoding.ByteStream)
 	byte_stream.Init()
 
-	return this.input_size_dpu_8bytes * 4, byte_stream
+	return this.input_size_dpu_8bytes * this.element_type.Size(), byte_stream
+}
+
+func (this *Vas) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *Vas) Params() *misc.BenchmarkParams {
+	params := new(misc.BenchmarkParams)
+	params.Init("VAS")
+	params.AddIntParam("size", 8192, 1, "number of elements")
+	params.AddStringParam(
+		"scaling",
+		"strong",
+		[]string{"strong", "weak"},
+		"whether size is the total over the DPUs (strong) or per DPU (weak)",
+	)
+	return params
 }
 
 func (this *Vas) NumExecutions() int {
@@ -0,0 +1,145 @@
+package prim
+
+import (
+	"errors"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
+)
+
+// Wram prepares the data of the PrIM WRAM microbenchmark. Each DPU reads BL-sized blocks of B and C
+// into WRAM with the matching indices of A and copies B[A[i]] to C[A[i]] within each block, so the
+// pattern of the indices decides the WRAM access pattern. The MRAM heap holds the 32-bit indices A,
+// then B, then C, which starts zeroed.
+//
+// Its knob OP chooses the indices: streaming (0, 1, 2, ...), strided (each index is the previous
+// one plus the stride parameter, which defaults to 2) or random. MEM (WRAM, MRAM) chooses whether
+// the DPUs time only the copies in WRAM or the whole kernel, and TYPE (INT32, INT64) is the type of
+// B and C. The number of elements per DPU must be a multiple of the elements in a block.
+type Wram struct {
+	microbenchmark *Microbenchmark
+	element_type   *ElementType
+	index_type     *ElementType
+	num_executions int
+
+	buffer_a []int64
+	buffer_b []int64
+	buffer_c []int64
+}
+
+func (this *Wram) Init(command_line_parser *misc.CommandLineParser) {
+	this.microbenchmark = new(Microbenchmark)
+	this.microbenchmark.Init(
+		command_line_parser,
+		"WRAM",
+		map[string]string{"BL": "10", "OP": "streaming", "MEM": "WRAM", "TYPE": "INT64"},
+		this.Params(),
+	)
+
+	op := this.microbenchmark.CheckedDefine("OP", []string{"streaming", "strided", "random"})
+	this.microbenchmark.CheckedDefine("MEM", []string{"WRAM", "MRAM"})
+
+	this.element_type = new(ElementType)
+	this.element_type.Init(this.microbenchmark.CheckedDefine("TYPE", []string{"INT32", "INT64"}))
+
+	this.index_type = new(ElementType)
+	this.index_type.Init("UINT32")
+
+	input_size_dpu := this.microbenchmark.InputSizeDpu()
+	block_elems := this.microbenchmark.BlockSize() / this.element_type.Size()
+	if input_size_dpu%block_elems != 0 {
+		err := errors.New("input size per DPU is not a multiple of the elements in a block")
+		panic(err)
+	}
+
+	this.microbenchmark.CheckAlignment(this.index_type.Size())
+
+	stride := this.microbenchmark.Param("stride")
+
+	this.num_executions = 1
+
+	this.buffer_a = make([]int64, 0)
+	this.buffer_b = this.microbenchmark.RandomElements(this.element_type)
+	this.buffer_c = make([]int64, len(this.buffer_b))
+	for i := range this.buffer_b {
+		var a int64
+		if op == "streaming" {
+			a = int64(i) % block_elems
+		} else if op == "strided" {
+			// NOTE: like the PrIM host, the indices continue from the previous DPU's
+			previous := int64(0)
+			if i > 0 {
+				previous = this.buffer_a[i-1]
+			}
+			a = (previous + stride) % block_elems
+		} else {
+			a = int64(this.microbenchmark.InputGenerator().Intn(1<<31)) % block_elems
+		}
+
+		this.buffer_a = append(this.buffer_a, a)
+	}
+
+	for block := int64(0); block < int64(len(this.buffer_b)); block += block_elems {
+		for j := int64(0); j < block_elems; j++ {
+			address := block + this.buffer_a[block+j]
+			this.buffer_c[address] = this.buffer_b[address]
+		}
+	}
+
+	this.microbenchmark.PrintHeader()
+}
+
+func (this *Wram) InputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	dpu_host := make(map[string]*encoding.ByteStream, 0)
+	dpu_host["DPU_INPUT_ARGUMENTS"] = this.microbenchmark.Arguments(
+		[]int64{this.microbenchmark.InputSizeDpu(), 0},
+	)
+
+	return dpu_host
+}
+
+func (this *Wram) OutputDpuHost(execution int, dpu_id int) map[string]*encoding.ByteStream {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	return make(map[string]*encoding.ByteStream, 0)
+}
+
+func (this *Wram) InputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	byte_stream := this.microbenchmark.DpuElements(this.index_type, this.buffer_a, dpu_id)
+	byte_stream.Merge(this.microbenchmark.DpuElements(this.element_type, this.buffer_b, dpu_id))
+
+	zeros := make([]int64, len(this.buffer_c))
+	byte_stream.Merge(this.microbenchmark.DpuElements(this.element_type, zeros, dpu_id))
+
+	return 0, byte_stream
+}
+
+func (this *Wram) OutputDpuMramHeapPointerName(
+	execution int,
+	dpu_id int,
+) (int64, *encoding.ByteStream) {
+	this.microbenchmark.Verify(execution, this.num_executions, dpu_id)
+
+	offset := this.microbenchmark.InputSizeDpu() * (this.index_type.Size() + this.element_type.Size())
+	return offset, this.microbenchmark.DpuElements(this.element_type, this.buffer_c, dpu_id)
+}
+
+func (this *Wram) OutputLayout(name string) (int64, int64, int64) {
+	return this.element_type.Size(), 0, 0
+}
+
+func (this *Wram) Params() *misc.BenchmarkParams {
+	params := microbenchmarkParams("WRAM")
+	params.AddIntParam("stride", 2, 1, "distance between the indices of the strided OP")
+	return params
+}
+
+func (this *Wram) NumExecutions() int {
+	return this.num_executions
+}
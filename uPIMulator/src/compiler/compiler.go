@@ -3,6 +3,7 @@ package compiler
 import (
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"uPIMulator/src/misc"
 )
@@ -15,6 +16,8 @@ type Compiler struct {
 
 	num_dpus     int
 	num_tasklets int
+
+	benchmark_defines map[string]string
 }
 
 func (this *Compiler) Init(command_line_parser *misc.CommandLineParser) {
@@ -30,6 +33,8 @@ func (this *Compiler) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.benchmark_defines = command_line_parser.BenchmarkDefines()
+
 	this.Build()
 }
 
@@ -51,13 +56,12 @@ func (this *Compiler) Compile() {
 }
 
 func (this *Compiler) CompileBenchmark() {
-	command := exec.Command(
-		"docker",
+	args := []string{
 		"run",
 		"--privileged",
 		"--rm",
 		"-v",
-		this.root_dirpath+":/root/uPIMulator",
+		this.root_dirpath + ":/root/uPIMulator",
 		"bongjoonhyun/upimulator",
 		"python3",
 		"/root/uPIMulator/benchmark/build.py",
@@ -65,7 +69,21 @@ func (this *Compiler) CompileBenchmark() {
 		strconv.Itoa(this.num_dpus),
 		"--num_tasklets",
 		strconv.Itoa(this.num_tasklets),
-	)
+		"--benchmark",
+		this.benchmark,
+	}
+
+	names := make([]string, 0)
+	for name := range this.benchmark_defines {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		args = append(args, "--define", name+"="+this.benchmark_defines[name])
+	}
+
+	command := exec.Command("docker", args...)
 
 	err := command.Run()
 
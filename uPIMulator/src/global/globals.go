@@ -15,6 +15,17 @@ var (
 	ImageDirpath                string
 	ReadBandwidth               int64
 	WriteBandwidth              int64
+	HostTransferMode            string
+	TransposeBandwidth          int64
+	LaunchLatency               int64
+	BootSkew                    int64
+	PollInterval                int64
+	LaunchMode                  string
+	SyncInterval                int
+	HostScript                  string
+	HostSocket                  string
+	DpuLog                      string
+	DpuLogLive                  bool
 	LogicFrequency              int64
 	MemoryFrequency             int64
 	FrequencyRatio              float64
@@ -25,8 +36,37 @@ var (
 	TCl                         int64
 	TBl                         int64
 	TRp                         int64
+	TWr                         int64
+	TWtr                        int64
+	TRtw                        int64
+	TRefi                       int64
+	TRfc                        int64
+	PagePolicy                  string
+	PageTimeout                 int64
+	NumBanks                    int
+	NumBankGroups               int
+	AddressMapping              string
+	TCcdS                       int64
+	TCcdL                       int64
 	NumRevolverSchedulingCycles int64
 	LoadLocal                   int
+	DebugPort                   int
+	Sanitize                    bool
+	DetectRaces                 bool
+	DmaCheck                    string
+	Verify                      string
+	DeadlockWindow              int64
+	MaxCycles                   int64
+	SchedulingPolicy            string
+	FrFcfsCap                   int64
+	WriteHighWatermark          int64
+	WriteLowWatermark           int64
+	RecordDmaTrace              bool
+	ReplayDmaTrace              string
+	ReplayMode                  string
+	HammerThreshold             int64
+	HammerWindow                int64
+	HammerFlip                  bool
 )
 
 func Init(command_line_parser *misc.CommandLineParser) {
@@ -42,6 +82,17 @@ func Init(command_line_parser *misc.CommandLineParser) {
 	ImageDirpath = command_line_parser.StringParameter("image_dirpath")
 	ReadBandwidth = command_line_parser.IntParameter("read_bandwidth")
 	WriteBandwidth = command_line_parser.IntParameter("write_bandwidth")
+	HostTransferMode = command_line_parser.StringParameter("host_transfer_mode")
+	TransposeBandwidth = command_line_parser.IntParameter("transpose_bandwidth")
+	LaunchLatency = command_line_parser.IntParameter("launch_latency")
+	BootSkew = command_line_parser.IntParameter("boot_skew")
+	PollInterval = command_line_parser.IntParameter("poll_interval")
+	LaunchMode = command_line_parser.StringParameter("launch_mode")
+	SyncInterval = int(command_line_parser.IntParameter("sync_interval"))
+	HostScript = command_line_parser.StringParameter("host_script")
+	HostSocket = command_line_parser.StringParameter("host_socket")
+	DpuLog = command_line_parser.StringParameter("dpu_log")
+	DpuLogLive = command_line_parser.BoolParameter("dpu_log_live")
 	LogicFrequency = command_line_parser.IntParameter("logic_frequency")
 	MemoryFrequency = command_line_parser.IntParameter("memory_frequency")
 	FrequencyRatio = float64(MemoryFrequency) / float64(LogicFrequency)
@@ -52,9 +103,38 @@ func Init(command_line_parser *misc.CommandLineParser) {
 	TCl = command_line_parser.IntParameter("t_cl")
 	TBl = command_line_parser.IntParameter("t_bl")
 	TRp = command_line_parser.IntParameter("t_rp")
+	TWr = command_line_parser.IntParameter("t_wr")
+	TWtr = command_line_parser.IntParameter("t_wtr")
+	TRtw = command_line_parser.IntParameter("t_rtw")
+	TRefi = command_line_parser.IntParameter("t_refi")
+	TRfc = command_line_parser.IntParameter("t_rfc")
+	PagePolicy = command_line_parser.StringParameter("page_policy")
+	PageTimeout = command_line_parser.IntParameter("page_timeout")
+	NumBanks = int(command_line_parser.IntParameter("num_banks"))
+	NumBankGroups = int(command_line_parser.IntParameter("num_bank_groups"))
+	AddressMapping = command_line_parser.StringParameter("address_mapping")
+	TCcdS = command_line_parser.IntParameter("t_ccd_s")
+	TCcdL = command_line_parser.IntParameter("t_ccd_l")
 	NumRevolverSchedulingCycles = command_line_parser.IntParameter(
 		"num_revolver_scheduling_cycles",
 	)
 	LoadLocal = int(command_line_parser.IntParameter("load_local"))
+	DebugPort = int(command_line_parser.IntParameter("debug_port"))
+	Sanitize = command_line_parser.BoolParameter("sanitize")
+	DetectRaces = command_line_parser.BoolParameter("detect_races")
+	DmaCheck = command_line_parser.StringParameter("dma_check")
+	Verify = command_line_parser.StringParameter("verify")
+	DeadlockWindow = command_line_parser.IntParameter("deadlock_window")
+	MaxCycles = command_line_parser.IntParameter("max_cycles")
+	SchedulingPolicy = command_line_parser.StringParameter("scheduling_policy")
+	FrFcfsCap = command_line_parser.IntParameter("frfcfs_cap")
+	WriteHighWatermark = command_line_parser.IntParameter("write_high_watermark")
+	WriteLowWatermark = command_line_parser.IntParameter("write_low_watermark")
+	RecordDmaTrace = command_line_parser.BoolParameter("record_dma_trace")
+	ReplayDmaTrace = command_line_parser.StringParameter("replay_dma_trace")
+	ReplayMode = command_line_parser.StringParameter("replay_mode")
+	HammerThreshold = command_line_parser.IntParameter("hammer_threshold")
+	HammerWindow = command_line_parser.IntParameter("hammer_window")
+	HammerFlip = command_line_parser.BoolParameter("hammer_flip")
 
 }
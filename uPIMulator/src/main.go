@@ -15,7 +15,9 @@ import (
 	"uPIMulator/src/linker"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator"
+	"uPIMulator/src/simulator/debugger"
 	"uPIMulator/src/simulator/dpu/sram"
+	"uPIMulator/src/simulator/server"
 )
 
 func main() {
@@ -28,6 +30,7 @@ func main() {
 
 	if command_line_parser.IsArgSet("help") {
 		fmt.Printf("%s", command_line_parser.StringifyHelpMsgs())
+		fmt.Printf("%s", assembler.StringifyParamHelpMsgs())
 	} else {
 		command_line_validator := new(misc.CommandLineValidator)
 		command_line_validator.Init(command_line_parser)
@@ -54,6 +57,20 @@ func main() {
 		options_file_dumper.Init(options_filepath)
 		options_file_dumper.WriteLines([]string{command_line_parser.StringifyOptions()})
 
+		if global.ReplayDmaTrace != "" {
+			trace_replayer := new(simulator.TraceReplayer)
+			trace_replayer.Init(global.ReplayDmaTrace)
+			trace_replayer.Run()
+			trace_replayer.Dump()
+			trace_replayer.Fini()
+			return
+		}
+
+		// NOTE: the assembler prepares the data before the compilation, so that the parameters of
+		// the benchmark are checked first
+		assembler_ := new(assembler.Assembler)
+		assembler_.Init(command_line_parser)
+
 		compiler_ := new(compiler.Compiler)
 		compiler_.Init(command_line_parser)
 		compiler_.Compile()
@@ -62,19 +79,41 @@ func main() {
 		linker_.Init(command_line_parser)
 		linker_.Link()
 
-		assembler_ := new(assembler.Assembler)
-		assembler_.Init(command_line_parser)
 		assembler_.Assemble()
 
 		simulator_ := new(simulator.Simulator)
 		simulator_.Init()
 
+		if global.DebugPort != 0 {
+			debugger_ := new(debugger.Debugger)
+			debugger_.Init(simulator_)
+			debugger_.Run()
+			debugger_.Fini()
+
+			if debugger_.IsKilled() {
+				fmt.Println("simulation is killed by the debugger")
+				os.Exit(1)
+			}
+		}
+
+		if global.HostSocket != "" {
+			server_ := new(server.Server)
+			server_.Init(simulator_)
+			server_.Run()
+			server_.Dump()
+			server_.Fini()
+		}
+
 		for !simulator_.IsFinished() {
 			simulator_.Cycle()
 		}
 
 		simulator_.Dump()
 		simulator_.Fini()
+
+		if simulator_.HasAborted() {
+			os.Exit(1)
+		}
 	}
 }
 
@@ -92,6 +131,8 @@ func InitCommandLineParser() *misc.CommandLineParser {
 		"number of simulation threads to launch")
 
 	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "benchmark name")
+	command_line_parser.AddOption(misc.STRING, "manifest", "",
+		"JSON manifest of the host data of the benchmark (replaces its built-in data preparation)")
 
 	command_line_parser.AddOption(misc.INT, "num_channels", "1", "number of PIM memory channels")
 	command_line_parser.AddOption(
@@ -103,8 +144,17 @@ func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser.AddOption(misc.INT, "num_dpus_per_rank", "1", "number of DPUs per rank")
 
 	command_line_parser.AddOption(misc.INT, "num_tasklets", "1", "number of tasklets")
-	command_line_parser.AddOption(misc.STRING, "data_prep_params", "8192",
-		"data preparation parameter")
+	command_line_parser.AddOption(misc.STRING, "data_prep_params", "",
+		"runtime parameters of the benchmark as comma-separated NAME=VALUE pairs (see BENCHMARK_PARAMS)")
+	command_line_parser.AddOption(misc.STRING, "dataset", "",
+		"input data file of the benchmark (BFS: graph, SpMV: Matrix Market matrix, NW: two sequences)")
+	command_line_parser.AddOption(misc.STRING, "benchmark_defines", "",
+		"compile-time knobs of the benchmark as comma-separated KEY=VALUE pairs (e.g., OP=ADD,TYPE=INT32)")
+	command_line_parser.AddOption(misc.INT, "seed", "0", "seed of the random input data")
+	command_line_parser.AddOption(misc.STRING, "data_distribution", "default",
+		"distribution of the input data (default, uniform, zipf, sorted, equal)")
+	command_line_parser.AddOption(misc.STRING, "input_file", "",
+		"binary file of little-endian input elements (replaces the random input data)")
 
 	command_line_parser.AddOption(
 		misc.STRING,
@@ -151,6 +201,31 @@ func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser.AddOption(misc.INT, "t_rp", "32", "DPU MRAM t_rp timing parameter [cycle]")
 	command_line_parser.AddOption(misc.INT, "t_cl", "32", "DPU MRAM t_cl timing parameter [cycle]")
 	command_line_parser.AddOption(misc.INT, "t_bl", "8", "DPU MRAM t_bl timing parameter [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_wr", "0",
+		"DPU MRAM write recovery time before a precharge [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_wtr", "0",
+		"DPU MRAM write-to-read turnaround [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_rtw", "0",
+		"DPU MRAM read-to-write turnaround [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_refi", "0",
+		"DPU MRAM refresh interval (0 disables refresh) [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_rfc", "0",
+		"DPU MRAM refresh cycle time during which the bank is blocked [cycle]")
+
+	command_line_parser.AddOption(misc.STRING, "page_policy", "open",
+		"DPU MRAM row buffer policy (open, closed, adaptive)")
+	command_line_parser.AddOption(misc.INT, "page_timeout", "64",
+		"idle cycles after which the adaptive page policy closes the open row")
+
+	command_line_parser.AddOption(misc.INT, "num_banks", "1", "number of banks per DPU's MRAM")
+	command_line_parser.AddOption(misc.INT, "num_bank_groups", "1",
+		"number of bank groups per DPU's MRAM")
+	command_line_parser.AddOption(misc.STRING, "address_mapping", "ro_ba_co",
+		"MRAM address mapping (ro_ba_co, ro_bg_ba_co, ba_ro_co)")
+	command_line_parser.AddOption(misc.INT, "t_ccd_s", "0",
+		"DPU MRAM column-to-column delay across bank groups [cycle]")
+	command_line_parser.AddOption(misc.INT, "t_ccd_l", "0",
+		"DPU MRAM column-to-column delay within a bank group [cycle]")
 
 	command_line_parser.AddOption(
 		misc.INT,
@@ -164,6 +239,51 @@ func InitCommandLineParser() *misc.CommandLineParser {
 		"3",
 		"write bandwidth per DPU per rank [bytes/cycle]",
 	)
+	command_line_parser.AddOption(misc.STRING, "host_transfer_mode", "parallel",
+		"host-DPU transfer mode (serial, parallel)")
+	command_line_parser.AddOption(misc.INT, "transpose_bandwidth", "0",
+		"host transposition bandwidth [bytes/cycle] (0 means no transposition overhead)")
+	command_line_parser.AddOption(misc.INT, "launch_latency", "0",
+		"cycles for a launch to reach the first rank over the control interface")
+	command_line_parser.AddOption(misc.INT, "boot_skew", "0",
+		"cycles between the boots of consecutive ranks")
+	command_line_parser.AddOption(misc.INT, "poll_interval", "0",
+		"cycles between the host's completion polls (0 means completion is noticed immediately)")
+	command_line_parser.AddOption(misc.STRING, "launch_mode", "sync",
+		"DPU launch mode (sync: DPU_SYNCHRONOUS, async: DPU_ASYNCHRONOUS per rank)")
+	command_line_parser.AddOption(misc.INT, "sync_interval", "0",
+		"executions between dpu_sync points of asynchronous launches (0 means only at the end)")
+	command_line_parser.AddOption(misc.STRING, "host_script", "",
+		"host script to run instead of the fixed transfer-launch-transfer executions")
+	command_line_parser.AddOption(misc.STRING, "host_socket", "",
+		"Unix socket on which a host API server drives the DPUs instead of the benchmark's host")
+	command_line_parser.AddOption(misc.STRING, "dpu_log", "none",
+		"what to do with the DPUs' printf output (none, print: print it, save: save it to bin_dirpath)")
+	command_line_parser.AddOption(misc.BOOL, "dpu_log_live", "false",
+		"decode the DPUs' printf output while they run instead of after each launch")
+	command_line_parser.AddOption(misc.STRING, "scheduling_policy", "frfcfs",
+		"MRAM request scheduling policy (fcfs, frfcfs, frfcfs_cap, round_robin, read_priority)")
+	command_line_parser.AddOption(misc.INT, "frfcfs_cap", "4",
+		"maximum number of consecutive row hits bypassing an older request (frfcfs_cap)")
+	command_line_parser.AddOption(misc.INT, "write_high_watermark", "64",
+		"number of buffered writes that starts a write drain (read_priority)")
+	command_line_parser.AddOption(misc.INT, "write_low_watermark", "32",
+		"number of buffered writes that ends a write drain (read_priority)")
+
+	command_line_parser.AddOption(misc.BOOL, "record_dma_trace", "false",
+		"record the MRAM DMA trace of each DPU to bin_dirpath")
+	command_line_parser.AddOption(misc.STRING, "replay_dma_trace", "",
+		"replay a recorded DMA trace on the MRAM timing model only")
+	command_line_parser.AddOption(misc.STRING, "replay_mode", "closed",
+		"DMA trace replay mode (closed, open)")
+
+	command_line_parser.AddOption(misc.INT, "hammer_threshold", "0",
+		"ACTs of a row within the hammer window that disturb its neighbors (0 disables tracking)")
+	command_line_parser.AddOption(misc.INT, "hammer_window", "0",
+		"refresh window of the hammer tracker [memory cycles] (0 means 64 ms)")
+	command_line_parser.AddOption(misc.BOOL, "hammer_flip", "false",
+		"whether to flip a bit of the victim rows of a hammered row in MRAM")
+
 	command_line_parser.AddOption(
 		misc.INT,
 		"load_local",
@@ -171,6 +291,25 @@ func InitCommandLineParser() *misc.CommandLineParser {
 		"whether to load MRAM data from local",
 	)
 
+	// NOTE: when set, the simulator waits for a GDB/LLDB client on localhost:debug_port and only
+	// advances the cycle model while the client lets it run; 0 disables the debug stub
+	command_line_parser.AddOption(misc.INT, "debug_port", "0",
+		"TCP port of the GDB remote serial protocol stub (0 disables debugging)")
+
+	command_line_parser.AddOption(misc.BOOL, "sanitize", "false",
+		"whether to check stack, heap, uninitialized and DMA accesses of DPU programs")
+	command_line_parser.AddOption(misc.BOOL, "detect_races", "false",
+		"whether to report data races between tasklets on WRAM and MRAM")
+	command_line_parser.AddOption(misc.STRING, "dma_check", "none",
+		"how to handle ldma/sdma violating the DMA engine constraints (none, strict, truncate)")
+	command_line_parser.AddOption(misc.STRING, "verify", "report",
+		"how to check the outputs of every execution against the expected outputs (none, report, strict)")
+
+	command_line_parser.AddOption(misc.INT, "deadlock_window", "1000000",
+		"number of cycles without forward progress before aborting (0 disables the check)")
+	command_line_parser.AddOption(misc.INT, "max_cycles", "0",
+		"maximum number of cycles before aborting (0 means unlimited)")
+
 	return command_line_parser
 }
 
@@ -0,0 +1,241 @@
+package misc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type BenchmarkParam struct {
+	param_type        CommandLineOptionType
+	name              string
+	default_parameter string
+	custom_parameter  string
+	help_msg          string
+
+	min_value int64
+	choices   []string
+}
+
+func (this *BenchmarkParam) Parameter() string {
+	if this.custom_parameter == "" {
+		return this.default_parameter
+	} else {
+		return this.custom_parameter
+	}
+}
+
+// Validate panics if the parameter is not an integer >= min_value or not one of the choices.
+func (this *BenchmarkParam) Validate(benchmark string) {
+	if this.param_type == INT {
+		int_parameter, err := strconv.ParseInt(this.Parameter(), 10, 64)
+		if err != nil {
+			err_msg := fmt.Sprintf(
+				"%s parameter %s (%s) is not an integer",
+				benchmark,
+				this.name,
+				this.Parameter(),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if int_parameter < this.min_value {
+			err_msg := fmt.Sprintf(
+				"%s parameter %s (%d) < %d",
+				benchmark,
+				this.name,
+				int_parameter,
+				this.min_value,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	} else if !slices.Contains(this.choices, this.Parameter()) {
+		err_msg := fmt.Sprintf(
+			"%s parameter %s (%s) is not one of %s",
+			benchmark,
+			this.name,
+			this.Parameter(),
+			strings.Join(this.choices, "|"),
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+func (this *BenchmarkParam) HelpMsg() string {
+	var values string
+	if this.param_type == INT {
+		values = fmt.Sprintf("integer >= %d", this.min_value)
+	} else {
+		values = strings.Join(this.choices, "|")
+	}
+
+	return fmt.Sprintf("%s (%s, default %s)", this.help_msg, values, this.default_parameter)
+}
+
+// BenchmarkParams are the runtime parameters that a benchmark declares, given in data_prep_params
+// as comma-separated NAME=VALUE pairs. A value without a name is accepted only for a benchmark with
+// a single parameter, since a positional list silently changes meaning when its order is
+// misremembered.
+type BenchmarkParams struct {
+	benchmark string
+	names     []string
+	params    map[string]*BenchmarkParam
+}
+
+func (this *BenchmarkParams) Init(benchmark string) {
+	this.benchmark = benchmark
+	this.names = make([]string, 0)
+	this.params = make(map[string]*BenchmarkParam, 0)
+}
+
+func (this *BenchmarkParams) AddIntParam(
+	name string,
+	default_parameter int64,
+	min_value int64,
+	help_msg string,
+) {
+	benchmark_param := this.AddParam(INT, name, strconv.FormatInt(default_parameter, 10), help_msg)
+	benchmark_param.min_value = min_value
+}
+
+func (this *BenchmarkParams) AddStringParam(
+	name string,
+	default_parameter string,
+	choices []string,
+	help_msg string,
+) {
+	benchmark_param := this.AddParam(STRING, name, default_parameter, help_msg)
+	benchmark_param.choices = choices
+}
+
+func (this *BenchmarkParams) AddParam(
+	param_type CommandLineOptionType,
+	name string,
+	default_parameter string,
+	help_msg string,
+) *BenchmarkParam {
+	if _, found := this.params[name]; found {
+		err_msg := fmt.Sprintf("%s parameter (%s) is already added", this.benchmark, name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	benchmark_param := new(BenchmarkParam)
+	benchmark_param.param_type = param_type
+	benchmark_param.name = name
+	benchmark_param.default_parameter = default_parameter
+	benchmark_param.custom_parameter = ""
+	benchmark_param.help_msg = help_msg
+
+	this.names = append(this.names, name)
+	this.params[name] = benchmark_param
+	return benchmark_param
+}
+
+// Parse sets the parameters from data_prep_params and validates every parameter.
+func (this *BenchmarkParams) Parse(command_line_parser *CommandLineParser) {
+	data_prep_params := command_line_parser.StringParameter("data_prep_params")
+
+	if data_prep_params != "" {
+		for i, data_prep_param := range strings.Split(data_prep_params, ",") {
+			name, value, found := strings.Cut(data_prep_param, "=")
+			if !found {
+				if len(this.names) != 1 {
+					err_msg := this.PositionalErrMsg(i, data_prep_param)
+					err := errors.New(err_msg)
+					panic(err)
+				}
+
+				name = this.names[0]
+				value = data_prep_param
+			}
+
+			benchmark_param, found := this.params[name]
+			if !found {
+				err_msg := fmt.Sprintf("%s has no parameter %s", this.benchmark, name)
+				err := errors.New(err_msg)
+				panic(err)
+			} else if benchmark_param.custom_parameter != "" {
+				err_msg := fmt.Sprintf("%s parameter %s is given twice", this.benchmark, name)
+				err := errors.New(err_msg)
+				panic(err)
+			} else if value == "" {
+				err_msg := fmt.Sprintf("%s parameter %s is empty", this.benchmark, name)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			benchmark_param.custom_parameter = value
+		}
+	}
+
+	for _, name := range this.names {
+		this.params[name].Validate(this.benchmark)
+	}
+}
+
+// PositionalErrMsg names the parameter that a value without a name would have meant by its
+// position, so that the error says how to write it.
+func (this *BenchmarkParams) PositionalErrMsg(pos int, value string) string {
+	if pos >= len(this.names) {
+		return fmt.Sprintf(
+			"%s parameter %s has no name and %s has only %d parameters (%s)",
+			this.benchmark,
+			value,
+			this.benchmark,
+			len(this.names),
+			strings.Join(this.names, ", "),
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s parameter %s has no name; give it as %s=%s",
+		this.benchmark,
+		value,
+		this.names[pos],
+		value,
+	)
+}
+
+func (this *BenchmarkParams) IntParameter(name string) int64 {
+	benchmark_param := this.Param(name)
+	if benchmark_param.param_type != INT {
+		err_msg := fmt.Sprintf("%s parameter %s is not an integer", this.benchmark, name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	int_parameter, err := strconv.ParseInt(benchmark_param.Parameter(), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	return int_parameter
+}
+
+func (this *BenchmarkParams) StringParameter(name string) string {
+	return this.Param(name).Parameter()
+}
+
+func (this *BenchmarkParams) Param(name string) *BenchmarkParam {
+	if _, found := this.params[name]; !found {
+		err_msg := fmt.Sprintf("%s parameter (%s) is not found", this.benchmark, name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.params[name]
+}
+
+func (this *BenchmarkParams) StringifyHelpMsgs() string {
+	str := this.benchmark + "\n"
+
+	for _, name := range this.names {
+		str += "  " + name + "  -->  " + this.params[name].HelpMsg() + "\n"
+	}
+
+	return str
+}
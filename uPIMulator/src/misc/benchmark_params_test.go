@@ -0,0 +1,93 @@
+package misc_test
+
+import (
+	"testing"
+	"uPIMulator/src/misc"
+)
+
+func NewBenchmarkParams(num_params int) *misc.BenchmarkParams {
+	benchmark_params := new(misc.BenchmarkParams)
+	benchmark_params.Init("VA")
+
+	benchmark_params.AddIntParam("size", 8192, 1, "number of elements")
+	if num_params > 1 {
+		benchmark_params.AddStringParam(
+			"scaling",
+			"strong",
+			[]string{"strong", "weak"},
+			"strong or weak scaling",
+		)
+	}
+
+	return benchmark_params
+}
+
+func ParseBenchmarkParams(benchmark_params *misc.BenchmarkParams, data_prep_params string) {
+	command_line_parser := new(misc.CommandLineParser)
+	command_line_parser.Init()
+	command_line_parser.AddOption(misc.STRING, "data_prep_params", "", "benchmark parameters")
+	command_line_parser.Parse([]string{"uPIMulator", "--data_prep_params", data_prep_params})
+
+	benchmark_params.Parse(command_line_parser)
+}
+
+func TestBenchmarkParamsParse(t *testing.T) {
+	tests := []struct {
+		name             string
+		num_params       int
+		data_prep_params string
+		want_panic       bool
+		want_size        int64
+		want_scaling     string
+	}{
+		{"defaults", 2, "", false, 8192, "strong"},
+		{"named", 2, "size=16,scaling=weak", false, 16, "weak"},
+		{"any order", 2, "scaling=weak,size=16", false, 16, "weak"},
+		{"one named", 2, "scaling=weak", false, 8192, "weak"},
+		{"positional with a single parameter", 1, "16", false, 16, ""},
+		{"named with a single parameter", 1, "size=16", false, 16, ""},
+		{"positional with two parameters", 2, "16", true, 0, ""},
+		{"positional after a named one", 2, "size=16,weak", true, 0, ""},
+		{"positional past the parameters", 1, "size=16,weak", true, 0, ""},
+		{"unknown name", 2, "length=16", true, 0, ""},
+		{"given twice", 2, "size=16,size=32", true, 0, ""},
+		{"empty value", 2, "size=", true, 0, ""},
+		{"not an integer", 2, "size=many", true, 0, ""},
+		{"below the minimum", 2, "size=0", true, 0, ""},
+		{"not a choice", 2, "scaling=both", true, 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			benchmark_params := NewBenchmarkParams(test.num_params)
+			ParseBenchmarkParams(benchmark_params, test.data_prep_params)
+
+			if size := benchmark_params.IntParameter("size"); size != test.want_size {
+				t.Errorf("size = %d, want %d", size, test.want_size)
+			}
+
+			if test.num_params > 1 {
+				if scaling := benchmark_params.StringParameter("scaling"); scaling != test.want_scaling {
+					t.Errorf("scaling = %s, want %s", scaling, test.want_scaling)
+				}
+			}
+		})
+	}
+}
+
+func TestBenchmarkParamsPositional(t *testing.T) {
+	defer func() {
+		want := "VA parameter 16 has no name; give it as size=16"
+		if err, is_error := recover().(error); !is_error || err.Error() != want {
+			t.Errorf("panic = %v, want %q", err, want)
+		}
+	}()
+
+	ParseBenchmarkParams(NewBenchmarkParams(2), "16")
+}
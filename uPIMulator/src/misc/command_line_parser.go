@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 )
 
@@ -96,19 +95,26 @@ func (this *CommandLineParser) StringParameter(option string) string {
 	return command_line_option.StringParameter()
 }
 
-func (this *CommandLineParser) DataPrepParams() []int {
-	string_params := strings.Split(this.StringParameter("data_prep_params"), ",")
+// BenchmarkDefines returns the compile-time knobs of the benchmark, given as comma-separated
+// KEY=VALUE pairs in benchmark_defines.
+func (this *CommandLineParser) BenchmarkDefines() map[string]string {
+	benchmark_defines := make(map[string]string, 0)
 
-	data_prep_params := make([]int, 0)
-	for _, string_param := range string_params {
-		int_param, err := strconv.Atoi(string_param)
-		if err != nil {
+	if this.StringParameter("benchmark_defines") == "" {
+		return benchmark_defines
+	}
+
+	for _, define := range strings.Split(this.StringParameter("benchmark_defines"), ",") {
+		name, value, found := strings.Cut(define, "=")
+		if !found {
+			err_msg := fmt.Sprintf("benchmark define (%s) is not KEY=VALUE", define)
+			err := errors.New(err_msg)
 			panic(err)
 		}
 
-		data_prep_params = append(data_prep_params, int_param)
+		benchmark_defines[name] = value
 	}
-	return data_prep_params
+	return benchmark_defines
 }
 
 func (this *CommandLineParser) IsArgSet(arg string) bool {
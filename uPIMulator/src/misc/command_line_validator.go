@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 )
 
 type CommandLineValidator struct {
@@ -49,6 +50,80 @@ func (this *CommandLineValidator) Validate() {
 		panic(err)
 	}
 
+	if manifest := this.command_line_parser.StringParameter("manifest"); manifest != "" {
+		if _, stat_err := os.Stat(manifest); os.IsNotExist(stat_err) {
+			fmt.Println(manifest)
+
+			err := errors.New("manifest does not exist")
+			panic(err)
+		}
+	}
+
+	// NOTE: the defines become -D options of cmake and the compiler, so they are C macro names with
+	// plain values
+	name_regexp := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	value_regexp := regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+	for name, value := range this.command_line_parser.BenchmarkDefines() {
+		if !name_regexp.MatchString(name) || !value_regexp.MatchString(value) {
+			fmt.Printf("%s=%s\n", name, value)
+
+			err := errors.New("benchmark define is not valid")
+			panic(err)
+		}
+	}
+
+	if dataset := this.command_line_parser.StringParameter("dataset"); dataset != "" {
+		if _, stat_err := os.Stat(dataset); os.IsNotExist(stat_err) {
+			fmt.Println(dataset)
+
+			err := errors.New("dataset does not exist")
+			panic(err)
+		}
+	}
+
+	data_distribution := this.command_line_parser.StringParameter("data_distribution")
+	if data_distribution != "default" && data_distribution != "uniform" &&
+		data_distribution != "zipf" && data_distribution != "sorted" && data_distribution != "equal" {
+		err := errors.New("data_distribution is not valid")
+		panic(err)
+	}
+
+	if input_file := this.command_line_parser.StringParameter("input_file"); input_file != "" {
+		if _, stat_err := os.Stat(input_file); os.IsNotExist(stat_err) {
+			fmt.Println(input_file)
+
+			err := errors.New("input_file does not exist")
+			panic(err)
+		} else if data_distribution != "default" {
+			err := errors.New("input_file and data_distribution cannot be used together")
+			panic(err)
+		}
+	}
+
+	if host_script := this.command_line_parser.StringParameter("host_script"); host_script != "" {
+		if _, stat_err := os.Stat(host_script); os.IsNotExist(stat_err) {
+			fmt.Println(host_script)
+
+			err := errors.New("host_script does not exist")
+			panic(err)
+		}
+	}
+
+	if this.command_line_parser.StringParameter("host_socket") != "" &&
+		this.command_line_parser.StringParameter("host_script") != "" {
+		err := errors.New("host_socket and host_script cannot be used together")
+		panic(err)
+	}
+
+	dpu_log := this.command_line_parser.StringParameter("dpu_log")
+	if dpu_log != "none" && dpu_log != "print" && dpu_log != "save" {
+		err := errors.New("dpu_log is not valid")
+		panic(err)
+	} else if this.command_line_parser.BoolParameter("dpu_log_live") && dpu_log == "none" {
+		err := errors.New("dpu_log_live requires dpu_log")
+		panic(err)
+	}
+
 	if this.command_line_parser.IntParameter("logic_frequency") <= 0 {
 		err := errors.New("logic_frequency <= 0")
 		panic(err)
@@ -109,6 +184,75 @@ func (this *CommandLineValidator) Validate() {
 		panic(err)
 	}
 
+	if this.command_line_parser.IntParameter("t_wr") < 0 {
+		err := errors.New("t_wr < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_wtr") < 0 {
+		err := errors.New("t_wtr < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_rtw") < 0 {
+		err := errors.New("t_rtw < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_refi") < 0 {
+		err := errors.New("t_refi < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_rfc") < 0 {
+		err := errors.New("t_rfc < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_refi") > 0 &&
+		this.command_line_parser.IntParameter("t_rfc") >= this.command_line_parser.IntParameter("t_refi") {
+		err := errors.New("t_rfc >= t_refi")
+		panic(err)
+	}
+
+	page_policy := this.command_line_parser.StringParameter("page_policy")
+	if page_policy != "open" && page_policy != "closed" && page_policy != "adaptive" {
+		err := errors.New("page_policy is not valid")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("page_timeout") < 0 {
+		err := errors.New("page_timeout < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("num_banks") <= 0 {
+		err := errors.New("num_banks <= 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("num_bank_groups") <= 0 ||
+		this.command_line_parser.IntParameter("num_banks")%this.command_line_parser.IntParameter("num_bank_groups") != 0 {
+		err := errors.New("num_bank_groups does not divide num_banks")
+		panic(err)
+	}
+
+	address_mapping := this.command_line_parser.StringParameter("address_mapping")
+	if address_mapping != "ro_ba_co" && address_mapping != "ro_bg_ba_co" && address_mapping != "ba_ro_co" {
+		err := errors.New("address_mapping is not valid")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_ccd_s") < 0 {
+		err := errors.New("t_ccd_s < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("t_ccd_l") < 0 {
+		err := errors.New("t_ccd_l < 0")
+		panic(err)
+	}
+
 	if this.command_line_parser.IntParameter("read_bandwidth") <= 0 {
 		err := errors.New("read_bandwidth <= 0")
 		panic(err)
@@ -118,4 +262,100 @@ func (this *CommandLineValidator) Validate() {
 		err := errors.New("write_bandwidth <= 0")
 		panic(err)
 	}
+
+	scheduling_policies := map[string]bool{
+		"fcfs":          true,
+		"frfcfs":        true,
+		"frfcfs_cap":    true,
+		"round_robin":   true,
+		"read_priority": true,
+	}
+	if !scheduling_policies[this.command_line_parser.StringParameter("scheduling_policy")] {
+		err := errors.New("scheduling_policy is not valid")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("frfcfs_cap") < 0 {
+		err := errors.New("frfcfs_cap < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("write_low_watermark") < 0 {
+		err := errors.New("write_low_watermark < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("write_high_watermark") <=
+		this.command_line_parser.IntParameter("write_low_watermark") {
+		err := errors.New("write_high_watermark <= write_low_watermark")
+		panic(err)
+	}
+
+	host_transfer_mode := this.command_line_parser.StringParameter("host_transfer_mode")
+	if host_transfer_mode != "serial" && host_transfer_mode != "parallel" {
+		err := errors.New("host_transfer_mode is not valid")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("transpose_bandwidth") < 0 {
+		err := errors.New("transpose_bandwidth < 0")
+		panic(err)
+	}
+
+	launch_mode := this.command_line_parser.StringParameter("launch_mode")
+	if launch_mode != "sync" && launch_mode != "async" {
+		err := errors.New("launch_mode is not valid")
+		panic(err)
+	}
+
+	for _, option := range []string{"launch_latency", "boot_skew", "poll_interval", "sync_interval"} {
+		if this.command_line_parser.IntParameter(option) < 0 {
+			err := errors.New(option + " < 0")
+			panic(err)
+		}
+	}
+
+	dma_check := this.command_line_parser.StringParameter("dma_check")
+	if dma_check != "none" && dma_check != "strict" && dma_check != "truncate" {
+		err := errors.New("dma_check is not valid")
+		panic(err)
+	}
+
+	verify := this.command_line_parser.StringParameter("verify")
+	if verify != "none" && verify != "report" && verify != "strict" {
+		err := errors.New("verify is not valid")
+		panic(err)
+	}
+
+	replay_mode := this.command_line_parser.StringParameter("replay_mode")
+	if replay_mode != "closed" && replay_mode != "open" {
+		err := errors.New("replay_mode is not valid")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("hammer_threshold") < 0 {
+		err := errors.New("hammer_threshold < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("hammer_window") < 0 {
+		err := errors.New("hammer_window < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("debug_port") < 0 ||
+		this.command_line_parser.IntParameter("debug_port") > 65535 {
+		err := errors.New("debug_port is not a valid TCP port")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("deadlock_window") < 0 {
+		err := errors.New("deadlock_window < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("max_cycles") < 0 {
+		err := errors.New("max_cycles < 0")
+		panic(err)
+	}
 }
@@ -0,0 +1,147 @@
+package misc
+
+import (
+	"fmt"
+)
+
+// NOTE: a sample of up to 63 bits falls into one of 64 buckets
+const num_histogram_buckets = 64
+
+// Histogram counts samples in power-of-two buckets: bucket i holds samples in [2^(i-1), 2^i),
+// bucket 0 holds 0 (and negative samples). It takes a fixed amount of memory however many samples
+// are added. The count, sum, min and max are exact; a percentile is interpolated within the bucket
+// that holds it, so it is off by less than the width of that bucket.
+type Histogram struct {
+	name    string
+	buckets [num_histogram_buckets]int64
+	count   int64
+	sum     int64
+	min     int64
+	max     int64
+}
+
+func (this *Histogram) Init(name string) {
+	this.name = name
+	this.buckets = [num_histogram_buckets]int64{}
+	this.count = 0
+	this.sum = 0
+	this.min = 0
+	this.max = 0
+}
+
+func (this *Histogram) Name() string {
+	return this.name
+}
+
+func (this *Histogram) Add(sample int64) {
+	if this.count == 0 || sample < this.min {
+		this.min = sample
+	}
+
+	if this.count == 0 || sample > this.max {
+		this.max = sample
+	}
+
+	this.buckets[this.Bucket(sample)]++
+	this.count++
+	this.sum += sample
+}
+
+func (this *Histogram) Count() int64 {
+	return this.count
+}
+
+func (this *Histogram) Sum() int64 {
+	return this.sum
+}
+
+func (this *Histogram) Mean() float64 {
+	if this.count == 0 {
+		return 0
+	}
+
+	return float64(this.sum) / float64(this.count)
+}
+
+func (this *Histogram) Min() int64 {
+	return this.min
+}
+
+func (this *Histogram) Max() int64 {
+	return this.max
+}
+
+// Percentile returns an estimate of the sample at p percent of the sorted samples. The samples of
+// a bucket are assumed to be spread evenly over the bucket.
+func (this *Histogram) Percentile(p float64) int64 {
+	if this.count == 0 {
+		return 0
+	}
+
+	index := int64(p / 100 * float64(this.count-1))
+
+	for bucket, count := range this.buckets {
+		if index >= count {
+			index -= count
+			continue
+		}
+
+		begin, end := this.BucketRange(bucket)
+		sample := begin + int64(float64(end-begin)*float64(index)/float64(count))
+
+		return max(this.min, min(sample, this.max))
+	}
+
+	return this.max
+}
+
+func (this *Histogram) Bucket(sample int64) int {
+	bucket := 0
+	for sample > 0 {
+		sample >>= 1
+		bucket++
+	}
+	return bucket
+}
+
+// BucketRange returns the samples [begin, end) that a bucket holds.
+func (this *Histogram) BucketRange(bucket int) (int64, int64) {
+	if bucket == 0 {
+		return 0, 1
+	}
+
+	begin := int64(1) << (bucket - 1)
+	end := begin << 1
+	if end < 0 {
+		end = begin + (begin - 1)
+	}
+
+	return begin, end
+}
+
+func (this *Histogram) ToLines() []string {
+	lines := make([]string, 0)
+
+	lines = append(lines, fmt.Sprintf("%s_count: %d", this.name, this.Count()))
+	if this.count == 0 {
+		return lines
+	}
+
+	lines = append(lines, fmt.Sprintf("%s_mean: %.2f", this.name, this.Mean()))
+	lines = append(lines, fmt.Sprintf("%s_min: %d", this.name, this.Min()))
+	lines = append(lines, fmt.Sprintf("%s_p50: %d", this.name, this.Percentile(50)))
+	lines = append(lines, fmt.Sprintf("%s_p90: %d", this.name, this.Percentile(90)))
+	lines = append(lines, fmt.Sprintf("%s_p99: %d", this.name, this.Percentile(99)))
+	lines = append(lines, fmt.Sprintf("%s_max: %d", this.name, this.Max()))
+
+	for bucket, count := range this.buckets {
+		if count == 0 {
+			continue
+		}
+
+		begin, end := this.BucketRange(bucket)
+		lines = append(lines, fmt.Sprintf("%s_[%d,%d): %d", this.name, begin, end, count))
+	}
+
+	return lines
+}
@@ -0,0 +1,144 @@
+package misc_test
+
+import (
+	"slices"
+	"testing"
+	"uPIMulator/src/misc"
+)
+
+func NewHistogram(samples []int64) *misc.Histogram {
+	histogram := new(misc.Histogram)
+	histogram.Init("latency")
+
+	for _, sample := range samples {
+		histogram.Add(sample)
+	}
+
+	return histogram
+}
+
+func Range(begin int64, end int64) []int64 {
+	samples := make([]int64, 0)
+	for sample := begin; sample < end; sample++ {
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+func TestHistogramPercentile(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int64
+		p       float64
+		want    int64
+	}{
+		{"empty", nil, 50, 0},
+		{"single sample", []int64{5}, 50, 5},
+		{"equal samples are clamped to min", slices.Repeat([]int64{7}, 10), 50, 7},
+		{"zeros", []int64{0, 0, 0, 1}, 50, 0},
+		{"min", Range(1, 101), 0, 1},
+		{"median of 1..100", Range(1, 101), 50, 50},
+		{"max is clamped", Range(1, 101), 100, 100},
+		{"spread over one bucket", Range(32, 64), 50, 47},
+		{"p90 within its bucket", Range(32, 64), 90, 59},
+		{"order does not matter", []int64{63, 32, 48, 40, 56}, 50, 44},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			histogram := NewHistogram(test.samples)
+
+			if got := histogram.Percentile(test.p); got != test.want {
+				t.Errorf("Percentile(%v) = %d, want %d", test.p, got, test.want)
+			}
+		})
+	}
+}
+
+// TestHistogramPercentileError checks that an estimated percentile is off from the exact one by
+// less than the width of the bucket that holds the exact one.
+func TestHistogramPercentileError(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int64
+	}{
+		{"1..1000", Range(1, 1001)},
+		{"skewed", append(slices.Repeat([]int64{3}, 900), Range(1000, 1100)...)},
+		{"powers of two", []int64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			histogram := NewHistogram(test.samples)
+
+			sorted := slices.Clone(test.samples)
+			slices.Sort(sorted)
+
+			for _, p := range []float64{0, 10, 50, 90, 99, 100} {
+				exact := sorted[int(p/100*float64(len(sorted)-1))]
+
+				width := int64(1)
+				if exact > 0 {
+					width = int64(1) << (histogram.Bucket(exact) - 1)
+				}
+
+				got := histogram.Percentile(p)
+				if got-exact >= width || exact-got >= width {
+					t.Errorf("Percentile(%v) = %d, exact %d, bucket width %d", p, got, exact, width)
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramToLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int64
+		want    []string
+	}{
+		{"empty", nil, []string{"latency_count: 0"}},
+		{
+			"buckets",
+			[]int64{0, 1, 2, 3},
+			[]string{
+				"latency_count: 4",
+				"latency_mean: 1.50",
+				"latency_min: 0",
+				"latency_p50: 1",
+				"latency_p90: 2",
+				"latency_p99: 2",
+				"latency_max: 3",
+				"latency_[0,1): 1",
+				"latency_[1,2): 1",
+				"latency_[2,4): 2",
+			},
+		},
+		{
+			"empty buckets are skipped",
+			[]int64{1, 100},
+			[]string{
+				"latency_count: 2",
+				"latency_mean: 50.50",
+				"latency_min: 1",
+				"latency_p50: 1",
+				"latency_p90: 1",
+				"latency_p99: 1",
+				"latency_max: 100",
+				"latency_[1,2): 1",
+				"latency_[64,128): 1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewHistogram(test.samples).ToLines()
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("ToLines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
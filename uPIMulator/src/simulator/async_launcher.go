@@ -0,0 +1,179 @@
+package simulator
+
+import (
+	"fmt"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/host"
+	"uPIMulator/src/simulator/rank"
+)
+
+type RankLaunchState int
+
+const (
+	TRANSFERRING RankLaunchState = iota
+	RUNNING
+	RETURNING
+	SYNCING
+	FINISHED
+)
+
+// AsyncLauncher models DPU_ASYNCHRONOUS launches: every rank goes through its executions on its
+// own, so that the host transfers to and from a rank while the other ranks compute. A transfer
+// occupies its channel, so transfers to ranks of the same channel are serialized on the shared
+// timeline. Every sync_interval executions, the ranks meet at a dpu_sync: a rank that reaches it
+// waits until every rank has reached it.
+type AsyncLauncher struct {
+	host *host.Host
+
+	ranks        []*rank.Rank
+	states       []RankLaunchState
+	executions   []int
+	ready_cycles []int64
+	begin_cycles []int64
+
+	channel_cycles []int64
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *AsyncLauncher) Init(host_ *host.Host, channels []*channel.Channel) {
+	this.host = host_
+
+	this.ranks = make([]*rank.Rank, 0)
+	for _, channel_ := range channels {
+		this.ranks = append(this.ranks, channel_.Ranks()...)
+	}
+
+	this.states = make([]RankLaunchState, len(this.ranks))
+	this.executions = make([]int, len(this.ranks))
+	this.ready_cycles = make([]int64, len(this.ranks))
+	this.begin_cycles = make([]int64, len(this.ranks))
+
+	this.channel_cycles = make([]int64, len(channels))
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("AsyncLauncher")
+
+	for i := range this.ranks {
+		this.Transfer(i)
+	}
+}
+
+func (this *AsyncLauncher) Fini() {
+}
+
+func (this *AsyncLauncher) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *AsyncLauncher) IsFinished() bool {
+	for _, state := range this.states {
+		if state != FINISHED {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *AsyncLauncher) Cycle() {
+	cycles := this.host.Cycles()
+
+	for i, rank_ := range this.ranks {
+		if this.states[i] == TRANSFERRING && cycles >= this.ready_cycles[i] {
+			this.Increment(i, "cpu_dpu_cycles", cycles-this.begin_cycles[i])
+
+			this.host.LaunchRank(rank_, cycles+global.LaunchLatency)
+
+			this.states[i] = RUNNING
+			this.begin_cycles[i] = cycles
+		} else if this.states[i] == RUNNING && this.host.IsRankComplete(rank_, this.begin_cycles[i]) {
+			fmt.Printf(
+				"execution (%d) of rank %d-%d is finished...\n",
+				this.executions[i],
+				rank_.ChannelId(),
+				rank_.RankId(),
+			)
+
+			this.Increment(i, "kernel_cycles", cycles-this.begin_cycles[i])
+
+			this.Reserve(i, this.host.CheckRank(this.executions[i], rank_))
+
+			this.states[i] = RETURNING
+			this.begin_cycles[i] = cycles
+		} else if this.states[i] == RETURNING && cycles >= this.ready_cycles[i] {
+			this.Increment(i, "dpu_cpu_cycles", cycles-this.begin_cycles[i])
+
+			this.executions[i]++
+
+			if this.executions[i] == this.host.NumExecutions() {
+				this.states[i] = FINISHED
+			} else if global.SyncInterval > 0 && this.executions[i]%global.SyncInterval == 0 {
+				this.states[i] = SYNCING
+				this.begin_cycles[i] = cycles
+			} else {
+				this.Transfer(i)
+			}
+		}
+	}
+
+	this.Sync()
+
+	this.stat_factory.Increment("cycles", 1)
+}
+
+// Sync releases the ranks waiting at a dpu_sync once no rank is still on its way to it.
+func (this *AsyncLauncher) Sync() {
+	num_syncing := 0
+	for _, state := range this.states {
+		if state == SYNCING {
+			num_syncing++
+		} else if state != FINISHED {
+			return
+		}
+	}
+
+	if num_syncing == 0 {
+		return
+	}
+
+	this.stat_factory.Increment("num_syncs", 1)
+
+	for i, state := range this.states {
+		if state == SYNCING {
+			this.Increment(i, "sync_cycles", this.host.Cycles()-this.begin_cycles[i])
+			this.Transfer(i)
+		}
+	}
+}
+
+// Transfer sends the inputs of the next execution of a rank. The bytes reach the DPUs right away
+// since the rank is idle, but the rank is only launched once the transfer is over.
+func (this *AsyncLauncher) Transfer(i int) {
+	this.Reserve(i, this.host.ScheduleRank(this.executions[i], this.ranks[i]))
+
+	this.states[i] = TRANSFERRING
+	this.begin_cycles[i] = this.host.Cycles()
+}
+
+// Reserve books the channel of a rank for a transfer of the given cycles, after the transfers
+// already booked on the channel.
+func (this *AsyncLauncher) Reserve(i int, cycles int64) {
+	channel_id := this.ranks[i].ChannelId()
+
+	begin_cycle := this.host.Cycles()
+	if this.channel_cycles[channel_id] > begin_cycle {
+		begin_cycle = this.channel_cycles[channel_id]
+	}
+
+	this.channel_cycles[channel_id] = begin_cycle + cycles
+	this.ready_cycles[i] = begin_cycle + cycles
+}
+
+func (this *AsyncLauncher) Increment(i int, stat string, value int64) {
+	rank_ := this.ranks[i]
+
+	this.stat_factory.Increment(stat, value)
+	this.stat_factory.Increment(fmt.Sprintf("rank%d_%d_%s", rank_.ChannelId(), rank_.RankId(), stat), value)
+}
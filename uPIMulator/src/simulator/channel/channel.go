@@ -18,6 +18,8 @@ type Channel struct {
 	input_q         *ChannelMessageQ
 	communication_q *ChannelMessageQ
 	ready_q         *ChannelMessageQ
+
+	cycles int64
 }
 
 func (this *Channel) Init(channel_id int) {
@@ -43,6 +45,8 @@ func (this *Channel) Init(channel_id int) {
 
 	this.ready_q = new(ChannelMessageQ)
 	this.ready_q.Init(-1, 0)
+
+	this.cycles = 0
 }
 
 func (this *Channel) Fini() {
@@ -77,6 +81,12 @@ func (this *Channel) Dpus() []*dpu.Dpu {
 	return dpus
 }
 
+// Cycles returns the number of cycles the channel has spent transferring data between the host
+// and the DPUs.
+func (this *Channel) Cycles() int64 {
+	return this.cycles
+}
+
 func (this *Channel) Lock() {
 	this.mutex.Lock()
 }
@@ -145,6 +155,8 @@ func (this *Channel) Cycle() {
 	this.input_q.Cycle()
 	this.communication_q.Cycle()
 	this.ready_q.Cycle()
+
+	this.cycles++
 }
 
 func (this *Channel) ServiceInputQ() {
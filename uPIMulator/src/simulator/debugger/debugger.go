@@ -0,0 +1,880 @@
+package debugger
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/global"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
+)
+
+// NOTE: the debugger polls for an interrupt (Ctrl-C) once every num_interrupt_polling_cycles
+// logic cycles while the cycle model is running
+const num_interrupt_polling_cycles = 1024
+
+type StopEvent struct {
+	dpu_index   int
+	debug_event *logic.DebugEvent
+}
+
+// Debugger is a GDB remote serial protocol stub. While a client is attached, the simulator only
+// cycles inside Resume, so every register and memory access observes a paused cycle model.
+//
+// Each tasklet of each DPU is exposed as a thread whose ID is
+// dpu_index * max_num_tasklets + thread_id + 1. Memory is exposed with the same address map as
+// the linker (atomic, WRAM, IRAM and MRAM offsets of the config loader) and is read from the DPU
+// of the current general thread.
+type Debugger struct {
+	simulator *simulator.Simulator
+
+	listener      net.Listener
+	packet_stream *PacketStream
+
+	symbol_file_path string
+
+	general_thread_id int
+	stop_reply        string
+	stop_events       []*StopEvent
+
+	is_detached bool
+	is_killed   bool
+}
+
+func (this *Debugger) Init(simulator_ *simulator.Simulator) {
+	this.simulator = simulator_
+
+	address := fmt.Sprintf("127.0.0.1:%d", global.DebugPort)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		panic(err)
+	}
+
+	this.listener = listener
+	this.packet_stream = nil
+
+	// NOTE: the linker writes the executable as assembly and binary images, not as an ELF, so the
+	// client loads the linker's labels from a symbol file instead
+	this.symbol_file_path = filepath.Join(global.BinDirpath, "symbols.elf")
+
+	symbol_file := new(SymbolFile)
+	symbol_file.Init(simulator_.SymbolTable())
+	symbol_file.Write(this.symbol_file_path)
+
+	this.general_thread_id = this.ThreadId(0, 0)
+	this.stop_reply = fmt.Sprintf("T05thread:%x;", this.general_thread_id)
+	this.stop_events = make([]*StopEvent, 0)
+
+	this.is_detached = false
+	this.is_killed = false
+}
+
+func (this *Debugger) Fini() {
+	if this.packet_stream != nil {
+		this.packet_stream.Fini()
+	}
+
+	this.listener.Close()
+}
+
+// Run waits for a client and serves it until it detaches, kills the target or disconnects. After
+// a detach or a disconnect, the simulator runs to completion without the debugger.
+func (this *Debugger) Run() {
+	fmt.Printf("waiting for a debugger on %s...\n", this.listener.Addr().String())
+	fmt.Printf("load the symbols with \"file %s\"\n", this.symbol_file_path)
+
+	conn, err := this.listener.Accept()
+	if err != nil {
+		panic(err)
+	}
+
+	this.packet_stream = new(PacketStream)
+	this.packet_stream.Init(conn)
+
+	for packet := range this.packet_stream.PacketQ() {
+		if packet == INTERRUPT {
+			continue
+		}
+
+		reply, has_reply := this.Handle(packet)
+		if has_reply {
+			this.packet_stream.WritePacket(reply)
+		}
+
+		if this.is_detached {
+			break
+		}
+	}
+
+	this.ClearDebugUnits()
+}
+
+// IsKilled returns whether the client has killed the target, which ends the simulation.
+func (this *Debugger) IsKilled() bool {
+	return this.is_killed
+}
+
+func (this *Debugger) Handle(packet string) (string, bool) {
+	if len(packet) == 0 {
+		return "", true
+	}
+
+	command := packet[0]
+	args := packet[1:]
+
+	if command == '?' {
+		return this.stop_reply, true
+	} else if command == 'g' {
+		return this.ReadRegisters(), true
+	} else if command == 'G' {
+		return this.WriteRegisters(args), true
+	} else if command == 'p' {
+		return this.ReadRegister(args), true
+	} else if command == 'P' {
+		return this.WriteRegister(args), true
+	} else if command == 'm' {
+		return this.ReadMemory(args), true
+	} else if command == 'M' {
+		return this.WriteMemory(args), true
+	} else if command == 'Z' {
+		return this.InsertPoint(args), true
+	} else if command == 'z' {
+		return this.RemovePoint(args), true
+	} else if command == 'c' {
+		return this.Resume(nil), true
+	} else if command == 's' {
+		thread_id := this.general_thread_id
+		return this.Resume(&thread_id), true
+	} else if command == 'H' {
+		return this.SetThread(args), true
+	} else if command == 'T' {
+		return this.CheckThread(args), true
+	} else if command == 'D' {
+		this.is_detached = true
+		return "OK", true
+	} else if command == 'k' {
+		this.is_detached = true
+		this.is_killed = true
+		return "", false
+	} else if command == 'q' || command == 'Q' {
+		return this.HandleQuery(packet), true
+	} else if command == 'v' {
+		return this.HandleV(packet), true
+	} else {
+		return "", true
+	}
+}
+
+func (this *Debugger) HandleQuery(packet string) string {
+	if strings.HasPrefix(packet, "qSupported") {
+		return "PacketSize=4000;qXfer:features:read+;QStartNoAckMode+;vContSupported+"
+	} else if packet == "QStartNoAckMode" {
+		this.packet_stream.SetNoAck()
+		return "OK"
+	} else if packet == "qAttached" {
+		return "1"
+	} else if packet == "qC" {
+		return fmt.Sprintf("QC%x", this.general_thread_id)
+	} else if packet == "qfThreadInfo" {
+		thread_ids := make([]string, 0)
+		for dpu_index := range this.simulator.Dpus() {
+			for thread_id := 0; thread_id < global.NumTasklets; thread_id++ {
+				thread_ids = append(thread_ids, fmt.Sprintf("%x", this.ThreadId(dpu_index, thread_id)))
+			}
+		}
+		return "m" + strings.Join(thread_ids, ",")
+	} else if packet == "qsThreadInfo" {
+		return "l"
+	} else if strings.HasPrefix(packet, "qThreadExtraInfo,") {
+		dpu_, thread, found := this.FindThread(packet[len("qThreadExtraInfo,"):])
+		if !found {
+			return "E01"
+		}
+		return hex.EncodeToString([]byte(this.StringifyThread(dpu_, thread)))
+	} else if strings.HasPrefix(packet, "qXfer:features:read:target.xml:") {
+		return this.ReadTargetXml(packet[len("qXfer:features:read:target.xml:"):])
+	} else if strings.HasPrefix(packet, "qRegisterInfo") {
+		return this.RegisterInfo(packet[len("qRegisterInfo"):])
+	} else if packet == "qHostInfo" || packet == "qProcessInfo" {
+		return "endian:little;ptrsize:4;"
+	} else if strings.HasPrefix(packet, "qRcmd,") {
+		return this.HandleMonitor(packet[len("qRcmd,"):])
+	} else {
+		return ""
+	}
+}
+
+func (this *Debugger) HandleV(packet string) string {
+	if packet == "vCont?" {
+		return "vCont;c;C;s;S"
+	} else if strings.HasPrefix(packet, "vCont;") {
+		for _, action := range strings.Split(packet[len("vCont;"):], ";") {
+			if len(action) == 0 || (action[0] != 's' && action[0] != 'S') {
+				continue
+			}
+
+			thread_id := this.general_thread_id
+			if colon := strings.Index(action, ":"); colon != -1 {
+				parsed_thread_id, err := strconv.ParseInt(action[colon+1:], 16, 64)
+				if err == nil && parsed_thread_id > 0 {
+					thread_id = int(parsed_thread_id)
+				}
+			}
+
+			return this.Resume(&thread_id)
+		}
+
+		return this.Resume(nil)
+	} else {
+		return ""
+	}
+}
+
+// Resume cycles the simulator until a breakpoint, watchpoint or single step is hit, the client
+// interrupts, or the simulation finishes. If step_thread_id is set, the thread stops right after
+// its next instruction is issued; the other threads keep running in the meantime as they would
+// on the real DPU.
+func (this *Debugger) Resume(step_thread_id *int) string {
+	dpus := this.simulator.Dpus()
+
+	for _, stop_event := range this.stop_events {
+		if stop_event.debug_event.DebugEventType() == logic.BREAKPOINT {
+			dpus[stop_event.dpu_index].DebugUnit().Skip(stop_event.debug_event.ThreadId())
+		}
+	}
+	this.stop_events = make([]*StopEvent, 0)
+
+	if step_thread_id != nil {
+		dpu_index, thread_id := this.DecodeThreadId(*step_thread_id)
+		if dpu_index < len(dpus) {
+			dpus[dpu_index].DebugUnit().Skip(thread_id)
+			dpus[dpu_index].DebugUnit().Step(thread_id)
+		}
+	}
+
+	for cycle := 0; ; cycle++ {
+		if this.simulator.IsFinished() {
+			this.stop_reply = "W00"
+			return this.stop_reply
+		}
+
+		this.simulator.Cycle()
+
+		for dpu_index, dpu_ := range dpus {
+			for _, debug_event := range dpu_.DebugUnit().Events() {
+				stop_event := new(StopEvent)
+				stop_event.dpu_index = dpu_index
+				stop_event.debug_event = debug_event
+				this.stop_events = append(this.stop_events, stop_event)
+			}
+			dpu_.DebugUnit().ClearEvents()
+		}
+
+		if len(this.stop_events) > 0 {
+			this.stop_reply = this.StopReply(this.stop_events[0])
+			return this.stop_reply
+		}
+
+		if cycle%num_interrupt_polling_cycles == 0 && this.IsInterrupted() {
+			this.stop_reply = fmt.Sprintf("T02thread:%x;", this.general_thread_id)
+			return this.stop_reply
+		}
+	}
+}
+
+func (this *Debugger) IsInterrupted() bool {
+	select {
+	case packet, ok := <-this.packet_stream.PacketQ():
+		return !ok || packet == INTERRUPT
+	default:
+		return false
+	}
+}
+
+func (this *Debugger) StopReply(stop_event *StopEvent) string {
+	debug_event := stop_event.debug_event
+
+	this.general_thread_id = this.ThreadId(stop_event.dpu_index, debug_event.ThreadId())
+
+	if debug_event.DebugEventType() == logic.WATCHPOINT {
+		watchpoint := debug_event.Watchpoint()
+
+		var kind string
+		if watchpoint.WatchpointType() == logic.WRITE_WATCHPOINT {
+			kind = "watch"
+		} else if watchpoint.WatchpointType() == logic.READ_WATCHPOINT {
+			kind = "rwatch"
+		} else {
+			kind = "awatch"
+		}
+
+		return fmt.Sprintf(
+			"T05%s:%x;thread:%x;",
+			kind,
+			watchpoint.Address(),
+			this.general_thread_id,
+		)
+	}
+
+	return fmt.Sprintf("T05thread:%x;", this.general_thread_id)
+}
+
+func (this *Debugger) ReadRegisters() string {
+	_, thread, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	registers := ""
+	for index := 0; index <= config_loader.NumGpRegisters(); index++ {
+		registers += this.EncodeRegister(this.RegisterValue(thread, index))
+	}
+
+	return registers
+}
+
+func (this *Debugger) WriteRegisters(args string) string {
+	_, thread, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for index := 0; index <= config_loader.NumGpRegisters() && 8*(index+1) <= len(args); index++ {
+		value, ok := this.DecodeRegister(args[8*index : 8*(index+1)])
+		if !ok {
+			return "E01"
+		}
+
+		this.SetRegisterValue(thread, index, value)
+	}
+
+	return "OK"
+}
+
+func (this *Debugger) ReadRegister(args string) string {
+	_, thread, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	index, err := strconv.ParseInt(args, 16, 64)
+	if err != nil || !this.IsValidRegister(int(index)) {
+		return "E01"
+	}
+
+	return this.EncodeRegister(this.RegisterValue(thread, int(index)))
+}
+
+func (this *Debugger) WriteRegister(args string) string {
+	_, thread, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	words := strings.Split(args, "=")
+	if len(words) != 2 {
+		return "E01"
+	}
+
+	index, err := strconv.ParseInt(words[0], 16, 64)
+	if err != nil || !this.IsValidRegister(int(index)) {
+		return "E01"
+	}
+
+	value, ok := this.DecodeRegister(words[1])
+	if !ok {
+		return "E01"
+	}
+
+	this.SetRegisterValue(thread, int(index), value)
+	return "OK"
+}
+
+// NOTE: registers 0 to num_gp_registers - 1 are the general purpose registers and register
+// num_gp_registers is the PC
+func (this *Debugger) IsValidRegister(index int) bool {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return 0 <= index && index <= config_loader.NumGpRegisters()
+}
+
+func (this *Debugger) RegisterValue(thread *logic.Thread, index int) int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if index == config_loader.NumGpRegisters() {
+		return thread.RegFile().ReadPcReg()
+	}
+
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(index)
+
+	return thread.RegFile().ReadGpReg(gp_reg_descriptor, word.UNSIGNED)
+}
+
+func (this *Debugger) SetRegisterValue(thread *logic.Thread, index int, value int64) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if index == config_loader.NumGpRegisters() {
+		thread.RegFile().WritePcReg(value)
+		return
+	}
+
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(index)
+
+	thread.RegFile().WriteGpReg(gp_reg_descriptor, value)
+}
+
+func (this *Debugger) EncodeRegister(value int64) string {
+	bytes := make([]byte, 4)
+	for i := range bytes {
+		bytes[i] = byte(value >> (8 * i))
+	}
+	return hex.EncodeToString(bytes)
+}
+
+func (this *Debugger) DecodeRegister(value string) (int64, bool) {
+	bytes, err := hex.DecodeString(value)
+	if err != nil || len(bytes) != 4 {
+		return 0, false
+	}
+
+	decoded := int64(0)
+	for i, byte_ := range bytes {
+		decoded |= int64(byte_) << (8 * i)
+	}
+	return decoded, true
+}
+
+func (this *Debugger) ReadMemory(args string) string {
+	dpu_, _, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	address, size, ok := this.ParseAddressSize(args)
+	if !ok {
+		return "E01"
+	}
+
+	byte_stream, err := this.Peek(dpu_, address, size)
+	if err != nil {
+		return "E01"
+	}
+
+	bytes := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		bytes = append(bytes, byte_stream.Get(int(i)))
+	}
+	return hex.EncodeToString(bytes)
+}
+
+func (this *Debugger) WriteMemory(args string) string {
+	dpu_, _, found := this.CurrentThread()
+	if !found {
+		return "E01"
+	}
+
+	words := strings.Split(args, ":")
+	if len(words) != 2 {
+		return "E01"
+	}
+
+	address, size, ok := this.ParseAddressSize(words[0])
+	if !ok {
+		return "E01"
+	}
+
+	bytes, err := hex.DecodeString(words[1])
+	if err != nil || int64(len(bytes)) != size {
+		return "E01"
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for _, byte_ := range bytes {
+		byte_stream.Append(byte_)
+	}
+
+	if err := this.Poke(dpu_, address, byte_stream); err != nil {
+		return "E01"
+	}
+	return "OK"
+}
+
+func (this *Debugger) Peek(dpu_ *dpu.Dpu, address int64, size int64) (*encoding.ByteStream, error) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if this.IsIn(address, size, config_loader.AtomicOffset(), config_loader.AtomicSize()) {
+		// NOTE: each atomic bit is shown as 1 if a tasklet holds it and 0 otherwise
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+		for i := int64(0); i < size; i++ {
+			if dpu_.Atomic().Holder(address+i) != nil {
+				byte_stream.Append(1)
+			} else {
+				byte_stream.Append(0)
+			}
+		}
+		return byte_stream, nil
+	} else if this.IsIn(address, size, config_loader.WramOffset(), config_loader.WramSize()) {
+		return dpu_.Wram().Read(address, size), nil
+	} else if this.IsIn(address, size, config_loader.IramOffset(), config_loader.IramSize()) {
+		return dpu_.Iram().Peek(address, size), nil
+	} else if this.IsIn(address, size, config_loader.MramOffset(), config_loader.MramSize()) {
+		return dpu_.MemoryController().Peek(address, size), nil
+	} else {
+		return nil, errors.New("address range is not mapped")
+	}
+}
+
+func (this *Debugger) Poke(dpu_ *dpu.Dpu, address int64, byte_stream *encoding.ByteStream) error {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	size := byte_stream.Size()
+
+	if this.IsIn(address, size, config_loader.WramOffset(), config_loader.WramSize()) {
+		dpu_.Wram().Write(address, size, byte_stream)
+		return nil
+	} else if this.IsIn(address, size, config_loader.IramOffset(), config_loader.IramSize()) {
+		dpu_.Iram().Poke(address, byte_stream)
+		return nil
+	} else if this.IsIn(address, size, config_loader.MramOffset(), config_loader.MramSize()) {
+		dpu_.MemoryController().Poke(address, byte_stream)
+		return nil
+	} else {
+		return errors.New("address range is not writable")
+	}
+}
+
+func (this *Debugger) IsIn(address int64, size int64, offset int64, region_size int64) bool {
+	return offset <= address && address+size <= offset+region_size
+}
+
+func (this *Debugger) ParseAddressSize(args string) (int64, int64, bool) {
+	words := strings.Split(args, ",")
+	if len(words) != 2 {
+		return 0, 0, false
+	}
+
+	address, err := strconv.ParseInt(words[0], 16, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	size, err := strconv.ParseInt(words[1], 16, 64)
+	if err != nil || size < 0 {
+		return 0, 0, false
+	}
+
+	return address, size, true
+}
+
+func (this *Debugger) InsertPoint(args string) string {
+	return this.UpdatePoint(args, true)
+}
+
+func (this *Debugger) RemovePoint(args string) string {
+	return this.UpdatePoint(args, false)
+}
+
+// UpdatePoint handles Z/z packets. Software (0) and hardware (1) breakpoints are treated alike;
+// types 2, 3 and 4 are write, read and access watchpoints.
+func (this *Debugger) UpdatePoint(args string, is_insert bool) string {
+	words := strings.Split(args, ",")
+	if len(words) < 3 {
+		return "E01"
+	}
+
+	address, size, ok := this.ParseAddressSize(words[1] + "," + words[2])
+	if !ok {
+		return "E01"
+	}
+
+	for _, dpu_ := range this.simulator.Dpus() {
+		debug_unit := dpu_.DebugUnit()
+
+		if words[0] == "0" || words[0] == "1" {
+			if is_insert {
+				debug_unit.InsertBreakpoint(address)
+			} else {
+				debug_unit.RemoveBreakpoint(address)
+			}
+		} else if words[0] == "2" || words[0] == "3" || words[0] == "4" {
+			var watchpoint_type logic.WatchpointType
+			if words[0] == "2" {
+				watchpoint_type = logic.WRITE_WATCHPOINT
+			} else if words[0] == "3" {
+				watchpoint_type = logic.READ_WATCHPOINT
+			} else {
+				watchpoint_type = logic.ACCESS_WATCHPOINT
+			}
+
+			if size <= 0 {
+				return "E01"
+			}
+
+			if is_insert {
+				debug_unit.InsertWatchpoint(watchpoint_type, address, size)
+			} else {
+				debug_unit.RemoveWatchpoint(watchpoint_type, address, size)
+			}
+		} else {
+			return ""
+		}
+	}
+
+	return "OK"
+}
+
+func (this *Debugger) ClearDebugUnits() {
+	for _, dpu_ := range this.simulator.Dpus() {
+		dpu_.DebugUnit().Init()
+	}
+}
+
+func (this *Debugger) SetThread(args string) string {
+	if len(args) < 2 {
+		return "E01"
+	}
+
+	// NOTE: thread IDs 0 (any thread) and -1 (all threads) keep the current thread
+	if args[1:] == "0" || args[1:] == "-1" {
+		return "OK"
+	}
+
+	thread_id, err := strconv.ParseInt(args[1:], 16, 64)
+	if err != nil {
+		return "E01"
+	}
+
+	if args[0] == 'g' || args[0] == 'c' {
+		if _, _, found := this.FindThread(args[1:]); !found {
+			return "E01"
+		}
+		this.general_thread_id = int(thread_id)
+	}
+
+	return "OK"
+}
+
+func (this *Debugger) CheckThread(args string) string {
+	if _, _, found := this.FindThread(args); !found {
+		return "E01"
+	}
+	return "OK"
+}
+
+func (this *Debugger) ThreadId(dpu_index int, thread_id int) int {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return dpu_index*config_loader.MaxNumTasklets() + thread_id + 1
+}
+
+func (this *Debugger) DecodeThreadId(thread_id int) (int, int) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return (thread_id - 1) / config_loader.MaxNumTasklets(), (thread_id - 1) % config_loader.MaxNumTasklets()
+}
+
+func (this *Debugger) FindThread(thread_id_string string) (*dpu.Dpu, *logic.Thread, bool) {
+	thread_id, err := strconv.ParseInt(thread_id_string, 16, 64)
+	if err != nil || thread_id <= 0 {
+		return nil, nil, false
+	}
+
+	dpu_index, tasklet_id := this.DecodeThreadId(int(thread_id))
+
+	dpus := this.simulator.Dpus()
+	if dpu_index >= len(dpus) || tasklet_id >= len(dpus[dpu_index].Threads()) {
+		return nil, nil, false
+	}
+
+	return dpus[dpu_index], dpus[dpu_index].Threads()[tasklet_id], true
+}
+
+func (this *Debugger) CurrentThread() (*dpu.Dpu, *logic.Thread, bool) {
+	return this.FindThread(fmt.Sprintf("%x", this.general_thread_id))
+}
+
+func (this *Debugger) StringifyThread(dpu_ *dpu.Dpu, thread *logic.Thread) string {
+	pc := thread.RegFile().ReadPcReg()
+
+	return fmt.Sprintf(
+		"DPU%d-%d-%d tasklet %d %s at %s",
+		dpu_.ChannelId(),
+		dpu_.RankId(),
+		dpu_.DpuId(),
+		thread.ThreadId(),
+		thread.StringifyThreadState(),
+		this.simulator.SymbolTable().Symbolize(pc),
+	)
+}
+
+func (this *Debugger) ReadTargetXml(args string) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	lines := make([]string, 0)
+	lines = append(lines, "<?xml version=\"1.0\"?>")
+	lines = append(lines, "<!DOCTYPE target SYSTEM \"gdb-target.dtd\">")
+	lines = append(lines, "<target version=\"1.0\">")
+	lines = append(lines, "<feature name=\"org.upmem.dpu.core\">")
+	for index := 0; index < config_loader.NumGpRegisters(); index++ {
+		lines = append(
+			lines,
+			fmt.Sprintf("<reg name=\"r%d\" bitsize=\"32\" regnum=\"%d\" type=\"uint32\"/>", index, index),
+		)
+	}
+	lines = append(
+		lines,
+		fmt.Sprintf(
+			"<reg name=\"pc\" bitsize=\"32\" regnum=\"%d\" type=\"code_ptr\"/>",
+			config_loader.NumGpRegisters(),
+		),
+	)
+	lines = append(lines, "</feature>")
+	lines = append(lines, "</target>")
+
+	xml := strings.Join(lines, "\n")
+
+	offset, size, ok := this.ParseAddressSize(args)
+	if !ok {
+		return "E01"
+	}
+
+	if offset >= int64(len(xml)) {
+		return "l"
+	} else if offset+size >= int64(len(xml)) {
+		return "l" + xml[offset:]
+	} else {
+		return "m" + xml[offset:offset+size]
+	}
+}
+
+// RegisterInfo answers the LLDB qRegisterInfo query, which LLDB uses instead of target.xml.
+func (this *Debugger) RegisterInfo(args string) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	index, err := strconv.ParseInt(args, 16, 64)
+	if err != nil || !this.IsValidRegister(int(index)) {
+		return "E45"
+	}
+
+	if int(index) == config_loader.NumGpRegisters() {
+		return fmt.Sprintf(
+			"name:pc;bitsize:32;offset:%d;encoding:uint;format:hex;set:General Purpose Registers;generic:pc;",
+			4*index,
+		)
+	}
+
+	return fmt.Sprintf(
+		"name:r%d;bitsize:32;offset:%d;encoding:uint;format:hex;set:General Purpose Registers;",
+		index,
+		4*index,
+	)
+}
+
+// HandleMonitor runs a "monitor" command. The output is sent as console output (O) packets and
+// the command is answered with OK.
+//
+//	monitor break <symbol|address>   inserts a breakpoint at a linker label
+//	monitor delete <symbol|address>  removes it
+//	monitor threads                  prints the state and PC of every tasklet
+//	monitor symbol <address>         symbolizes an address
+func (this *Debugger) HandleMonitor(args string) string {
+	bytes, err := hex.DecodeString(args)
+	if err != nil {
+		return "E01"
+	}
+
+	words := strings.Fields(string(bytes))
+	if len(words) == 0 {
+		return "OK"
+	}
+
+	lines := make([]string, 0)
+
+	if (words[0] == "break" || words[0] == "delete") && len(words) == 2 {
+		address, found := this.ResolveAddress(words[1])
+		if !found {
+			lines = append(lines, fmt.Sprintf("no symbol \"%s\"", words[1]))
+		} else {
+			var reply string
+			if words[0] == "break" {
+				reply = this.InsertPoint(fmt.Sprintf("0,%x,0", address))
+				lines = append(lines, fmt.Sprintf("breakpoint at 0x%x (%s)", address, this.simulator.SymbolTable().Symbolize(address)))
+			} else {
+				reply = this.RemovePoint(fmt.Sprintf("0,%x,0", address))
+				lines = append(lines, fmt.Sprintf("deleted breakpoint at 0x%x", address))
+			}
+
+			if reply != "OK" {
+				return reply
+			}
+		}
+	} else if words[0] == "threads" {
+		for dpu_index, dpu_ := range this.simulator.Dpus() {
+			for _, thread := range dpu_.Threads() {
+				line := fmt.Sprintf(
+					"%x: %s",
+					this.ThreadId(dpu_index, thread.ThreadId()),
+					this.StringifyThread(dpu_, thread),
+				)
+				lines = append(lines, line)
+			}
+		}
+	} else if words[0] == "symbol" && len(words) == 2 {
+		address, found := this.ResolveAddress(words[1])
+		if !found {
+			lines = append(lines, fmt.Sprintf("invalid address \"%s\"", words[1]))
+		} else {
+			lines = append(lines, this.simulator.SymbolTable().Symbolize(address))
+		}
+	} else {
+		lines = append(lines, "commands: break <symbol|address>, delete <symbol|address>, threads, symbol <address>")
+	}
+
+	for _, line := range lines {
+		this.packet_stream.WritePacket("O" + hex.EncodeToString([]byte(line+"\n")))
+	}
+
+	return "OK"
+}
+
+func (this *Debugger) ResolveAddress(name string) (int64, bool) {
+	if this.simulator.SymbolTable().HasAddress(name) {
+		return this.simulator.SymbolTable().Address(name), true
+	}
+
+	address, err := strconv.ParseInt(name, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return address, true
+}
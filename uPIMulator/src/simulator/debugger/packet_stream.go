@@ -0,0 +1,162 @@
+package debugger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// INTERRUPT is delivered in place of a packet when the client sends a break (Ctrl-C).
+const INTERRUPT = "\x03"
+
+// PacketStream frames GDB remote serial protocol packets ($data#checksum) over a TCP connection.
+// Incoming packets are acknowledged and forwarded to a channel by a background goroutine so that
+// the debugger can poll for interrupts while the cycle model is running.
+type PacketStream struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mutex  sync.Mutex
+
+	no_ack bool
+
+	packet_q chan string
+}
+
+func (this *PacketStream) Init(conn net.Conn) {
+	this.conn = conn
+	this.reader = bufio.NewReader(conn)
+
+	this.no_ack = false
+
+	this.packet_q = make(chan string, 64)
+
+	go this.Receive()
+}
+
+func (this *PacketStream) Fini() {
+	this.conn.Close()
+}
+
+func (this *PacketStream) SetNoAck() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.no_ack = true
+}
+
+func (this *PacketStream) PacketQ() chan string {
+	return this.packet_q
+}
+
+func (this *PacketStream) Receive() {
+	defer close(this.packet_q)
+
+	for {
+		c, err := this.reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		if c == INTERRUPT[0] {
+			this.packet_q <- INTERRUPT
+		} else if c == '$' {
+			packet, err := this.ReadPacket()
+			if err != nil {
+				return
+			}
+
+			if packet != nil {
+				this.WriteRaw("+")
+				this.packet_q <- *packet
+			} else {
+				this.WriteRaw("-")
+			}
+		}
+	}
+}
+
+// ReadPacket reads the body of a packet whose leading '$' has already been consumed. It returns
+// nil if the checksum does not match.
+func (this *PacketStream) ReadPacket() (*string, error) {
+	data := make([]byte, 0)
+	checksum := uint8(0)
+
+	for {
+		c, err := this.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if c == '#' {
+			break
+		}
+
+		checksum += c
+
+		if c == '}' {
+			escaped, err := this.reader.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+
+			checksum += escaped
+			data = append(data, escaped^0x20)
+		} else {
+			data = append(data, c)
+		}
+	}
+
+	checksum_chars := make([]byte, 2)
+	for i := range checksum_chars {
+		c, err := this.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		checksum_chars[i] = c
+	}
+
+	expected_checksum, err := strconv.ParseUint(string(checksum_chars), 16, 8)
+	if err != nil || uint8(expected_checksum) != checksum {
+		return nil, nil
+	}
+
+	packet := string(data)
+	return &packet, nil
+}
+
+func (this *PacketStream) WritePacket(data string) {
+	escaped := make([]byte, 0)
+	checksum := uint8(0)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if c == '#' || c == '$' || c == '}' || c == '*' {
+			escaped = append(escaped, '}', c^0x20)
+			checksum += '}' + (c ^ 0x20)
+		} else {
+			escaped = append(escaped, c)
+			checksum += c
+		}
+	}
+
+	this.WriteRaw(fmt.Sprintf("$%s#%02x", string(escaped), checksum))
+}
+
+func (this *PacketStream) WriteRaw(data string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.no_ack && (data == "+" || data == "-") {
+		return
+	}
+
+	_, err := this.conn.Write([]byte(data))
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		fmt.Printf("debugger: %v\n", err)
+	}
+}
@@ -0,0 +1,194 @@
+package debugger
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+type SymbolSection struct {
+	name    string
+	address int64
+	size    int64
+	flags   elf.SectionFlag
+}
+
+// SymbolFile is an ELF file that holds only a symbol table, so that a client can resolve the
+// linker's labels (e.g., "break main" or "info symbol") after "file" or "symbol-file". Its IRAM,
+// WRAM and MRAM sections have no contents; the client reads memory from the target.
+type SymbolFile struct {
+	sections []*SymbolSection
+	symbols  []*symbol.Symbol
+}
+
+func (this *SymbolFile) Init(symbol_table *symbol.SymbolTable) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.sections = make([]*SymbolSection, 0)
+	this.AddSection(
+		".text",
+		config_loader.IramOffset(),
+		config_loader.IramSize(),
+		elf.SHF_ALLOC|elf.SHF_EXECINSTR,
+	)
+	this.AddSection(
+		".data",
+		config_loader.WramOffset(),
+		config_loader.WramSize(),
+		elf.SHF_ALLOC|elf.SHF_WRITE,
+	)
+	this.AddSection(
+		".mram",
+		config_loader.MramOffset(),
+		config_loader.MramSize(),
+		elf.SHF_ALLOC|elf.SHF_WRITE,
+	)
+
+	this.symbols = symbol_table.Symbols()
+}
+
+func (this *SymbolFile) AddSection(name string, address int64, size int64, flags elf.SectionFlag) {
+	section := new(SymbolSection)
+	section.name = name
+	section.address = address
+	section.size = size
+	section.flags = flags
+
+	this.sections = append(this.sections, section)
+}
+
+// Write lays out the file as the ELF header, the symbol table, the string tables and the section
+// headers: null, the memory sections, .symtab, .strtab and .shstrtab.
+func (this *SymbolFile) Write(path string) {
+	symtab := new(bytes.Buffer)
+	strtab := new(bytes.Buffer)
+	shstrtab := new(bytes.Buffer)
+
+	strtab.WriteByte(0)
+	shstrtab.WriteByte(0)
+
+	binary.Write(symtab, binary.LittleEndian, elf.Sym32{})
+	for _, symbol_ := range this.symbols {
+		section_index, symbol_type := this.SectionIndex(symbol_.Address())
+
+		sym := elf.Sym32{
+			Name:  uint32(strtab.Len()),
+			Value: uint32(symbol_.Address()),
+			Info:  elf.ST_INFO(elf.STB_GLOBAL, symbol_type),
+			Shndx: section_index,
+		}
+		binary.Write(symtab, binary.LittleEndian, sym)
+
+		strtab.WriteString(symbol_.Name())
+		strtab.WriteByte(0)
+	}
+
+	section_names := make([]uint32, 0)
+	for _, section := range this.sections {
+		section_names = append(section_names, this.AddName(shstrtab, section.name))
+	}
+	symtab_name := this.AddName(shstrtab, ".symtab")
+	strtab_name := this.AddName(shstrtab, ".strtab")
+	shstrtab_name := this.AddName(shstrtab, ".shstrtab")
+
+	header_size := binary.Size(elf.Header32{})
+	symtab_offset := header_size
+	strtab_offset := symtab_offset + symtab.Len()
+	shstrtab_offset := strtab_offset + strtab.Len()
+	section_headers_offset := (shstrtab_offset + shstrtab.Len() + 3) / 4 * 4
+
+	section_headers := make([]elf.Section32, 0)
+	section_headers = append(section_headers, elf.Section32{})
+	for i, section := range this.sections {
+		section_headers = append(section_headers, elf.Section32{
+			Name:      section_names[i],
+			Type:      uint32(elf.SHT_NOBITS),
+			Flags:     uint32(section.flags),
+			Addr:      uint32(section.address),
+			Off:       uint32(header_size),
+			Size:      uint32(section.size),
+			Addralign: 1,
+		})
+	}
+
+	symtab_index := len(section_headers)
+	section_headers = append(section_headers, elf.Section32{
+		Name:      symtab_name,
+		Type:      uint32(elf.SHT_SYMTAB),
+		Off:       uint32(symtab_offset),
+		Size:      uint32(symtab.Len()),
+		Link:      uint32(symtab_index + 1),
+		Info:      1,
+		Addralign: 4,
+		Entsize:   uint32(binary.Size(elf.Sym32{})),
+	})
+	section_headers = append(section_headers, elf.Section32{
+		Name:      strtab_name,
+		Type:      uint32(elf.SHT_STRTAB),
+		Off:       uint32(strtab_offset),
+		Size:      uint32(strtab.Len()),
+		Addralign: 1,
+	})
+	section_headers = append(section_headers, elf.Section32{
+		Name:      shstrtab_name,
+		Type:      uint32(elf.SHT_STRTAB),
+		Off:       uint32(shstrtab_offset),
+		Size:      uint32(shstrtab.Len()),
+		Addralign: 1,
+	})
+
+	header := elf.Header32{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(elf.EM_NONE),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     uint32(section_headers_offset),
+		Ehsize:    uint16(header_size),
+		Shentsize: uint16(binary.Size(elf.Section32{})),
+		Shnum:     uint16(len(section_headers)),
+		Shstrndx:  uint16(len(section_headers) - 1),
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	file := new(bytes.Buffer)
+	binary.Write(file, binary.LittleEndian, header)
+	file.Write(symtab.Bytes())
+	file.Write(strtab.Bytes())
+	file.Write(shstrtab.Bytes())
+	for file.Len() < section_headers_offset {
+		file.WriteByte(0)
+	}
+	binary.Write(file, binary.LittleEndian, section_headers)
+
+	if err := os.WriteFile(path, file.Bytes(), 0644); err != nil {
+		panic(err)
+	}
+}
+
+// SectionIndex returns the section that holds address, with functions in IRAM and objects
+// elsewhere; an address in no section (e.g., an atomic bit) is absolute.
+func (this *SymbolFile) SectionIndex(address int64) (uint16, elf.SymType) {
+	for i, section := range this.sections {
+		if section.address <= address && address < section.address+section.size {
+			if section.flags&elf.SHF_EXECINSTR != 0 {
+				return uint16(i + 1), elf.STT_FUNC
+			}
+			return uint16(i + 1), elf.STT_OBJECT
+		}
+	}
+
+	return uint16(elf.SHN_ABS), elf.STT_NOTYPE
+}
+
+func (this *SymbolFile) AddName(shstrtab *bytes.Buffer, name string) uint32 {
+	offset := uint32(shstrtab.Len())
+	shstrtab.WriteString(name)
+	shstrtab.WriteByte(0)
+	return offset
+}
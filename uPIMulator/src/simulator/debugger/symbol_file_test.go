@@ -0,0 +1,107 @@
+package debugger_test
+
+import (
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/simulator/debugger"
+	"uPIMulator/src/simulator/symbol"
+)
+
+func NewSymbolTable(t *testing.T, addresses string) *symbol.SymbolTable {
+	dirpath := t.TempDir()
+
+	addresses_path := filepath.Join(dirpath, "addresses.txt")
+	if err := os.WriteFile(addresses_path, []byte(addresses), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	values_path := filepath.Join(dirpath, "values.txt")
+	if err := os.WriteFile(values_path, []byte("NR_TASKLETS: 16\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	symbol_table := new(symbol.SymbolTable)
+	symbol_table.Init(addresses_path, values_path)
+	return symbol_table
+}
+
+func TestSymbolFileWrite(t *testing.T) {
+	addresses := "main: 393224\n.LBB0_1: 393232\n__bootstrap: 393216\nbuffer: 1024\n" +
+		"DPU_MRAM_HEAP_POINTER_NAME: 134217728\natomic_bit: 8\n"
+	symbol_table := NewSymbolTable(t, addresses)
+
+	path := filepath.Join(t.TempDir(), "symbols.elf")
+
+	symbol_file := new(debugger.SymbolFile)
+	symbol_file.Init(symbol_table)
+	symbol_file.Write(path)
+
+	file, err := elf.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if file.Class != elf.ELFCLASS32 || file.ByteOrder.String() != "LittleEndian" {
+		t.Errorf(
+			"class = %v, byte order = %v, want ELFCLASS32, LittleEndian",
+			file.Class,
+			file.ByteOrder,
+		)
+	}
+
+	symbols, err := file.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		want_value   uint64
+		want_type    elf.SymType
+		want_section string
+	}{
+		{"__bootstrap", 393216, elf.STT_FUNC, ".text"},
+		{"main", 393224, elf.STT_FUNC, ".text"},
+		{"buffer", 1024, elf.STT_OBJECT, ".data"},
+		{"DPU_MRAM_HEAP_POINTER_NAME", 134217728, elf.STT_OBJECT, ".mram"},
+		{"atomic_bit", 8, elf.STT_NOTYPE, ""},
+	}
+
+	if len(symbols) != len(tests) {
+		t.Fatalf("len(Symbols()) = %d, want %d without local labels", len(symbols), len(tests))
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, symbol_ := range symbols {
+				if symbol_.Name != test.name {
+					continue
+				}
+
+				if symbol_.Value != test.want_value {
+					t.Errorf("value = %d, want %d", symbol_.Value, test.want_value)
+				}
+
+				if symbol_type := elf.ST_TYPE(symbol_.Info); symbol_type != test.want_type {
+					t.Errorf("type = %v, want %v", symbol_type, test.want_type)
+				}
+
+				section := ""
+				if symbol_.Section < elf.SectionIndex(len(file.Sections)) {
+					section = file.Sections[symbol_.Section].Name
+				}
+
+				if section != test.want_section {
+					t.Errorf("section = %q, want %q", section, test.want_section)
+				}
+
+				return
+			}
+
+			t.Errorf("symbol is not found")
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"uPIMulator/src/simulator/dpu/dram"
 	"uPIMulator/src/simulator/dpu/logic"
 	"uPIMulator/src/simulator/dpu/sram"
+	"uPIMulator/src/simulator/symbol"
 )
 
 type Dpu struct {
@@ -27,6 +28,15 @@ type Dpu struct {
 	memory_controller *dram.MemoryController
 	dma               *logic.Dma
 	logic             *logic.Logic
+	debug_unit        *logic.DebugUnit
+	sanitizer         *logic.Sanitizer
+	dma_checker       *logic.DmaChecker
+	race_detector     *logic.RaceDetector
+	dma_trace         *dram.DmaTrace
+	hammer_tracker    *dram.HammerTracker
+	lock_profiler     *logic.LockProfiler
+
+	soft_float_profiler *logic.SoftFloatProfiler
 
 	stat_factory *misc.StatFactory
 }
@@ -112,6 +122,71 @@ func (this *Dpu) Init(
 	this.logic.ConnectOperandCollector(this.operand_collector)
 	this.logic.ConnectDma(this.dma)
 
+	this.lock_profiler = new(logic.LockProfiler)
+	this.lock_profiler.Init(channel_id, rank_id, dpu_id)
+	this.logic.ConnectLockProfiler(this.lock_profiler)
+
+	this.soft_float_profiler = new(logic.SoftFloatProfiler)
+	this.soft_float_profiler.Init(channel_id, rank_id, dpu_id)
+	this.logic.ConnectSoftFloatProfiler(this.soft_float_profiler)
+
+	if global.Sanitize {
+		this.sanitizer = new(logic.Sanitizer)
+		this.sanitizer.Init(channel_id, rank_id, dpu_id)
+		this.sanitizer.ConnectOperandCollector(this.operand_collector)
+		this.logic.ConnectSanitizer(this.sanitizer)
+		this.dma.ConnectSanitizer(this.sanitizer)
+	} else {
+		this.sanitizer = nil
+	}
+
+	if global.DmaCheck != "none" {
+		this.dma_checker = new(logic.DmaChecker)
+		this.dma_checker.Init(channel_id, rank_id, dpu_id)
+		this.logic.ConnectDmaChecker(this.dma_checker)
+	} else {
+		this.dma_checker = nil
+	}
+
+	if global.DetectRaces {
+		this.race_detector = new(logic.RaceDetector)
+		this.race_detector.Init(channel_id, rank_id, dpu_id)
+		this.logic.ConnectRaceDetector(this.race_detector)
+	} else {
+		this.race_detector = nil
+	}
+
+	if global.RecordDmaTrace {
+		this.dma_trace = new(dram.DmaTrace)
+		this.dma_trace.Init()
+		this.dma.ConnectDmaTrace(this.dma_trace)
+	} else {
+		this.dma_trace = nil
+	}
+
+	if global.HammerThreshold > 0 {
+		this.hammer_tracker = new(dram.HammerTracker)
+		this.hammer_tracker.Init(
+			channel_id,
+			rank_id,
+			dpu_id,
+			this.memory_controller.MemoryScheduler().AddressMapper(),
+		)
+		this.hammer_tracker.ConnectMram(this.mram)
+		this.memory_controller.ConnectHammerTracker(this.hammer_tracker)
+	} else {
+		this.hammer_tracker = nil
+	}
+
+	if global.DebugPort != 0 {
+		this.debug_unit = new(logic.DebugUnit)
+		this.debug_unit.Init()
+		this.logic.ConnectDebugUnit(this.debug_unit)
+		this.thread_scheduler.ConnectDebugUnit(this.debug_unit)
+	} else {
+		this.debug_unit = nil
+	}
+
 	name := fmt.Sprintf("DPU%d-%d-%d", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
@@ -132,6 +207,28 @@ func (this *Dpu) Fini() {
 
 	this.logic.Fini()
 	this.dma.Fini()
+	this.lock_profiler.Fini()
+	this.soft_float_profiler.Fini()
+
+	if this.debug_unit != nil {
+		this.debug_unit.Fini()
+	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.Fini()
+	}
+
+	if this.dma_checker != nil {
+		this.dma_checker.Fini()
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.Fini()
+	}
+
+	if this.hammer_tracker != nil {
+		this.hammer_tracker.Fini()
+	}
 }
 
 func (this *Dpu) ChannelId() int {
@@ -162,6 +259,50 @@ func (this *Dpu) Dma() *logic.Dma {
 	return this.dma
 }
 
+func (this *Dpu) Atomic() *sram.Atomic {
+	return this.atomic
+}
+
+func (this *Dpu) Iram() *sram.Iram {
+	return this.iram
+}
+
+func (this *Dpu) Wram() *sram.Wram {
+	return this.wram
+}
+
+func (this *Dpu) LockProfiler() *logic.LockProfiler {
+	return this.lock_profiler
+}
+
+func (this *Dpu) SoftFloatProfiler() *logic.SoftFloatProfiler {
+	return this.soft_float_profiler
+}
+
+func (this *Dpu) DebugUnit() *logic.DebugUnit {
+	return this.debug_unit
+}
+
+func (this *Dpu) Sanitizer() *logic.Sanitizer {
+	return this.sanitizer
+}
+
+func (this *Dpu) DmaChecker() *logic.DmaChecker {
+	return this.dma_checker
+}
+
+func (this *Dpu) RaceDetector() *logic.RaceDetector {
+	return this.race_detector
+}
+
+func (this *Dpu) DmaTrace() *dram.DmaTrace {
+	return this.dma_trace
+}
+
+func (this *Dpu) HammerTracker() *dram.HammerTracker {
+	return this.hammer_tracker
+}
+
 func (this *Dpu) Threads() []*logic.Thread {
 	return this.threads
 }
@@ -170,7 +311,38 @@ func (this *Dpu) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *Dpu) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	this.lock_profiler.ConnectSymbolTable(symbol_table)
+	this.soft_float_profiler.ConnectSymbolTable(symbol_table)
+
+	if this.sanitizer != nil {
+		this.sanitizer.ConnectSymbolTable(symbol_table)
+	}
+
+	if this.dma_checker != nil {
+		this.dma_checker.ConnectSymbolTable(symbol_table)
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.ConnectSymbolTable(symbol_table)
+	}
+
+	if this.hammer_tracker != nil {
+		this.hammer_tracker.ConnectSymbolTable(symbol_table)
+	}
+}
+
 func (this *Dpu) Boot() {
+	this.lock_profiler.Boot()
+
+	if this.sanitizer != nil {
+		this.sanitizer.Boot()
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.Boot()
+	}
+
 	this.thread_scheduler.Boot(0)
 }
 
@@ -183,6 +355,18 @@ func (this *Dpu) IsZombie() bool {
 	return this.logic.IsEmpty() && this.memory_controller.IsEmpty()
 }
 
+// HasOutstandingDma returns true if a tasklet waits for its DMA. Refreshes and precharges the
+// memory controller issues on its own do not count.
+func (this *Dpu) HasOutstandingDma() bool {
+	for _, thread := range this.threads {
+		if thread.ThreadState() == logic.BLOCK {
+			return true
+		}
+	}
+
+	return !this.dma.IsEmpty()
+}
+
 func (this *Dpu) Cycle() {
 	for _, thread := range this.threads {
 		thread.IncrementIssueCycle()
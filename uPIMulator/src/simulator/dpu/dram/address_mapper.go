@@ -0,0 +1,67 @@
+package dram
+
+import (
+	"errors"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+)
+
+// AddressMapper maps an MRAM address to the bank that holds it. A row is always a whole wordline,
+// so the mapping only decides how consecutive wordlines are spread over the banks:
+//
+//	ro_ba_co     consecutive wordlines go to consecutive banks
+//	ro_bg_ba_co  consecutive wordlines go to consecutive bank groups first
+//	ba_ro_co     each bank holds a contiguous 1/num_banks of MRAM
+type AddressMapper struct {
+	mram_offset     int64
+	num_wordlines   int64
+	num_banks       int
+	num_bank_groups int
+}
+
+func (this *AddressMapper) Init() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.mram_offset = config_loader.MramOffset()
+	this.num_wordlines = config_loader.MramSize() / global.WordlineSize
+	this.num_banks = global.NumBanks
+	this.num_bank_groups = global.NumBankGroups
+
+	if this.num_banks <= 0 {
+		err := errors.New("number of banks <= 0")
+		panic(err)
+	} else if this.num_bank_groups <= 0 || this.num_banks%this.num_bank_groups != 0 {
+		err := errors.New("number of bank groups does not divide number of banks")
+		panic(err)
+	}
+}
+
+func (this *AddressMapper) NumBanks() int {
+	return this.num_banks
+}
+
+func (this *AddressMapper) Bank(address int64) int {
+	wordline := (address - this.mram_offset) / global.WordlineSize
+	num_banks := int64(this.num_banks)
+	num_bank_groups := int64(this.num_bank_groups)
+	num_banks_per_group := num_banks / num_bank_groups
+
+	if global.AddressMapping == "ro_ba_co" {
+		return int(wordline % num_banks)
+	} else if global.AddressMapping == "ro_bg_ba_co" {
+		bank_group := wordline % num_bank_groups
+		bank := (wordline / num_bank_groups) % num_banks_per_group
+		return int(bank_group*num_banks_per_group + bank)
+	} else if global.AddressMapping == "ba_ro_co" {
+		num_wordlines_per_bank := (this.num_wordlines + num_banks - 1) / num_banks
+		return int(wordline / num_wordlines_per_bank)
+	} else {
+		err := errors.New("address mapping is not valid")
+		panic(err)
+	}
+}
+
+func (this *AddressMapper) BankGroup(bank int) int {
+	return bank / (this.num_banks / this.num_bank_groups)
+}
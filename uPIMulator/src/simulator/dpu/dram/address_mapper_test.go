@@ -0,0 +1,142 @@
+package dram_test
+
+import (
+	"testing"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu/dram"
+)
+
+const wordline_size = 1024
+
+func NewAddressMapper(
+	address_mapping string,
+	num_banks int,
+	num_bank_groups int,
+) *dram.AddressMapper {
+	global.WordlineSize = wordline_size
+	global.NumBanks = num_banks
+	global.NumBankGroups = num_bank_groups
+	global.AddressMapping = address_mapping
+
+	address_mapper := new(dram.AddressMapper)
+	address_mapper.Init()
+	return address_mapper
+}
+
+func WordlineAddress(wordline int64) int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return config_loader.MramOffset() + wordline*wordline_size
+}
+
+func TestAddressMapperBank(t *testing.T) {
+	// NOTE: 64 MB of MRAM holds 65536 wordlines of 1 KB, so each of 4 banks holds 16384 with ba_ro_co
+	tests := []struct {
+		name            string
+		address_mapping string
+		num_banks       int
+		num_bank_groups int
+		wordline        int64
+		want_bank       int
+		want_bank_group int
+	}{
+		{"single bank", "ro_ba_co", 1, 1, 12345, 0, 0},
+		{"ro_ba_co first", "ro_ba_co", 4, 2, 0, 0, 0},
+		{"ro_ba_co next bank", "ro_ba_co", 4, 2, 1, 1, 0},
+		{"ro_ba_co other group", "ro_ba_co", 4, 2, 2, 2, 1},
+		{"ro_ba_co wraps", "ro_ba_co", 4, 2, 5, 1, 0},
+		{"ro_bg_ba_co first", "ro_bg_ba_co", 4, 2, 0, 0, 0},
+		{"ro_bg_ba_co next group", "ro_bg_ba_co", 4, 2, 1, 2, 1},
+		{"ro_bg_ba_co back to first group", "ro_bg_ba_co", 4, 2, 2, 1, 0},
+		{"ro_bg_ba_co last", "ro_bg_ba_co", 4, 2, 3, 3, 1},
+		{"ro_bg_ba_co wraps", "ro_bg_ba_co", 4, 2, 4, 0, 0},
+		{"ba_ro_co first", "ba_ro_co", 4, 2, 0, 0, 0},
+		{"ba_ro_co end of bank 0", "ba_ro_co", 4, 2, 16383, 0, 0},
+		{"ba_ro_co start of bank 1", "ba_ro_co", 4, 2, 16384, 1, 0},
+		{"ba_ro_co last", "ba_ro_co", 4, 2, 65535, 3, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address_mapper := NewAddressMapper(test.address_mapping, test.num_banks, test.num_bank_groups)
+
+			bank := address_mapper.Bank(WordlineAddress(test.wordline))
+			if bank != test.want_bank {
+				t.Errorf("Bank() = %d, want %d", bank, test.want_bank)
+			}
+
+			if bank_group := address_mapper.BankGroup(bank); bank_group != test.want_bank_group {
+				t.Errorf("BankGroup(%d) = %d, want %d", bank, bank_group, test.want_bank_group)
+			}
+		})
+	}
+}
+
+// TestAddressMapperRowSplit checks that a row is a whole wordline: the bytes of a wordline share
+// its bank and row, and the next byte starts the next row.
+func TestAddressMapperRowSplit(t *testing.T) {
+	tests := []struct {
+		name            string
+		address_mapping string
+		offset          int64
+		want_same_row   bool
+		want_same_bank  bool
+	}{
+		{"ro_ba_co same wordline", "ro_ba_co", wordline_size - 1, true, true},
+		{"ro_ba_co next wordline", "ro_ba_co", wordline_size, false, false},
+		{"ro_bg_ba_co same wordline", "ro_bg_ba_co", wordline_size - 1, true, true},
+		{"ro_bg_ba_co next wordline", "ro_bg_ba_co", wordline_size, false, false},
+		{"ba_ro_co same wordline", "ba_ro_co", wordline_size - 1, true, true},
+		{"ba_ro_co next wordline", "ba_ro_co", wordline_size, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address_mapper := NewAddressMapper(test.address_mapping, 4, 2)
+			memory_scheduler := new(dram.MemoryScheduler)
+
+			for _, wordline := range []int64{0, 7, 100} {
+				address := WordlineAddress(wordline)
+
+				is_same_row := memory_scheduler.WordlineAddress(address) ==
+					memory_scheduler.WordlineAddress(address+test.offset)
+				if is_same_row != test.want_same_row {
+					t.Errorf("wordline %d: same row = %v, want %v", wordline, is_same_row, test.want_same_row)
+				}
+
+				is_same_bank := address_mapper.Bank(address) == address_mapper.Bank(address+test.offset)
+				if is_same_bank != test.want_same_bank {
+					t.Errorf("wordline %d: same bank = %v, want %v", wordline, is_same_bank, test.want_same_bank)
+				}
+			}
+		})
+	}
+}
+
+func TestAddressMapperInit(t *testing.T) {
+	tests := []struct {
+		name            string
+		num_banks       int
+		num_bank_groups int
+		want_panic      bool
+	}{
+		{"valid", 4, 2, false},
+		{"no bank", 0, 1, true},
+		{"no bank group", 4, 0, true},
+		{"bank groups do not divide banks", 4, 3, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			NewAddressMapper("ro_ba_co", test.num_banks, test.num_bank_groups)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package dram
+
+import (
+	"uPIMulator/src/global"
+)
+
+// DataBus is the data bus the banks of a multi-bank MRAM share. A column access may start t_bl
+// cycles after the previous one and, in addition, t_ccd_l cycles after one to the same bank group
+// or t_ccd_s cycles after one to another bank group.
+type DataBus struct {
+	cycles int64
+
+	last_cycle      *int64
+	last_bank_group int
+}
+
+func (this *DataBus) Init() {
+	this.cycles = 0
+
+	this.last_cycle = nil
+	this.last_bank_group = 0
+}
+
+func (this *DataBus) CanIssue(bank_group int) bool {
+	if this.last_cycle == nil {
+		return true
+	}
+
+	t_ccd := global.TCcdS
+	if bank_group == this.last_bank_group {
+		t_ccd = global.TCcdL
+	}
+
+	return this.cycles-*this.last_cycle >= this.Max(global.TBl, t_ccd)
+}
+
+func (this *DataBus) Issue(bank_group int) {
+	this.last_cycle = new(int64)
+	*this.last_cycle = this.cycles
+
+	this.last_bank_group = bank_group
+}
+
+func (this *DataBus) Cycle() {
+	this.cycles++
+}
+
+func (this *DataBus) Max(x int64, y int64) int64 {
+	if x >= y {
+		return x
+	} else {
+		return y
+	}
+}
@@ -15,7 +15,14 @@ type DmaCommand struct {
 	byte_stream *encoding.ByteStream
 	acks        []bool
 
+	thread_id   *int
+	pc          *int64
 	instruction *instruction.Instruction
+
+	issue_cycle      *int64
+	enter_cycle      *int64
+	first_byte_cycle *int64
+	complete_cycle   *int64
 }
 
 func (this *DmaCommand) InitReadFromMram(mram_address int64, size int64) {
@@ -38,7 +45,14 @@ func (this *DmaCommand) InitReadFromMram(mram_address int64, size int64) {
 		this.acks = append(this.acks, false)
 	}
 
+	this.thread_id = nil
+	this.pc = nil
 	this.instruction = nil
+
+	this.issue_cycle = nil
+	this.enter_cycle = nil
+	this.first_byte_cycle = nil
+	this.complete_cycle = nil
 }
 
 func (this *DmaCommand) InitWriteToMram(
@@ -60,13 +74,21 @@ func (this *DmaCommand) InitWriteToMram(
 		this.acks = append(this.acks, false)
 	}
 
+	this.thread_id = nil
+	this.pc = nil
 	this.instruction = nil
+
+	this.issue_cycle = nil
+	this.enter_cycle = nil
+	this.first_byte_cycle = nil
+	this.complete_cycle = nil
 }
 
 func (this *DmaCommand) InitReadFromMramToWram(
 	wram_address int64,
 	mram_address int64,
 	size int64,
+	thread_id int,
 	instruction_ *instruction.Instruction,
 ) {
 	if instruction_.OpCode() != instruction.LDMA {
@@ -95,7 +117,16 @@ func (this *DmaCommand) InitReadFromMramToWram(
 		this.acks = append(this.acks, false)
 	}
 
+	this.thread_id = new(int)
+	*this.thread_id = thread_id
+
+	this.pc = nil
 	this.instruction = instruction_
+
+	this.issue_cycle = nil
+	this.enter_cycle = nil
+	this.first_byte_cycle = nil
+	this.complete_cycle = nil
 }
 
 func (this *DmaCommand) InitWriteToMramFromWram(
@@ -103,6 +134,7 @@ func (this *DmaCommand) InitWriteToMramFromWram(
 	mram_address int64,
 	size int64,
 	byte_stream *encoding.ByteStream,
+	thread_id int,
 	instruction_ *instruction.Instruction,
 ) {
 	if instruction_.OpCode() != instruction.SDMA {
@@ -126,7 +158,16 @@ func (this *DmaCommand) InitWriteToMramFromWram(
 		this.acks = append(this.acks, false)
 	}
 
+	this.thread_id = new(int)
+	*this.thread_id = thread_id
+
+	this.pc = nil
 	this.instruction = instruction_
+
+	this.issue_cycle = nil
+	this.enter_cycle = nil
+	this.first_byte_cycle = nil
+	this.complete_cycle = nil
 }
 
 func (this *DmaCommand) Fini() {
@@ -162,6 +203,44 @@ func (this *DmaCommand) Size() int64 {
 	return this.size
 }
 
+// SetThreadId attributes a DMA command of the host to a tasklet, e.g. when replaying a DMA trace.
+func (this *DmaCommand) SetThreadId(thread_id int) {
+	this.thread_id = new(int)
+	*this.thread_id = thread_id
+}
+
+func (this *DmaCommand) HasThreadId() bool {
+	return this.thread_id != nil
+}
+
+func (this *DmaCommand) ThreadId() int {
+	if this.thread_id == nil {
+		err := errors.New("DMA command does not have a thread ID")
+		panic(err)
+	}
+
+	return *this.thread_id
+}
+
+// SetPc records the IRAM address of the ldma/sdma that issued the DMA command.
+func (this *DmaCommand) SetPc(pc int64) {
+	this.pc = new(int64)
+	*this.pc = pc
+}
+
+func (this *DmaCommand) HasPc() bool {
+	return this.pc != nil
+}
+
+func (this *DmaCommand) Pc() int64 {
+	if this.pc == nil {
+		err := errors.New("DMA command does not have a PC")
+		panic(err)
+	}
+
+	return *this.pc
+}
+
 func (this *DmaCommand) HasInstruction() bool {
 	return this.instruction != nil
 }
@@ -170,6 +249,71 @@ func (this *DmaCommand) Instruction() *instruction.Instruction {
 	return this.instruction
 }
 
+// NOTE: the cycles below are memory cycles of the memory controller that serves the DMA command
+
+func (this *DmaCommand) HasIssueCycle() bool {
+	return this.issue_cycle != nil
+}
+
+func (this *DmaCommand) IssueCycle() int64 {
+	if this.issue_cycle == nil {
+		err := errors.New("DMA command does not have an issue cycle")
+		panic(err)
+	}
+
+	return *this.issue_cycle
+}
+
+func (this *DmaCommand) SetIssueCycle(cycle int64) {
+	this.issue_cycle = new(int64)
+	*this.issue_cycle = cycle
+}
+
+func (this *DmaCommand) EnterCycle() int64 {
+	if this.enter_cycle == nil {
+		err := errors.New("DMA command does not have an enter cycle")
+		panic(err)
+	}
+
+	return *this.enter_cycle
+}
+
+func (this *DmaCommand) SetEnterCycle(cycle int64) {
+	this.enter_cycle = new(int64)
+	*this.enter_cycle = cycle
+}
+
+func (this *DmaCommand) FirstByteCycle() int64 {
+	if this.first_byte_cycle == nil {
+		err := errors.New("DMA command does not have a first byte cycle")
+		panic(err)
+	}
+
+	return *this.first_byte_cycle
+}
+
+// SetFirstByteCycle keeps the cycle of the first memory command served for the DMA command.
+func (this *DmaCommand) SetFirstByteCycle(cycle int64) {
+	if this.first_byte_cycle == nil {
+		this.first_byte_cycle = new(int64)
+		*this.first_byte_cycle = cycle
+	}
+}
+
+func (this *DmaCommand) CompleteCycle() int64 {
+	if this.complete_cycle == nil {
+		err := errors.New("DMA command does not have a complete cycle")
+		panic(err)
+	}
+
+	return *this.complete_cycle
+}
+
+func (this *DmaCommand) SetCompleteCycle(cycle int64) {
+	this.complete_cycle = new(int64)
+	*this.complete_cycle = cycle
+}
+
 func (this *DmaCommand) ByteStream(mram_address int64, size int64) *encoding.ByteStream {
 	byte_stream := new(encoding.ByteStream)
 	byte_stream.Init()
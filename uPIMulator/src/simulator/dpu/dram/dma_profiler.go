@@ -0,0 +1,130 @@
+package dram
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+)
+
+// DmaProfiler records the latency of every DMA command served by a memory controller, in memory
+// cycles, and the MRAM bandwidth achieved. A DMA command is timestamped when a tasklet issues it
+// to the DMA engine, when it enters the memory controller, when its first memory command is served
+// by a row buffer and when it completes. Latencies are split by read/write and by transfer size.
+type DmaProfiler struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	histograms map[string]*misc.Histogram
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *DmaProfiler) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.histograms = make(map[string]*misc.Histogram, 0)
+
+	name := fmt.Sprintf("DmaProfiler[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *DmaProfiler) Fini() {
+}
+
+func (this *DmaProfiler) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *DmaProfiler) Record(dma_command *DmaCommand) {
+	if dma_command.Size() == 0 {
+		return
+	}
+
+	var prefix string
+	if dma_command.MemoryOperation() == READ {
+		prefix = "read"
+	} else if dma_command.MemoryOperation() == WRITE {
+		prefix = "write"
+	} else {
+		err := errors.New("memory operation is not valid")
+		panic(err)
+	}
+
+	issue_cycle := dma_command.EnterCycle()
+	if dma_command.HasIssueCycle() {
+		issue_cycle = dma_command.IssueCycle()
+	}
+
+	latency := dma_command.CompleteCycle() - issue_cycle
+
+	this.Histogram(prefix + "_latency").Add(latency)
+	this.Histogram(fmt.Sprintf("%s_%dB_latency", prefix, dma_command.Size())).Add(latency)
+
+	this.Histogram(prefix + "_queue_latency").Add(dma_command.EnterCycle() - issue_cycle)
+	this.Histogram(prefix + "_first_byte_latency").Add(dma_command.FirstByteCycle() - issue_cycle)
+
+	this.stat_factory.Increment("num_"+prefix+"s", 1)
+	this.stat_factory.Increment(prefix+"_bytes", dma_command.Size())
+}
+
+func (this *DmaProfiler) Histogram(name string) *misc.Histogram {
+	if _, found := this.histograms[name]; !found {
+		histogram := new(misc.Histogram)
+		histogram.Init(this.stat_factory.Name() + "_" + name)
+		this.histograms[name] = histogram
+	}
+
+	return this.histograms[name]
+}
+
+// Lines reports the bandwidth over memory_cycles memory cycles, in bytes/cycle and in GB/s at
+// memory_frequency, followed by the latency histograms.
+func (this *DmaProfiler) Lines(memory_cycles int64) []string {
+	lines := make([]string, 0)
+
+	lines = append(lines, this.stat_factory.ToLines()...)
+
+	if memory_cycles > 0 {
+		for _, prefix := range []string{"read", "write"} {
+			bytes_per_cycle := float64(this.stat_factory.Value(prefix+"_bytes")) / float64(memory_cycles)
+			gb_per_second := bytes_per_cycle * float64(global.MemoryFrequency) / 1000
+
+			lines = append(lines, fmt.Sprintf(
+				"%s_%s_bandwidth: %.4f bytes/cycle (%.4f GB/s)",
+				this.stat_factory.Name(),
+				prefix,
+				bytes_per_cycle,
+				gb_per_second,
+			))
+		}
+	}
+
+	names := make([]string, 0)
+	for name := range this.histograms {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		lines = append(lines, this.histograms[name].ToLines()...)
+	}
+
+	return lines
+}
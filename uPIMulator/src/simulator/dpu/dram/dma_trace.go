@@ -0,0 +1,195 @@
+package dram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+// DmaTraceEntry is a DMA command of a tasklet as recorded during a full run. Cycles are logic
+// cycles of the DPU.
+type DmaTraceEntry struct {
+	thread_id        int
+	issue_cycle      int64
+	complete_cycle   int64
+	memory_operation MemoryOperation
+	mram_address     int64
+	size             int64
+}
+
+func (this *DmaTraceEntry) Init(
+	thread_id int,
+	issue_cycle int64,
+	complete_cycle int64,
+	memory_operation MemoryOperation,
+	mram_address int64,
+	size int64,
+) {
+	if memory_operation != READ && memory_operation != WRITE {
+		err := errors.New("memory operation is not valid")
+		panic(err)
+	}
+
+	this.thread_id = thread_id
+	this.issue_cycle = issue_cycle
+	this.complete_cycle = complete_cycle
+	this.memory_operation = memory_operation
+	this.mram_address = mram_address
+	this.size = size
+}
+
+func (this *DmaTraceEntry) ThreadId() int {
+	return this.thread_id
+}
+
+func (this *DmaTraceEntry) IssueCycle() int64 {
+	return this.issue_cycle
+}
+
+func (this *DmaTraceEntry) CompleteCycle() int64 {
+	return this.complete_cycle
+}
+
+func (this *DmaTraceEntry) MemoryOperation() MemoryOperation {
+	return this.memory_operation
+}
+
+func (this *DmaTraceEntry) MramAddress() int64 {
+	return this.mram_address
+}
+
+func (this *DmaTraceEntry) Size() int64 {
+	return this.size
+}
+
+// DmaTrace is a list of DMA trace entries in completion order. Its text format has one entry per
+// line:
+//
+//	<tasklet> <issue cycle> <complete cycle> <R|W> <MRAM address> <size>
+type DmaTrace struct {
+	entries []*DmaTraceEntry
+}
+
+func (this *DmaTrace) Init() {
+	this.entries = make([]*DmaTraceEntry, 0)
+}
+
+func (this *DmaTrace) Entries() []*DmaTraceEntry {
+	return this.entries
+}
+
+func (this *DmaTrace) Append(entry *DmaTraceEntry) {
+	this.entries = append(this.entries, entry)
+}
+
+func (this *DmaTrace) Dump(path string) {
+	lines := make([]string, 0)
+	for _, entry := range this.entries {
+		memory_operation := "R"
+		if entry.memory_operation == WRITE {
+			memory_operation = "W"
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%d %d %d %s %d %d",
+			entry.thread_id,
+			entry.issue_cycle,
+			entry.complete_cycle,
+			memory_operation,
+			entry.mram_address,
+			entry.size,
+		))
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+// Load reads a DMA trace and rejects a line whose tasklet, MRAM address range or cycles are not
+// valid, so that a malformed trace is reported with its line instead of panicking in the MRAM.
+func (this *DmaTrace) Load(path string) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	mram_begin_address := config_loader.MramOffset()
+	mram_end_address := config_loader.MramOffset() + config_loader.MramSize()
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for i, line := range file_scanner.ReadLines() {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var thread_id int
+		var issue_cycle int64
+		var complete_cycle int64
+		var memory_operation string
+		var mram_address int64
+		var size int64
+
+		_, scan_err := fmt.Sscanf(
+			line,
+			"%d %d %d %s %d %d",
+			&thread_id,
+			&issue_cycle,
+			&complete_cycle,
+			&memory_operation,
+			&mram_address,
+			&size,
+		)
+		if scan_err != nil {
+			err_msg := fmt.Sprintf("line %d: DMA trace entry (%s) is not valid", i+1, line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		if thread_id < 0 || thread_id >= config_loader.MaxNumTasklets() {
+			err_msg := fmt.Sprintf("line %d: tasklet %d is not valid", i+1, thread_id)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if issue_cycle < 0 || complete_cycle < issue_cycle {
+			err_msg := fmt.Sprintf(
+				"line %d: cycles [%d, %d] are not valid",
+				i+1,
+				issue_cycle,
+				complete_cycle,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if size <= 0 {
+			err_msg := fmt.Sprintf("line %d: size %d <= 0", i+1, size)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if mram_address < mram_begin_address || mram_address+size > mram_end_address {
+			err_msg := fmt.Sprintf(
+				"line %d: MRAM address range [%d, %d) is not in MRAM",
+				i+1,
+				mram_address,
+				mram_address+size,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		entry := new(DmaTraceEntry)
+		if memory_operation == "R" {
+			entry.Init(thread_id, issue_cycle, complete_cycle, READ, mram_address, size)
+		} else if memory_operation == "W" {
+			entry.Init(thread_id, issue_cycle, complete_cycle, WRITE, mram_address, size)
+		} else {
+			err_msg := fmt.Sprintf(
+				"line %d: memory operation %s is not valid",
+				i+1,
+				memory_operation,
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.entries = append(this.entries, entry)
+	}
+}
@@ -0,0 +1,105 @@
+package dram_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/simulator/dpu/dram"
+)
+
+func LoadDmaTrace(t *testing.T, trace string) *dram.DmaTrace {
+	path := filepath.Join(t.TempDir(), "dma_trace.txt")
+	if err := os.WriteFile(path, []byte(trace), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dma_trace := new(dram.DmaTrace)
+	dma_trace.Init()
+	dma_trace.Load(path)
+	return dma_trace
+}
+
+func TestDmaTraceDumpLoad(t *testing.T) {
+	entries := []struct {
+		thread_id        int
+		issue_cycle      int64
+		complete_cycle   int64
+		memory_operation dram.MemoryOperation
+		mram_address     int64
+		size             int64
+	}{
+		{0, 10, 52, dram.READ, 134217728, 2048},
+		{23, 11, 60, dram.WRITE, 134217728 + 4096, 8},
+		{0, 70, 90, dram.READ, 134217728 + 64*1024*1024 - 8, 8},
+	}
+
+	dma_trace := new(dram.DmaTrace)
+	dma_trace.Init()
+	for _, entry := range entries {
+		dma_trace_entry := new(dram.DmaTraceEntry)
+		dma_trace_entry.Init(
+			entry.thread_id,
+			entry.issue_cycle,
+			entry.complete_cycle,
+			entry.memory_operation,
+			entry.mram_address,
+			entry.size,
+		)
+		dma_trace.Append(dma_trace_entry)
+	}
+
+	path := filepath.Join(t.TempDir(), "dma_trace.txt")
+	dma_trace.Dump(path)
+
+	loaded := new(dram.DmaTrace)
+	loaded.Init()
+	loaded.Load(path)
+
+	if len(loaded.Entries()) != len(entries) {
+		t.Fatalf("len(Entries()) = %d, want %d", len(loaded.Entries()), len(entries))
+	}
+
+	for i, entry := range entries {
+		got := loaded.Entries()[i]
+		if got.ThreadId() != entry.thread_id || got.IssueCycle() != entry.issue_cycle ||
+			got.CompleteCycle() != entry.complete_cycle ||
+			got.MemoryOperation() != entry.memory_operation ||
+			got.MramAddress() != entry.mram_address || got.Size() != entry.size {
+			t.Errorf("entry %d = %+v, want %+v", i, *got, entry)
+		}
+	}
+}
+
+func TestDmaTraceLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		trace      string
+		want_panic bool
+	}{
+		{"valid", "0 10 52 R 134217728 8\n\n23 11 60 W 134217736 8\n", false},
+		{"last bytes of MRAM", "0 10 52 R 201326584 8\n", false},
+		{"missing field", "0 10 52 R 134217728\n", true},
+		{"not a number", "0 ten 52 R 134217728 8\n", true},
+		{"negative tasklet", "-1 10 52 R 134217728 8\n", true},
+		{"tasklet past the last one", "24 10 52 R 134217728 8\n", true},
+		{"negative issue cycle", "0 -1 52 R 134217728 8\n", true},
+		{"completed before issued", "0 52 10 R 134217728 8\n", true},
+		{"zero size", "0 10 52 R 134217728 0\n", true},
+		{"negative size", "0 10 52 W 134217728 -8\n", true},
+		{"below MRAM", "0 10 52 R 1024 8\n", true},
+		{"past MRAM", "0 10 52 R 201326584 16\n", true},
+		{"unknown operation", "0 10 52 X 134217728 8\n", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			LoadDmaTrace(t, test.trace)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package dram
+
+// FcfsPolicy issues memory commands strictly in arrival order.
+type FcfsPolicy struct {
+}
+
+func (this *FcfsPolicy) Init() {
+}
+
+func (this *FcfsPolicy) Name() string {
+	return "fcfs"
+}
+
+func (this *FcfsPolicy) Select(reorder_buffer *MemoryCommandQ, row_address *int64) int {
+	if reorder_buffer.CanPop(1) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (this *FcfsPolicy) Issue(memory_command *MemoryCommand, is_row_hit bool, is_reordered bool) {
+}
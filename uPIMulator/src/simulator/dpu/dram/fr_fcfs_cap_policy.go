@@ -0,0 +1,51 @@
+package dram
+
+import (
+	"uPIMulator/src/global"
+)
+
+// FrFcfsCapPolicy is FR-FCFS that lets at most frfcfs_cap row hits in a row bypass an older memory
+// command, so that a stream of row hits cannot starve a row miss.
+type FrFcfsCapPolicy struct {
+	cap              int64
+	num_row_bypasses int64
+}
+
+func (this *FrFcfsCapPolicy) Init() {
+	this.cap = global.FrFcfsCap
+	this.num_row_bypasses = 0
+}
+
+func (this *FrFcfsCapPolicy) Name() string {
+	return "frfcfs_cap"
+}
+
+func (this *FrFcfsCapPolicy) Select(reorder_buffer *MemoryCommandQ, row_address *int64) int {
+	if row_address != nil && this.num_row_bypasses < this.cap {
+		for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+			memory_command, _ := reorder_buffer.Front(i)
+
+			if IsRowHit(memory_command, row_address) {
+				return i
+			}
+		}
+	}
+
+	if reorder_buffer.CanPop(1) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (this *FrFcfsCapPolicy) Issue(
+	memory_command *MemoryCommand,
+	is_row_hit bool,
+	is_reordered bool,
+) {
+	if is_reordered {
+		this.num_row_bypasses++
+	} else {
+		this.num_row_bypasses = 0
+	}
+}
@@ -0,0 +1,43 @@
+package dram
+
+import (
+	"uPIMulator/src/global"
+)
+
+// FrFcfsPolicy issues the oldest memory command that hits the open row, or the oldest memory
+// command if none does.
+type FrFcfsPolicy struct {
+}
+
+func (this *FrFcfsPolicy) Init() {
+}
+
+func (this *FrFcfsPolicy) Name() string {
+	return "frfcfs"
+}
+
+func (this *FrFcfsPolicy) Select(reorder_buffer *MemoryCommandQ, row_address *int64) int {
+	if row_address != nil {
+		for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+			memory_command, _ := reorder_buffer.Front(i)
+
+			if IsRowHit(memory_command, row_address) {
+				return i
+			}
+		}
+	}
+
+	if reorder_buffer.CanPop(1) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (this *FrFcfsPolicy) Issue(memory_command *MemoryCommand, is_row_hit bool, is_reordered bool) {
+}
+
+func IsRowHit(memory_command *MemoryCommand, row_address *int64) bool {
+	return row_address != nil &&
+		memory_command.Address()/global.WordlineSize*global.WordlineSize == *row_address
+}
@@ -0,0 +1,316 @@
+package dram
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+// NOTE: the number of hottest rows listed in the report
+const num_hottest_rows = 16
+
+// HammerSource is what caused an ACT: the tasklet and the PC of an ldma/sdma, the tasklet alone
+// (PC -1) if the PC is not known, or the host (tasklet -1).
+type HammerSource struct {
+	thread_id int
+	pc        int64
+}
+
+// HammerTracker counts the activations (ACTs) of every MRAM row of a single DPU within a sliding
+// window of hammer_window memory cycles, which stands for the refresh window. A row whose ACT
+// count within the window reaches hammer_threshold hammers its physical neighbors in the same
+// bank, the victims. With hammer_flip, a random bit of every victim row is flipped in MRAM each
+// time one of its neighbors reaches the threshold. ACTs are attributed to the tasklet and the PC
+// of the ldma/sdma that caused them (tasklet -1 for the host).
+type HammerTracker struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	address_mapper *AddressMapper
+	mram           *Mram
+	symbol_table   *symbol.SymbolTable
+
+	window int64
+	random *rand.Rand
+
+	activation_cycles      map[int64][]int64
+	num_activations        map[int64]int64
+	max_window_activations map[int64]int64
+	sources                map[int64]map[HammerSource]int64
+	num_disturbances       map[int64]int64
+
+	lines []string
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *HammerTracker) Init(
+	channel_id int,
+	rank_id int,
+	dpu_id int,
+	address_mapper *AddressMapper,
+) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.address_mapper = address_mapper
+	this.mram = nil
+	this.symbol_table = nil
+
+	// NOTE: the default window is the 64 ms refresh window of DDR4
+	this.window = global.HammerWindow
+	if this.window == 0 {
+		this.window = 64 * 1000 * global.MemoryFrequency
+	}
+
+	this.random = rand.New(rand.NewSource(int64(channel_id<<16 | rank_id<<8 | dpu_id)))
+
+	this.activation_cycles = make(map[int64][]int64, 0)
+	this.num_activations = make(map[int64]int64, 0)
+	this.max_window_activations = make(map[int64]int64, 0)
+	this.sources = make(map[int64]map[HammerSource]int64, 0)
+	this.num_disturbances = make(map[int64]int64, 0)
+
+	this.lines = make([]string, 0)
+
+	name := fmt.Sprintf("HammerTracker[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *HammerTracker) Fini() {
+}
+
+func (this *HammerTracker) ConnectMram(mram *Mram) {
+	if this.mram != nil {
+		err := errors.New("MRAM is already set")
+		panic(err)
+	}
+
+	this.mram = mram
+}
+
+func (this *HammerTracker) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.symbol_table != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	this.symbol_table = symbol_table
+}
+
+func (this *HammerTracker) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Activate records the ACT of a row at cycle on behalf of a DMA command, which is nil if the
+// activation was not caused by a DMA of a tasklet.
+func (this *HammerTracker) Activate(row_address int64, dma_command *DmaCommand, cycle int64) {
+	activation_cycles := this.activation_cycles[row_address]
+
+	num_expired := 0
+	for num_expired < len(activation_cycles) && cycle-activation_cycles[num_expired] >= this.window {
+		num_expired++
+	}
+	activation_cycles = append(activation_cycles[num_expired:], cycle)
+	this.activation_cycles[row_address] = activation_cycles
+
+	num_window_activations := int64(len(activation_cycles))
+
+	this.num_activations[row_address]++
+	if num_window_activations > this.max_window_activations[row_address] {
+		this.max_window_activations[row_address] = num_window_activations
+	}
+
+	source := HammerSource{thread_id: -1, pc: -1}
+	if dma_command != nil && dma_command.HasThreadId() {
+		source.thread_id = dma_command.ThreadId()
+	}
+	if dma_command != nil && dma_command.HasPc() {
+		source.pc = dma_command.Pc()
+	}
+
+	if _, found := this.sources[row_address]; !found {
+		this.sources[row_address] = make(map[HammerSource]int64, 0)
+	}
+	this.sources[row_address][source]++
+
+	this.stat_factory.Increment("num_activations", 1)
+
+	if num_window_activations == global.HammerThreshold {
+		this.Hammer(row_address, cycle)
+	}
+}
+
+// Hammer disturbs the neighbors of an aggressor row that has just reached the threshold.
+func (this *HammerTracker) Hammer(row_address int64, cycle int64) {
+	this.stat_factory.Increment("num_hammers", 1)
+
+	this.lines = append(this.lines, fmt.Sprintf(
+		"cycle %d: row %d reaches %d ACTs within %d cycles",
+		cycle,
+		row_address,
+		global.HammerThreshold,
+		this.window,
+	))
+
+	for _, victim_address := range this.Neighbors(row_address) {
+		this.num_disturbances[victim_address]++
+		this.stat_factory.Increment("num_disturbances", 1)
+
+		if global.HammerFlip {
+			this.Flip(victim_address)
+		}
+	}
+}
+
+// Neighbors returns the rows physically adjacent to a row in its bank.
+func (this *HammerTracker) Neighbors(row_address int64) []int64 {
+	neighbors := make([]int64, 0)
+
+	bank := this.address_mapper.Bank(row_address)
+	begin_address := this.mram.Address()
+	end_address := this.mram.Address() + this.mram.Size()
+
+	for _, direction := range []int64{-1, 1} {
+		for i := int64(1); i <= int64(this.address_mapper.NumBanks()); i++ {
+			address := row_address + direction*i*global.WordlineSize
+			if address < begin_address || address >= end_address {
+				break
+			}
+
+			if this.address_mapper.Bank(address) == bank {
+				neighbors = append(neighbors, address)
+				break
+			}
+		}
+	}
+
+	return neighbors
+}
+
+func (this *HammerTracker) Flip(victim_address int64) {
+	byte_stream := this.mram.Read(victim_address)
+
+	index := this.random.Intn(int(byte_stream.Size()))
+	bit := this.random.Intn(8)
+
+	byte_stream.Set(index, byte_stream.Get(index)^uint8(1<<bit))
+	this.mram.Write(victim_address, byte_stream)
+
+	this.stat_factory.Increment("num_bit_flips", 1)
+
+	this.lines = append(this.lines, fmt.Sprintf(
+		"  bit %d of byte %d flipped in victim row %d",
+		bit,
+		victim_address+int64(index),
+		victim_address,
+	))
+}
+
+// Lines lists the hammer events and the hottest rows with the symbols they belong to and the
+// tasklets and PCs whose DMAs activated them.
+func (this *HammerTracker) Lines() []string {
+	name := fmt.Sprintf("DPU%d-%d-%d", this.channel_id, this.rank_id, this.dpu_id)
+
+	lines := make([]string, 0)
+	for _, line := range this.lines {
+		lines = append(lines, name+" "+line)
+	}
+
+	row_addresses := make([]int64, 0)
+	for row_address := range this.num_activations {
+		row_addresses = append(row_addresses, row_address)
+	}
+	for row_address := range this.num_disturbances {
+		if _, found := this.num_activations[row_address]; !found {
+			row_addresses = append(row_addresses, row_address)
+		}
+	}
+
+	slices.SortFunc(row_addresses, func(x int64, y int64) int {
+		if this.max_window_activations[x] != this.max_window_activations[y] {
+			return int(this.max_window_activations[y] - this.max_window_activations[x])
+		}
+		return int(x - y)
+	})
+
+	if len(row_addresses) > num_hottest_rows {
+		row_addresses = row_addresses[:num_hottest_rows]
+	}
+
+	for _, row_address := range row_addresses {
+		lines = append(lines, fmt.Sprintf(
+			"%s row %d (%s): %d ACTs, at most %d within the window, %d disturbances",
+			name,
+			row_address,
+			this.Symbolize(row_address),
+			this.num_activations[row_address],
+			this.max_window_activations[row_address],
+			this.num_disturbances[row_address],
+		))
+
+		sources := make([]HammerSource, 0)
+		for source := range this.sources[row_address] {
+			sources = append(sources, source)
+		}
+
+		// NOTE: sources with as many ACTs are sorted by tasklet and PC, so that the report does not
+		// depend on the order of the map
+		slices.SortFunc(sources, func(x HammerSource, y HammerSource) int {
+			if this.sources[row_address][x] != this.sources[row_address][y] {
+				return int(this.sources[row_address][y] - this.sources[row_address][x])
+			} else if x.thread_id != y.thread_id {
+				return x.thread_id - y.thread_id
+			}
+			return int(x.pc - y.pc)
+		})
+
+		for _, source := range sources {
+			if source.thread_id < 0 {
+				lines = append(lines, fmt.Sprintf("  host: %d ACTs", this.sources[row_address][source]))
+			} else if source.pc < 0 {
+				lines = append(lines, fmt.Sprintf(
+					"  tasklet %d: %d ACTs",
+					source.thread_id,
+					this.sources[row_address][source],
+				))
+			} else {
+				lines = append(lines, fmt.Sprintf(
+					"  tasklet %d at %d (%s): %d ACTs",
+					source.thread_id,
+					source.pc,
+					this.Symbolize(source.pc),
+					this.sources[row_address][source],
+				))
+			}
+		}
+	}
+
+	return lines
+}
+
+func (this *HammerTracker) Symbolize(address int64) string {
+	if this.symbol_table == nil {
+		return fmt.Sprintf("%d", address)
+	}
+
+	return this.symbol_table.Symbolize(address)
+}
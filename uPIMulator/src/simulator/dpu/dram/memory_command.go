@@ -12,6 +12,7 @@ const (
 	READ
 	WRITE
 	PRECHARGE
+	REFRESH
 )
 
 type MemoryCommand struct {
@@ -109,6 +110,20 @@ func (this *MemoryCommand) SetByteStream(byte_stream *encoding.ByteStream) {
 	}
 }
 
+// SetDmaCommand attributes an activation to the DMA command whose access opens the row.
+func (this *MemoryCommand) SetDmaCommand(dma_command *DmaCommand) {
+	if this.memory_operation != ACTIVATION {
+		err := errors.New("memory operation != ACTIVATION")
+		panic(err)
+	}
+
+	this.dma_command = dma_command
+}
+
+func (this *MemoryCommand) HasDmaCommand() bool {
+	return this.dma_command != nil
+}
+
 func (this *MemoryCommand) DmaCommand() *DmaCommand {
 	if this.dma_command == nil {
 		err := errors.New("memory command does not have a DMA command")
@@ -14,13 +14,16 @@ type MemoryController struct {
 	dpu_id     int
 
 	memory_scheduler *MemoryScheduler
-	row_buffer       *RowBuffer
+	row_buffers      []*RowBuffer
+	data_bus         *DataBus
 	mram             *Mram
 
-	input_q          *DmaCommandQ
-	wait_q           *DmaCommandQ
-	memory_command_q *MemoryCommandQ
-	ready_q          *DmaCommandQ
+	input_q           *DmaCommandQ
+	wait_q            *DmaCommandQ
+	memory_command_qs []*MemoryCommandQ
+	ready_q           *DmaCommandQ
+
+	dma_profiler *DmaProfiler
 
 	stat_factory *misc.StatFactory
 }
@@ -48,8 +51,31 @@ func (this *MemoryController) Init(
 	this.memory_scheduler = new(MemoryScheduler)
 	this.memory_scheduler.Init(channel_id, rank_id, dpu_id)
 
-	this.row_buffer = new(RowBuffer)
-	this.row_buffer.Init(channel_id, rank_id, dpu_id)
+	address_mapper := this.memory_scheduler.AddressMapper()
+
+	if address_mapper.NumBanks() > 1 {
+		this.data_bus = new(DataBus)
+		this.data_bus.Init()
+	} else {
+		this.data_bus = nil
+	}
+
+	this.row_buffers = make([]*RowBuffer, 0)
+	this.memory_command_qs = make([]*MemoryCommandQ, 0)
+	for i := 0; i < address_mapper.NumBanks(); i++ {
+		row_buffer := new(RowBuffer)
+		row_buffer.Init(channel_id, rank_id, dpu_id, i, address_mapper.BankGroup(i))
+
+		if this.data_bus != nil {
+			row_buffer.ConnectDataBus(this.data_bus)
+		}
+
+		this.row_buffers = append(this.row_buffers, row_buffer)
+
+		memory_command_q := new(MemoryCommandQ)
+		memory_command_q.Init(-1, 0)
+		this.memory_command_qs = append(this.memory_command_qs, memory_command_q)
+	}
 
 	this.mram = new(Mram)
 	this.mram.Init()
@@ -60,12 +86,12 @@ func (this *MemoryController) Init(
 	this.wait_q = new(DmaCommandQ)
 	this.wait_q.Init(-1, 0)
 
-	this.memory_command_q = new(MemoryCommandQ)
-	this.memory_command_q.Init(-1, 0)
-
 	this.ready_q = new(DmaCommandQ)
 	this.ready_q.Init(-1, 0)
 
+	this.dma_profiler = new(DmaProfiler)
+	this.dma_profiler.Init(channel_id, rank_id, dpu_id)
+
 	name := fmt.Sprintf("MemoryController[%d_%d_%d]", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
@@ -73,25 +99,51 @@ func (this *MemoryController) Init(
 
 func (this *MemoryController) Fini() {
 	this.memory_scheduler.Fini()
-	this.row_buffer.Fini()
+
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.Fini()
+	}
 
 	this.input_q.Fini()
 	this.wait_q.Fini()
-	this.memory_command_q.Fini()
+
+	for _, memory_command_q := range this.memory_command_qs {
+		memory_command_q.Fini()
+	}
+
 	this.ready_q.Fini()
+
+	this.dma_profiler.Fini()
 }
 
 func (this *MemoryController) ConnectMram(mram *Mram) {
 	this.mram = mram
-	this.row_buffer.ConnectMram(mram)
+
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.ConnectMram(mram)
+	}
+}
+
+func (this *MemoryController) ConnectHammerTracker(hammer_tracker *HammerTracker) {
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.ConnectHammerTracker(hammer_tracker)
+	}
 }
 
 func (this *MemoryController) MemoryScheduler() *MemoryScheduler {
 	return this.memory_scheduler
 }
 
-func (this *MemoryController) RowBuffer() *RowBuffer {
-	return this.row_buffer
+func (this *MemoryController) RowBuffers() []*RowBuffer {
+	return this.row_buffers
+}
+
+func (this *MemoryController) DmaProfiler() *DmaProfiler {
+	return this.dma_profiler
+}
+
+func (this *MemoryController) Cycles() int64 {
+	return this.stat_factory.Value("memory_cycle")
 }
 
 func (this *MemoryController) StatFactory() *misc.StatFactory {
@@ -99,11 +151,15 @@ func (this *MemoryController) StatFactory() *misc.StatFactory {
 }
 
 func (this *MemoryController) IsEmpty() bool {
+	for i := range this.row_buffers {
+		if !this.row_buffers[i].IsEmpty() || !this.memory_command_qs[i].IsEmpty() {
+			return false
+		}
+	}
+
 	return this.memory_scheduler.IsEmpty() &&
-		this.row_buffer.IsEmpty() &&
 		this.input_q.IsEmpty() &&
 		this.wait_q.IsEmpty() &&
-		this.memory_command_q.IsEmpty() &&
 		this.ready_q.IsEmpty()
 }
 
@@ -117,6 +173,8 @@ func (this *MemoryController) Push(dma_command *DmaCommand) {
 		panic(err)
 	}
 
+	dma_command.SetEnterCycle(this.Cycles())
+
 	this.input_q.Push(dma_command)
 }
 
@@ -189,9 +247,32 @@ func (this *MemoryController) Write(address int64, size int64, byte_stream *enco
 	}
 }
 
+// Peek reads MRAM as the DPU currently sees it, including the open row, without advancing the
+// memory controller.
+func (this *MemoryController) Peek(address int64, size int64) *encoding.ByteStream {
+	byte_stream := this.Read(address, size)
+
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.Peek(address, byte_stream)
+	}
+
+	return byte_stream
+}
+
+func (this *MemoryController) Poke(address int64, byte_stream *encoding.ByteStream) {
+	this.Write(address, byte_stream.Size(), byte_stream)
+
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.Poke(address, byte_stream)
+	}
+}
+
 func (this *MemoryController) Flush() {
 	this.memory_scheduler.Flush()
-	this.row_buffer.Flush()
+
+	for _, row_buffer := range this.row_buffers {
+		row_buffer.Flush()
+	}
 }
 
 func (this *MemoryController) Cycle() {
@@ -202,11 +283,25 @@ func (this *MemoryController) Cycle() {
 	this.ServiceWaitQ()
 
 	this.memory_scheduler.Cycle()
-	this.row_buffer.Cycle()
+
+	// NOTE: banks take turns in being the first to claim the shared data bus
+	num_banks := len(this.row_buffers)
+	first_bank := int(this.stat_factory.Value("memory_cycle") % int64(num_banks))
+	for i := 0; i < num_banks; i++ {
+		this.row_buffers[(first_bank+i)%num_banks].Cycle()
+	}
+
+	if this.data_bus != nil {
+		this.data_bus.Cycle()
+	}
 
 	this.input_q.Cycle()
 	this.wait_q.Cycle()
-	this.memory_command_q.Cycle()
+
+	for _, memory_command_q := range this.memory_command_qs {
+		memory_command_q.Cycle()
+	}
+
 	this.ready_q.Cycle()
 
 	this.stat_factory.Increment("memory_cycle", 1)
@@ -221,28 +316,38 @@ func (this *MemoryController) ServiceInputQ() {
 }
 
 func (this *MemoryController) ServiceScheduler() {
-	if this.memory_scheduler.CanPop() && this.memory_command_q.CanPush(1) {
-		memory_command := this.memory_scheduler.Pop()
-		this.memory_command_q.Push(memory_command)
+	for i, memory_command_q := range this.memory_command_qs {
+		if this.memory_scheduler.CanPop(i) && memory_command_q.CanPush(1) {
+			memory_command := this.memory_scheduler.Pop(i)
+			memory_command_q.Push(memory_command)
+		}
 	}
 }
 
 func (this *MemoryController) ServiceMemoryCommandQ() {
-	if this.memory_command_q.CanPop(1) && this.row_buffer.CanPush() {
-		memory_command := this.memory_command_q.Pop()
-		this.row_buffer.Push(memory_command)
+	for i, memory_command_q := range this.memory_command_qs {
+		if memory_command_q.CanPop(1) && this.row_buffers[i].CanPush() {
+			memory_command := memory_command_q.Pop()
+			this.row_buffers[i].Push(memory_command)
+		}
 	}
 }
 
 func (this *MemoryController) ServiceRowBuffer() {
-	if this.row_buffer.CanPop() {
-		memory_command := this.row_buffer.Pop()
+	for _, row_buffer := range this.row_buffers {
+		if !row_buffer.CanPop() {
+			continue
+		}
+
+		memory_command := row_buffer.Pop()
 
 		memory_operation := memory_command.MemoryOperation()
 		if memory_operation == ACTIVATION {
-			return
+			continue
 		} else if memory_operation == PRECHARGE {
-			return
+			continue
+		} else if memory_operation == REFRESH {
+			continue
 		} else if memory_operation == READ {
 			address := memory_command.Address()
 			size := memory_command.Size()
@@ -251,12 +356,14 @@ func (this *MemoryController) ServiceRowBuffer() {
 			dma_command := memory_command.DmaCommand()
 			dma_command.SetByteStream(address, size, byte_stream)
 			dma_command.SetAck(address, size)
+			dma_command.SetFirstByteCycle(this.Cycles())
 		} else if memory_operation == WRITE {
 			address := memory_command.Address()
 			size := memory_command.Size()
 
 			dma_command := memory_command.DmaCommand()
 			dma_command.SetAck(address, size)
+			dma_command.SetFirstByteCycle(this.Cycles())
 			this.mram.Write(memory_command.Address(), memory_command.ByteStream())
 		} else {
 			err := errors.New("memory operation is not valid")
@@ -272,6 +379,9 @@ func (this *MemoryController) ServiceWaitQ() {
 		if dma_command.IsReady() && this.ready_q.CanPush(1) {
 			this.wait_q.Remove(i)
 			this.ready_q.Push(dma_command)
+
+			dma_command.SetCompleteCycle(this.Cycles())
+			this.dma_profiler.Record(dma_command)
 		}
 	}
 }
@@ -12,14 +12,22 @@ type MemoryScheduler struct {
 	rank_id    int
 	dpu_id     int
 
-	input_q        *DmaCommandQ
-	reorder_buffer *MemoryCommandQ
-	ready_q        *MemoryCommandQ
+	address_mapper *AddressMapper
 
-	row_address            *int64
+	input_q         *DmaCommandQ
+	reorder_buffers []*MemoryCommandQ
+	ready_qs        []*MemoryCommandQ
+
+	row_addresses          []*int64
 	wordline_size          int64
 	min_access_granularity int64
 
+	scheduling_policies []SchedulingPolicy
+
+	cycles             int64
+	last_access_cycles []int64
+	next_refresh_cycle int64
+
 	stat_factory *misc.StatFactory
 }
 
@@ -43,26 +51,71 @@ func (this *MemoryScheduler) Init(
 	this.rank_id = rank_id
 	this.dpu_id = dpu_id
 
+	this.address_mapper = new(AddressMapper)
+	this.address_mapper.Init()
+
 	this.input_q = new(DmaCommandQ)
 	this.input_q.Init(-1, 0)
 
-	this.reorder_buffer = new(MemoryCommandQ)
-	this.reorder_buffer.Init(-1, 0)
-
-	this.ready_q = new(MemoryCommandQ)
-	this.ready_q.Init(-1, 0)
+	this.reorder_buffers = make([]*MemoryCommandQ, 0)
+	this.ready_qs = make([]*MemoryCommandQ, 0)
+	this.row_addresses = make([]*int64, 0)
+	this.scheduling_policies = make([]SchedulingPolicy, 0)
+	this.last_access_cycles = make([]int64, 0)
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		reorder_buffer := new(MemoryCommandQ)
+		reorder_buffer.Init(-1, 0)
+		this.reorder_buffers = append(this.reorder_buffers, reorder_buffer)
+
+		ready_q := new(MemoryCommandQ)
+		ready_q.Init(-1, 0)
+		this.ready_qs = append(this.ready_qs, ready_q)
+
+		this.row_addresses = append(this.row_addresses, nil)
+		this.scheduling_policies = append(this.scheduling_policies, this.InitSchedulingPolicy())
+		this.last_access_cycles = append(this.last_access_cycles, 0)
+	}
 
-	this.row_address = nil
+	this.cycles = 0
+	this.next_refresh_cycle = global.TRefi
 
 	name := fmt.Sprintf("MemoryScheduler[%d_%d_%d]", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
+	this.stat_factory.Increment("scheduling_policy_"+this.scheduling_policies[0].Name(), 1)
+}
+
+// InitSchedulingPolicy creates the scheduling policy of a bank. Each bank has its own instance
+// since policies keep per-bank state such as the row hit streak or the write drain mode.
+func (this *MemoryScheduler) InitSchedulingPolicy() SchedulingPolicy {
+	scheduling_policies := make(map[string]SchedulingPolicy, 0)
+	scheduling_policies["fcfs"] = new(FcfsPolicy)
+	scheduling_policies["frfcfs"] = new(FrFcfsPolicy)
+	scheduling_policies["frfcfs_cap"] = new(FrFcfsCapPolicy)
+	scheduling_policies["round_robin"] = new(RoundRobinPolicy)
+	scheduling_policies["read_priority"] = new(ReadPriorityPolicy)
+
+	if scheduling_policy, found := scheduling_policies[global.SchedulingPolicy]; found {
+		scheduling_policy.Init()
+		return scheduling_policy
+	} else {
+		fmt.Println(global.SchedulingPolicy)
+		err := errors.New("scheduling policy is not found")
+		panic(err)
+	}
 }
 
 func (this *MemoryScheduler) Fini() {
 	this.input_q.Fini()
-	this.reorder_buffer.Fini()
-	this.ready_q.Fini()
+
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		this.reorder_buffers[i].Fini()
+		this.ready_qs[i].Fini()
+	}
+}
+
+func (this *MemoryScheduler) AddressMapper() *AddressMapper {
+	return this.address_mapper
 }
 
 func (this *MemoryScheduler) StatFactory() *misc.StatFactory {
@@ -70,7 +123,17 @@ func (this *MemoryScheduler) StatFactory() *misc.StatFactory {
 }
 
 func (this *MemoryScheduler) IsEmpty() bool {
-	return this.input_q.IsEmpty() && this.reorder_buffer.IsEmpty() && this.ready_q.IsEmpty()
+	if !this.input_q.IsEmpty() {
+		return false
+	}
+
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		if !this.reorder_buffers[i].IsEmpty() || !this.ready_qs[i].IsEmpty() {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (this *MemoryScheduler) CanPush() bool {
@@ -86,17 +149,17 @@ func (this *MemoryScheduler) Push(dma_command *DmaCommand) {
 	this.input_q.Push(dma_command)
 }
 
-func (this *MemoryScheduler) CanPop() bool {
-	return this.ready_q.CanPop(1)
+func (this *MemoryScheduler) CanPop(bank int) bool {
+	return this.ready_qs[bank].CanPop(1)
 }
 
-func (this *MemoryScheduler) Pop() *MemoryCommand {
-	if !this.CanPop() {
+func (this *MemoryScheduler) Pop(bank int) *MemoryCommand {
+	if !this.CanPop(bank) {
 		err := errors.New("memory scheduler cannot be popped")
 		panic(err)
 	}
 
-	return this.ready_q.Pop()
+	return this.ready_qs[bank].Pop()
 }
 
 func (this *MemoryScheduler) Flush() {
@@ -105,19 +168,31 @@ func (this *MemoryScheduler) Flush() {
 		panic(err)
 	}
 
-	this.row_address = nil
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		this.row_addresses[i] = nil
+	}
 }
 
 func (this *MemoryScheduler) Cycle() {
 	this.ServiceInputQ()
 
-	if !this.ReorderFr() {
-		this.ReorderFcFs()
+	if global.TRefi > 0 && this.cycles >= this.next_refresh_cycle {
+		this.Refresh()
+	} else {
+		for i := 0; i < this.address_mapper.NumBanks(); i++ {
+			if !this.Schedule(i) && global.PagePolicy == "adaptive" {
+				this.CloseIdleRow(i)
+			}
+		}
 	}
 
+	this.cycles++
+
 	this.input_q.Cycle()
-	this.reorder_buffer.Cycle()
-	this.ready_q.Cycle()
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		this.reorder_buffers[i].Cycle()
+		this.ready_qs[i].Cycle()
+	}
 }
 
 func (this *MemoryScheduler) ServiceInputQ() {
@@ -155,76 +230,136 @@ func (this *MemoryScheduler) PopulateMemoryCommands(dma_command *DmaCommand) {
 			panic(err)
 		}
 
-		this.reorder_buffer.Push(memory_command)
+		this.reorder_buffers[this.address_mapper.Bank(address)].Push(memory_command)
 
 		address += size
 	}
 }
 
-func (this *MemoryScheduler) ReorderFr() bool {
-	if this.row_address != nil && this.ready_q.CanPush(1) {
-		for i := 0; this.reorder_buffer.CanPop(i + 1); i++ {
-			memory_command, _ := this.reorder_buffer.Front(i)
-
-			if this.WordlineAddress(memory_command.Address()) == *this.row_address &&
-				this.ready_q.CanPush(1) {
-				if i != 0 {
-					this.stat_factory.Increment("num_fr", 1)
-				} else {
-					this.stat_factory.Increment("num_fcfs", 1)
-				}
+func (this *MemoryScheduler) Schedule(bank int) bool {
+	reorder_buffer := this.reorder_buffers[bank]
+	ready_q := this.ready_qs[bank]
 
-				this.reorder_buffer.Remove(i)
-				this.ready_q.Push(memory_command)
+	if !ready_q.CanPush(3) {
+		return false
+	}
 
-				return true
-			}
-		}
+	pos := this.scheduling_policies[bank].Select(reorder_buffer, this.row_addresses[bank])
+	if pos == -1 {
+		return false
 	}
 
-	return false
-}
+	memory_command, _ := reorder_buffer.Front(pos)
+	reorder_buffer.Remove(pos)
 
-func (this *MemoryScheduler) ReorderFcFs() bool {
-	if this.reorder_buffer.CanPop(1) && this.row_address != nil && this.ready_q.CanPush(3) {
-		memory_command := this.reorder_buffer.Pop()
-		wordline_address := this.WordlineAddress(memory_command.Address())
+	wordline_address := this.WordlineAddress(memory_command.Address())
+	is_row_hit := IsRowHit(memory_command, this.row_addresses[bank])
+	is_reordered := pos != 0
 
-		if wordline_address == *this.row_address {
-			err := errors.New("FR has not worked correctly")
-			panic(err)
+	if is_row_hit {
+		if is_reordered {
+			this.stat_factory.Increment("num_fr", 1)
+		} else {
+			this.stat_factory.Increment("num_fcfs", 1)
 		}
 
-		precharge := new(MemoryCommand)
-		precharge.InitActivation(PRECHARGE, *this.row_address)
+		this.IncrementRowStat(bank, "row_hits")
+	} else {
+		if this.row_addresses[bank] != nil {
+			this.CloseRow(bank)
+
+			this.IncrementRowStat(bank, "row_conflicts")
+		} else {
+			this.IncrementRowStat(bank, "row_misses")
+		}
 
 		activation := new(MemoryCommand)
 		activation.InitActivation(ACTIVATION, wordline_address)
+		activation.SetDmaCommand(memory_command.DmaCommand())
 
-		this.ready_q.Push(precharge)
-		this.ready_q.Push(activation)
-		this.ready_q.Push(memory_command)
+		ready_q.Push(activation)
 
-		*this.row_address = wordline_address
+		this.row_addresses[bank] = new(int64)
+		*this.row_addresses[bank] = wordline_address
+	}
 
-		return true
-	} else if this.reorder_buffer.CanPop(1) && this.row_address == nil && this.ready_q.CanPush(2) {
-		memory_command := this.reorder_buffer.Pop()
-		wordline_address := this.WordlineAddress(memory_command.Address())
+	if is_reordered {
+		this.stat_factory.Increment("num_reorders", 1)
+	}
 
-		activation := new(MemoryCommand)
-		activation.InitActivation(ACTIVATION, wordline_address)
+	ready_q.Push(memory_command)
+	this.scheduling_policies[bank].Issue(memory_command, is_row_hit, is_reordered)
 
-		this.ready_q.Push(activation)
-		this.ready_q.Push(memory_command)
+	this.last_access_cycles[bank] = this.cycles
 
-		this.row_address = new(int64)
-		*this.row_address = wordline_address
+	if global.PagePolicy == "closed" {
+		this.CloseRow(bank)
+	}
 
-		return true
-	} else {
-		return false
+	return true
+}
+
+// IncrementRowStat counts a row hit, miss (no open row) or conflict (another open row) in total
+// and, for a multi-bank MRAM, per bank.
+func (this *MemoryScheduler) IncrementRowStat(bank int, stat string) {
+	this.stat_factory.Increment("num_"+stat, 1)
+
+	if this.address_mapper.NumBanks() > 1 {
+		this.stat_factory.Increment(fmt.Sprintf("bank%d_%s", bank, stat), 1)
+	}
+}
+
+// CloseIdleRow precharges the open row of a bank under the adaptive page policy once it has not
+// been accessed for page_timeout cycles and no buffered memory command hits it.
+func (this *MemoryScheduler) CloseIdleRow(bank int) {
+	row_address := this.row_addresses[bank]
+	if row_address == nil || this.cycles-this.last_access_cycles[bank] < global.PageTimeout {
+		return
+	}
+
+	reorder_buffer := this.reorder_buffers[bank]
+	for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+		memory_command, _ := reorder_buffer.Front(i)
+
+		if IsRowHit(memory_command, row_address) {
+			return
+		}
+	}
+
+	this.CloseRow(bank)
+	this.stat_factory.Increment("num_timeout_precharges", 1)
+}
+
+func (this *MemoryScheduler) CloseRow(bank int) {
+	if this.row_addresses[bank] == nil {
+		err := errors.New("row address is not set")
+		panic(err)
+	}
+
+	precharge := new(MemoryCommand)
+	precharge.InitActivation(PRECHARGE, *this.row_addresses[bank])
+
+	this.ready_qs[bank].Push(precharge)
+
+	this.row_addresses[bank] = nil
+}
+
+// Refresh closes the open rows and lets every bank refresh for t_rfc cycles every t_refi cycles.
+func (this *MemoryScheduler) Refresh() {
+	for i := 0; i < this.address_mapper.NumBanks(); i++ {
+		if this.row_addresses[i] != nil {
+			this.CloseRow(i)
+		}
+
+		refresh := new(MemoryCommand)
+		refresh.InitActivation(REFRESH, 0)
+
+		this.ready_qs[i].Push(refresh)
 	}
+
+	this.next_refresh_cycle += global.TRefi
+
+	this.stat_factory.Increment("num_refreshes", 1)
 }
 
 func (this *MemoryScheduler) WordlineAddress(address int64) int64 {
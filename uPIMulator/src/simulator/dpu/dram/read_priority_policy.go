@@ -0,0 +1,81 @@
+package dram
+
+import (
+	"uPIMulator/src/global"
+)
+
+// ReadPriorityPolicy serves reads before writes with FR-FCFS and buffers writes until
+// write_high_watermark of them are waiting. It then drains writes until at most
+// write_low_watermark are left. Either kind is served when the other one is absent.
+type ReadPriorityPolicy struct {
+	high_watermark int64
+	low_watermark  int64
+
+	is_draining bool
+
+	fr_fcfs_policy *FrFcfsPolicy
+}
+
+func (this *ReadPriorityPolicy) Init() {
+	this.high_watermark = global.WriteHighWatermark
+	this.low_watermark = global.WriteLowWatermark
+
+	this.is_draining = false
+
+	this.fr_fcfs_policy = new(FrFcfsPolicy)
+	this.fr_fcfs_policy.Init()
+}
+
+func (this *ReadPriorityPolicy) Name() string {
+	return "read_priority"
+}
+
+func (this *ReadPriorityPolicy) Select(reorder_buffer *MemoryCommandQ, row_address *int64) int {
+	num_writes := int64(0)
+	for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+		memory_command, _ := reorder_buffer.Front(i)
+
+		if memory_command.MemoryOperation() == WRITE {
+			num_writes++
+		}
+	}
+
+	if !this.is_draining && num_writes >= this.high_watermark {
+		this.is_draining = true
+	} else if this.is_draining && num_writes <= this.low_watermark {
+		this.is_draining = false
+	}
+
+	memory_operation := READ
+	if this.is_draining {
+		memory_operation = WRITE
+	}
+
+	pos := -1
+	for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+		memory_command, _ := reorder_buffer.Front(i)
+
+		if memory_command.MemoryOperation() != memory_operation {
+			continue
+		}
+
+		if IsRowHit(memory_command, row_address) {
+			return i
+		} else if pos == -1 {
+			pos = i
+		}
+	}
+
+	if pos != -1 {
+		return pos
+	}
+
+	return this.fr_fcfs_policy.Select(reorder_buffer, row_address)
+}
+
+func (this *ReadPriorityPolicy) Issue(
+	memory_command *MemoryCommand,
+	is_row_hit bool,
+	is_reordered bool,
+) {
+}
@@ -0,0 +1,70 @@
+package dram
+
+import (
+	"uPIMulator/src/misc"
+)
+
+// RoundRobinPolicy serves tasklets in turn so that a tasklet issuing large DMAs cannot starve the
+// others. Within the chosen tasklet, it issues the oldest row hit or else the oldest memory
+// command. Memory commands of the host are served as if they came from one more tasklet.
+type RoundRobinPolicy struct {
+	num_tasklets   int
+	last_thread_id int
+}
+
+func (this *RoundRobinPolicy) Init() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.num_tasklets = config_loader.MaxNumTasklets()
+	this.last_thread_id = -1
+}
+
+func (this *RoundRobinPolicy) Name() string {
+	return "round_robin"
+}
+
+func (this *RoundRobinPolicy) Select(reorder_buffer *MemoryCommandQ, row_address *int64) int {
+	pos := -1
+	distance := 0
+	is_row_hit := false
+
+	for i := 0; reorder_buffer.CanPop(i + 1); i++ {
+		memory_command, _ := reorder_buffer.Front(i)
+
+		cur_distance := this.Distance(this.ThreadId(memory_command))
+		cur_is_row_hit := IsRowHit(memory_command, row_address)
+
+		if pos == -1 || cur_distance < distance ||
+			(cur_distance == distance && cur_is_row_hit && !is_row_hit) {
+			pos = i
+			distance = cur_distance
+			is_row_hit = cur_is_row_hit
+		}
+	}
+
+	return pos
+}
+
+func (this *RoundRobinPolicy) Issue(
+	memory_command *MemoryCommand,
+	is_row_hit bool,
+	is_reordered bool,
+) {
+	this.last_thread_id = this.ThreadId(memory_command)
+}
+
+// ThreadId returns the tasklet of a memory command or -1 for the host.
+func (this *RoundRobinPolicy) ThreadId(memory_command *MemoryCommand) int {
+	if memory_command.DmaCommand().HasThreadId() {
+		return memory_command.DmaCommand().ThreadId()
+	} else {
+		return -1
+	}
+}
+
+// Distance is the number of turns until thread_id is served again after last_thread_id.
+func (this *RoundRobinPolicy) Distance(thread_id int) int {
+	num_turns := this.num_tasklets + 1
+	return (thread_id - this.last_thread_id - 1 + num_turns) % num_turns
+}
@@ -12,10 +12,14 @@ type RowBuffer struct {
 	channel_id int
 	rank_id    int
 	dpu_id     int
+	bank_id    int
+	bank_group int
 
-	mram        *Mram
-	row_address *int64
-	row_buffer  *encoding.ByteStream
+	mram           *Mram
+	data_bus       *DataBus
+	hammer_tracker *HammerTracker
+	row_address    *int64
+	row_buffer     *encoding.ByteStream
 
 	input_q *MemoryCommandQ
 	ready_q *MemoryCommandQ
@@ -24,6 +28,11 @@ type RowBuffer struct {
 	io_q         *MemoryCommandQ
 	bus_q        *MemoryCommandQ
 	precharge_q  *MemoryCommandQ
+	refresh_q    *MemoryCommandQ
+
+	cycles           int64
+	last_read_cycle  *int64
+	last_write_cycle *int64
 
 	stat_factory *misc.StatFactory
 }
@@ -32,6 +41,8 @@ func (this *RowBuffer) Init(
 	channel_id int,
 	rank_id int,
 	dpu_id int,
+	bank_id int,
+	bank_group int,
 ) {
 	if channel_id < 0 {
 		err := errors.New("channel ID < 0")
@@ -47,8 +58,12 @@ func (this *RowBuffer) Init(
 	this.channel_id = channel_id
 	this.rank_id = rank_id
 	this.dpu_id = dpu_id
+	this.bank_id = bank_id
+	this.bank_group = bank_group
 
 	this.mram = nil
+	this.data_bus = nil
+	this.hammer_tracker = nil
 	this.row_address = nil
 	this.row_buffer = nil
 
@@ -70,7 +85,17 @@ func (this *RowBuffer) Init(
 	this.precharge_q = new(MemoryCommandQ)
 	this.precharge_q.Init(1, global.TRp)
 
+	this.refresh_q = new(MemoryCommandQ)
+	this.refresh_q.Init(1, global.TRfc)
+
+	this.cycles = 0
+	this.last_read_cycle = nil
+	this.last_write_cycle = nil
+
 	name := fmt.Sprintf("RowBuffer[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	if global.NumBanks > 1 {
+		name = fmt.Sprintf("RowBuffer[%d_%d_%d_%d]", channel_id, rank_id, dpu_id, bank_id)
+	}
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
 }
@@ -82,6 +107,7 @@ func (this *RowBuffer) Fini() {
 	this.io_q.Fini()
 	this.bus_q.Fini()
 	this.precharge_q.Fini()
+	this.refresh_q.Fini()
 }
 
 func (this *RowBuffer) ConnectMram(mram *Mram) {
@@ -93,6 +119,25 @@ func (this *RowBuffer) ConnectMram(mram *Mram) {
 	this.mram = mram
 }
 
+// ConnectDataBus shares the data bus with the other banks of a multi-bank MRAM.
+func (this *RowBuffer) ConnectDataBus(data_bus *DataBus) {
+	if this.data_bus != nil {
+		err := errors.New("data bus is already connected")
+		panic(err)
+	}
+
+	this.data_bus = data_bus
+}
+
+func (this *RowBuffer) ConnectHammerTracker(hammer_tracker *HammerTracker) {
+	if this.hammer_tracker != nil {
+		err := errors.New("hammer tracker is already connected")
+		panic(err)
+	}
+
+	this.hammer_tracker = hammer_tracker
+}
+
 func (this *RowBuffer) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
@@ -101,7 +146,8 @@ func (this *RowBuffer) IsEmpty() bool {
 	return this.input_q.IsEmpty() && this.ready_q.IsEmpty() && this.activation_q.IsEmpty() &&
 		this.io_q.IsEmpty() &&
 		this.bus_q.IsEmpty() &&
-		this.precharge_q.IsEmpty()
+		this.precharge_q.IsEmpty() &&
+		this.refresh_q.IsEmpty()
 }
 
 func (this *RowBuffer) CanPush() bool {
@@ -145,6 +191,11 @@ func (this *RowBuffer) Cycle() {
 	this.ServiceIoQ()
 	this.ServiceBusQ()
 	this.ServicePrechargeQ()
+	this.ServiceRefreshQ()
+
+	if !this.refresh_q.IsEmpty() {
+		this.stat_factory.Increment("refresh_cycles", 1)
+	}
 
 	this.input_q.Cycle()
 	this.ready_q.Cycle()
@@ -153,6 +204,9 @@ func (this *RowBuffer) Cycle() {
 	this.io_q.Cycle()
 	this.bus_q.Cycle()
 	this.precharge_q.Cycle()
+	this.refresh_q.Cycle()
+
+	this.cycles++
 }
 
 func (this *RowBuffer) ServiceInputQ() {
@@ -166,20 +220,29 @@ func (this *RowBuffer) ServiceInputQ() {
 				this.input_q.Pop()
 			}
 		} else if memory_operation == READ {
-			if this.io_q.CanPush(1) && this.row_address != nil {
+			if this.io_q.CanPush(1) && this.row_address != nil &&
+				this.HasElapsed(this.last_write_cycle, global.TWtr) {
 				this.io_q.Push(memory_command)
 				this.input_q.Pop()
 			}
 		} else if memory_operation == WRITE {
-			if this.io_q.CanPush(1) && this.row_address != nil {
+			if this.io_q.CanPush(1) && this.row_address != nil &&
+				this.HasElapsed(this.last_read_cycle, global.TRtw) {
 				this.io_q.Push(memory_command)
 				this.input_q.Pop()
 			}
 		} else if memory_operation == PRECHARGE {
-			if this.activation_q.IsEmpty() && this.io_q.IsEmpty() && this.bus_q.IsEmpty() && this.precharge_q.IsEmpty() {
+			if this.activation_q.IsEmpty() && this.io_q.IsEmpty() && this.bus_q.IsEmpty() && this.precharge_q.IsEmpty() &&
+				this.HasElapsed(this.last_write_cycle, global.TWr) {
 				this.precharge_q.Push(memory_command)
 				this.input_q.Pop()
 			}
+		} else if memory_operation == REFRESH {
+			if this.row_address == nil && this.activation_q.IsEmpty() && this.io_q.IsEmpty() &&
+				this.bus_q.IsEmpty() && this.precharge_q.IsEmpty() && this.refresh_q.IsEmpty() {
+				this.refresh_q.Push(memory_command)
+				this.input_q.Pop()
+			}
 		} else {
 			err := errors.New("memory operation is not valid")
 			panic(err)
@@ -203,6 +266,17 @@ func (this *RowBuffer) ServiceActivationQ() {
 			this.row_address = new(int64)
 			*this.row_address = memory_command.Address()
 
+			// NOTE: the previous row of the bank has been written back by now, so the hammer tracker
+			// can flip bits of the victim rows directly in MRAM
+			if this.hammer_tracker != nil {
+				var dma_command *DmaCommand
+				if memory_command.HasDmaCommand() {
+					dma_command = memory_command.DmaCommand()
+				}
+
+				this.hammer_tracker.Activate(*this.row_address, dma_command, this.cycles)
+			}
+
 			this.row_buffer = this.ReadFromMram()
 		}
 	}
@@ -216,9 +290,14 @@ func (this *RowBuffer) ServiceActivationQ() {
 }
 
 func (this *RowBuffer) ServiceIoQ() {
-	if this.io_q.CanPop(1) && this.bus_q.CanPush(1) {
+	if this.io_q.CanPop(1) && this.bus_q.CanPush(1) &&
+		(this.data_bus == nil || this.data_bus.CanIssue(this.bank_group)) {
 		memory_command := this.io_q.Pop()
 		this.bus_q.Push(memory_command)
+
+		if this.data_bus != nil {
+			this.data_bus.Issue(this.bank_group)
+		}
 	}
 }
 
@@ -232,11 +311,17 @@ func (this *RowBuffer) ServiceBusQ() {
 			byte_stream := this.ReadFromRowBuffer(memory_command.Address(), memory_command.Size())
 			memory_command.SetByteStream(byte_stream)
 
+			this.last_read_cycle = new(int64)
+			*this.last_read_cycle = this.cycles
+
 			this.stat_factory.Increment("num_reads", 1)
 			this.stat_factory.Increment("read_bytes", memory_command.Size())
 		} else if memory_operation == WRITE {
 			this.WriteToRowBuffer(memory_command.Address(), memory_command.Size(), memory_command.ByteStream())
 
+			this.last_write_cycle = new(int64)
+			*this.last_write_cycle = this.cycles
+
 			this.stat_factory.Increment("num_writes", 1)
 			this.stat_factory.Increment("write_bytes", memory_command.Size())
 		} else {
@@ -267,6 +352,45 @@ func (this *RowBuffer) ServicePrechargeQ() {
 	}
 }
 
+func (this *RowBuffer) ServiceRefreshQ() {
+	if this.refresh_q.CanPop(1) && this.ready_q.CanPush(1) {
+		memory_command := this.refresh_q.Pop()
+		this.ready_q.Push(memory_command)
+	}
+}
+
+// HasElapsed returns true if at least timing cycles have passed since cycle, which is nil if the
+// event has not happened yet.
+func (this *RowBuffer) HasElapsed(cycle *int64, timing int64) bool {
+	return cycle == nil || this.cycles-*cycle >= timing
+}
+
+// Peek overwrites the bytes of byte_stream, which holds the MRAM contents starting at address,
+// with the ones of the open row that have not been written back yet.
+func (this *RowBuffer) Peek(address int64, byte_stream *encoding.ByteStream) {
+	if this.row_address == nil {
+		return
+	}
+
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		if *this.row_address <= address+i && address+i < *this.row_address+global.WordlineSize {
+			byte_stream.Set(int(i), this.row_buffer.Get(this.Index(address+i)))
+		}
+	}
+}
+
+func (this *RowBuffer) Poke(address int64, byte_stream *encoding.ByteStream) {
+	if this.row_address == nil {
+		return
+	}
+
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		if *this.row_address <= address+i && address+i < *this.row_address+global.WordlineSize {
+			this.row_buffer.Set(this.Index(address+i), byte_stream.Get(int(i)))
+		}
+	}
+}
+
 func (this *RowBuffer) ReadFromMram() *encoding.ByteStream {
 	if this.row_address == nil {
 		err := errors.New("row address is not set")
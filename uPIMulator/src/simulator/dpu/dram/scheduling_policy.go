@@ -0,0 +1,16 @@
+package dram
+
+// SchedulingPolicy decides which memory command of the reorder buffer the memory scheduler issues
+// next. The memory scheduler takes care of the precharge and activation the chosen command needs.
+type SchedulingPolicy interface {
+	Init()
+
+	Name() string
+
+	// Select returns the position of the next memory command in the reorder buffer or -1 to issue
+	// nothing this cycle. row_address is the open row or nil if no row is open.
+	Select(reorder_buffer *MemoryCommandQ, row_address *int64) int
+
+	// Issue is called with the memory command at the position returned by Select.
+	Issue(memory_command *MemoryCommand, is_row_hit bool, is_reordered bool)
+}
@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"errors"
+)
+
+type DebugEventType int
+
+const (
+	BREAKPOINT DebugEventType = iota
+	WATCHPOINT
+	STEP
+)
+
+type WatchpointType int
+
+const (
+	WRITE_WATCHPOINT WatchpointType = iota
+	READ_WATCHPOINT
+	ACCESS_WATCHPOINT
+)
+
+type Watchpoint struct {
+	watchpoint_type WatchpointType
+	address         int64
+	size            int64
+}
+
+func (this *Watchpoint) Init(watchpoint_type WatchpointType, address int64, size int64) {
+	if size <= 0 {
+		err := errors.New("watchpoint size <= 0")
+		panic(err)
+	}
+
+	this.watchpoint_type = watchpoint_type
+	this.address = address
+	this.size = size
+}
+
+func (this *Watchpoint) WatchpointType() WatchpointType {
+	return this.watchpoint_type
+}
+
+func (this *Watchpoint) Address() int64 {
+	return this.address
+}
+
+func (this *Watchpoint) Size() int64 {
+	return this.size
+}
+
+func (this *Watchpoint) IsHit(address int64, size int64, is_write bool) bool {
+	if address+size <= this.address || this.address+this.size <= address {
+		return false
+	}
+
+	if this.watchpoint_type == WRITE_WATCHPOINT {
+		return is_write
+	} else if this.watchpoint_type == READ_WATCHPOINT {
+		return !is_write
+	} else {
+		return true
+	}
+}
+
+type DebugEvent struct {
+	debug_event_type DebugEventType
+	thread_id        int
+	watchpoint       *Watchpoint
+}
+
+func (this *DebugEvent) Init(debug_event_type DebugEventType, thread_id int, watchpoint *Watchpoint) {
+	this.debug_event_type = debug_event_type
+	this.thread_id = thread_id
+	this.watchpoint = watchpoint
+}
+
+func (this *DebugEvent) DebugEventType() DebugEventType {
+	return this.debug_event_type
+}
+
+func (this *DebugEvent) ThreadId() int {
+	return this.thread_id
+}
+
+func (this *DebugEvent) Watchpoint() *Watchpoint {
+	return this.watchpoint
+}
+
+// DebugUnit holds the breakpoints and watchpoints of a single DPU. Logic consults it before
+// issuing an instruction and whenever an instruction touches WRAM or MRAM; the events it records
+// are drained by the debugger at cycle boundaries.
+type DebugUnit struct {
+	breakpoints map[int64]bool
+	watchpoints []*Watchpoint
+
+	stepping_threads map[int]bool
+	skipping_threads map[int]bool
+
+	events []*DebugEvent
+}
+
+func (this *DebugUnit) Init() {
+	this.breakpoints = make(map[int64]bool, 0)
+	this.watchpoints = make([]*Watchpoint, 0)
+
+	this.stepping_threads = make(map[int]bool, 0)
+	this.skipping_threads = make(map[int]bool, 0)
+
+	this.events = make([]*DebugEvent, 0)
+}
+
+func (this *DebugUnit) Fini() {
+}
+
+func (this *DebugUnit) InsertBreakpoint(address int64) {
+	this.breakpoints[address] = true
+}
+
+func (this *DebugUnit) RemoveBreakpoint(address int64) {
+	delete(this.breakpoints, address)
+}
+
+func (this *DebugUnit) HasBreakpoint(address int64) bool {
+	_, found := this.breakpoints[address]
+	return found
+}
+
+func (this *DebugUnit) InsertWatchpoint(watchpoint_type WatchpointType, address int64, size int64) {
+	watchpoint := new(Watchpoint)
+	watchpoint.Init(watchpoint_type, address, size)
+
+	this.watchpoints = append(this.watchpoints, watchpoint)
+}
+
+func (this *DebugUnit) RemoveWatchpoint(watchpoint_type WatchpointType, address int64, size int64) {
+	for i, watchpoint := range this.watchpoints {
+		if watchpoint.WatchpointType() == watchpoint_type && watchpoint.Address() == address &&
+			watchpoint.Size() == size {
+			this.watchpoints = append(this.watchpoints[:i], this.watchpoints[i+1:]...)
+			return
+		}
+	}
+}
+
+// Step makes the thread stop right after its next instruction is issued.
+func (this *DebugUnit) Step(thread_id int) {
+	this.stepping_threads[thread_id] = true
+}
+
+// Skip lets the thread issue the instruction it is parked on once even if a breakpoint is set
+// there, so that resuming from a breakpoint does not immediately hit it again.
+func (this *DebugUnit) Skip(thread_id int) {
+	this.skipping_threads[thread_id] = true
+}
+
+func (this *DebugUnit) HasEvents() bool {
+	return len(this.events) > 0
+}
+
+func (this *DebugUnit) Events() []*DebugEvent {
+	return this.events
+}
+
+func (this *DebugUnit) ClearEvents() {
+	this.events = make([]*DebugEvent, 0)
+}
+
+// ShouldBreak reports whether the thread must not issue the instruction at pc.
+func (this *DebugUnit) ShouldBreak(thread_id int, pc int64) bool {
+	if this.skipping_threads[thread_id] {
+		return false
+	}
+
+	if this.HasBreakpoint(pc) {
+		debug_event := new(DebugEvent)
+		debug_event.Init(BREAKPOINT, thread_id, nil)
+		this.events = append(this.events, debug_event)
+		return true
+	}
+
+	return false
+}
+
+func (this *DebugUnit) Issue(thread_id int) {
+	delete(this.skipping_threads, thread_id)
+
+	if this.stepping_threads[thread_id] {
+		delete(this.stepping_threads, thread_id)
+
+		debug_event := new(DebugEvent)
+		debug_event.Init(STEP, thread_id, nil)
+		this.events = append(this.events, debug_event)
+	}
+}
+
+func (this *DebugUnit) Access(thread_id int, address int64, size int64, is_write bool) {
+	for _, watchpoint := range this.watchpoints {
+		if watchpoint.IsHit(address, size, is_write) {
+			debug_event := new(DebugEvent)
+			debug_event.Init(WATCHPOINT, thread_id, watchpoint)
+			this.events = append(this.events, debug_event)
+		}
+	}
+}
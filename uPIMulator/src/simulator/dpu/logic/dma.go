@@ -14,9 +14,14 @@ type Dma struct {
 	iram              *sram.Iram
 	operand_collector *OperandCollector
 	memory_controller *dram.MemoryController
+	sanitizer         *Sanitizer
+	dma_trace         *dram.DmaTrace
 
 	input_q *dram.DmaCommandQ
 	ready_q *dram.DmaCommandQ
+
+	cycles       int64
+	issue_cycles map[*dram.DmaCommand]int64
 }
 
 func (this *Dma) Init() {
@@ -24,6 +29,8 @@ func (this *Dma) Init() {
 	this.iram = nil
 	this.operand_collector = nil
 	this.memory_controller = nil
+	this.sanitizer = nil
+	this.dma_trace = nil
 
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -35,6 +42,9 @@ func (this *Dma) Init() {
 
 	this.ready_q = new(dram.DmaCommandQ)
 	this.ready_q.Init(max_num_tasklets, 0)
+
+	this.cycles = 0
+	this.issue_cycles = make(map[*dram.DmaCommand]int64, 0)
 }
 
 func (this *Dma) Fini() {
@@ -78,6 +88,24 @@ func (this *Dma) ConnectMemoryController(memory_controller *dram.MemoryControlle
 	this.memory_controller = memory_controller
 }
 
+func (this *Dma) ConnectSanitizer(sanitizer *Sanitizer) {
+	if this.sanitizer != nil {
+		err := errors.New("sanitizer is already set")
+		panic(err)
+	}
+
+	this.sanitizer = sanitizer
+}
+
+func (this *Dma) ConnectDmaTrace(dma_trace *dram.DmaTrace) {
+	if this.dma_trace != nil {
+		err := errors.New("DMA trace is already set")
+		panic(err)
+	}
+
+	this.dma_trace = dma_trace
+}
+
 func (this *Dma) IsEmpty() bool {
 	return this.input_q.IsEmpty() && this.ready_q.IsEmpty()
 }
@@ -126,6 +154,23 @@ func (this *Dma) TransferToWram(address int64, byte_stream *encoding.ByteStream)
 		value := byte_stream.Get(int(i))
 		this.operand_collector.Sb(address+i, int64(value))
 	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.InitializeWram(address, byte_stream.Size())
+	}
+}
+
+// LoadWram writes the WRAM image of the program. Unlike a transfer of the host, it only
+// initializes the sections that the linker lays out.
+func (this *Dma) LoadWram(address int64, byte_stream *encoding.ByteStream) {
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		value := byte_stream.Get(int(i))
+		this.operand_collector.Sb(address+i, int64(value))
+	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.LoadWram(address, byte_stream.Size())
+	}
 }
 
 func (this *Dma) TransferFromMram(address int64, size int64) *encoding.ByteStream {
@@ -135,12 +180,18 @@ func (this *Dma) TransferFromMram(address int64, size int64) *encoding.ByteStrea
 
 func (this *Dma) TransferToMram(address int64, byte_stream *encoding.ByteStream) {
 	this.memory_controller.Write(address, byte_stream.Size(), byte_stream)
+
+	if this.sanitizer != nil {
+		this.sanitizer.InitializeMram(address, byte_stream.Size())
+	}
 }
 
 func (this *Dma) TransferFromWramToMram(
 	wram_address int64,
 	mram_address int64,
 	size int64,
+	thread_id int,
+	pc int64,
 	instruction_ *instruction.Instruction,
 ) {
 	if !this.CanPush() {
@@ -151,7 +202,15 @@ func (this *Dma) TransferFromWramToMram(
 	byte_stream := this.TransferFromWram(wram_address, size)
 
 	dma_command := new(dram.DmaCommand)
-	dma_command.InitWriteToMramFromWram(wram_address, mram_address, size, byte_stream, instruction_)
+	dma_command.InitWriteToMramFromWram(
+		wram_address,
+		mram_address,
+		size,
+		byte_stream,
+		thread_id,
+		instruction_,
+	)
+	dma_command.SetPc(pc)
 
 	this.Push(dma_command)
 }
@@ -160,6 +219,8 @@ func (this *Dma) TransferFromMramToWram(
 	wram_address int64,
 	mram_address int64,
 	size int64,
+	thread_id int,
+	pc int64,
 	instruction_ *instruction.Instruction,
 ) {
 	if !this.CanPush() {
@@ -168,7 +229,40 @@ func (this *Dma) TransferFromMramToWram(
 	}
 
 	dma_command := new(dram.DmaCommand)
-	dma_command.InitReadFromMramToWram(wram_address, mram_address, size, instruction_)
+	dma_command.InitReadFromMramToWram(wram_address, mram_address, size, thread_id, instruction_)
+	dma_command.SetPc(pc)
+
+	this.Push(dma_command)
+}
+
+// Discard completes a DMA instruction without transferring any byte so that its thread is still
+// waked up.
+func (this *Dma) Discard(thread_id int, instruction_ *instruction.Instruction) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	dma_command := new(dram.DmaCommand)
+	if instruction_.OpCode() == instruction.LDMA {
+		dma_command.InitReadFromMramToWram(
+			config_loader.WramOffset(),
+			config_loader.MramOffset(),
+			0,
+			thread_id,
+			instruction_,
+		)
+	} else {
+		byte_stream := new(encoding.ByteStream)
+		byte_stream.Init()
+
+		dma_command.InitWriteToMramFromWram(
+			config_loader.WramOffset(),
+			config_loader.MramOffset(),
+			0,
+			byte_stream,
+			thread_id,
+			instruction_,
+		)
+	}
 
 	this.Push(dma_command)
 }
@@ -183,7 +277,12 @@ func (this *Dma) Push(dma_command *dram.DmaCommand) {
 		panic(err)
 	}
 
+	dma_command.SetIssueCycle(this.memory_controller.Cycles())
 	this.input_q.Push(dma_command)
+
+	if this.dma_trace != nil && dma_command.HasThreadId() && dma_command.Size() > 0 {
+		this.issue_cycles[dma_command] = this.cycles
+	}
 }
 
 func (this *Dma) CanPop() bool {
@@ -202,6 +301,8 @@ func (this *Dma) Pop() *dram.DmaCommand {
 func (this *Dma) Cycle() {
 	this.ServiceInputQ()
 	this.ServiceReadyQ()
+
+	this.cycles++
 }
 
 func (this *Dma) ServiceInputQ() {
@@ -216,6 +317,11 @@ func (this *Dma) ServiceReadyQ() {
 		dma_command := this.memory_controller.Pop()
 		this.ready_q.Push(dma_command)
 
+		if issue_cycle, found := this.issue_cycles[dma_command]; found {
+			this.Trace(dma_command, issue_cycle)
+			delete(this.issue_cycles, dma_command)
+		}
+
 		if dma_command.MemoryOperation() == dram.READ {
 			wram_address := dma_command.WramAddress()
 			mram_address := dma_command.MramAddress()
@@ -223,6 +329,24 @@ func (this *Dma) ServiceReadyQ() {
 			byte_stream := dma_command.ByteStream(mram_address, size)
 
 			this.TransferToWram(wram_address, byte_stream)
+
+			if this.sanitizer != nil {
+				this.sanitizer.CopyMramToWram(wram_address, mram_address, size)
+			}
 		}
 	}
 }
+
+func (this *Dma) Trace(dma_command *dram.DmaCommand, issue_cycle int64) {
+	entry := new(dram.DmaTraceEntry)
+	entry.Init(
+		dma_command.ThreadId(),
+		issue_cycle,
+		this.cycles,
+		dma_command.MemoryOperation(),
+		dma_command.MramAddress(),
+		dma_command.Size(),
+	)
+
+	this.dma_trace.Append(entry)
+}
@@ -0,0 +1,201 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+const (
+	dma_alignment = 8
+	dma_max_size  = 2048
+
+	// NOTE: a kernel that violates a constraint in a loop would otherwise flood the output, so only
+	// the first violations of a DPU are printed and kept; the rest are only counted
+	dma_checker_max_num_reports = 16
+)
+
+// DmaChecker enforces the constraints of the UPMEM DMA engine on ldma/sdma: WRAM and MRAM addresses
+// aligned to 8 bytes and a size that is a multiple of 8 and at most 2048 bytes. In the strict
+// mode, the first violation faults the DPU and the transfer is suppressed. In the truncate mode,
+// the transfer is performed the way the hardware does: the low bits of the addresses and of the
+// size are dropped and the size is capped at 2048 bytes. Every violation is counted in both modes,
+// and the first dma_checker_max_num_reports of a DPU are printed and kept.
+type DmaChecker struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	symbol_table *symbol.SymbolTable
+
+	has_faulted bool
+	lines       []string
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *DmaChecker) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.symbol_table = nil
+
+	this.has_faulted = false
+	this.lines = make([]string, 0)
+
+	name := fmt.Sprintf("DmaChecker[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *DmaChecker) Fini() {
+}
+
+func (this *DmaChecker) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.symbol_table != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	this.symbol_table = symbol_table
+}
+
+func (this *DmaChecker) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *DmaChecker) Lines() []string {
+	return this.lines
+}
+
+func (this *DmaChecker) NumViolations() int64 {
+	return this.stat_factory.Value("num_violations")
+}
+
+func (this *DmaChecker) HasFaulted() bool {
+	return this.has_faulted
+}
+
+// Check returns the addresses and size to transfer and whether the transfer is performed.
+func (this *DmaChecker) Check(
+	thread *Thread,
+	pc int64,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) (int64, int64, int64, bool) {
+	violations := make([]string, 0)
+
+	if wram_address%dma_alignment != 0 {
+		violations = append(violations, "misaligned_wram_address")
+	}
+
+	if mram_address%dma_alignment != 0 {
+		violations = append(violations, "misaligned_mram_address")
+	}
+
+	// NOTE: an ldma/sdma encodes its size as (1 + n) * min_access_granularity with n < 256, so the
+	// size checks only fire if min_access_granularity is not a multiple of 8 or is larger than 8
+	if size%dma_alignment != 0 {
+		violations = append(violations, "misaligned_size")
+	}
+
+	if size > dma_max_size {
+		violations = append(violations, "oversized_transfer")
+	}
+
+	if len(violations) == 0 {
+		return wram_address, mram_address, size, true
+	}
+
+	for _, violation := range violations {
+		this.stat_factory.Increment(violation, 1)
+	}
+
+	this.stat_factory.Increment("num_violations", 1)
+
+	if this.NumViolations() <= dma_checker_max_num_reports {
+		this.Report(thread, pc, wram_address, mram_address, size, is_ldma, violations)
+	}
+
+	if global.DmaCheck == "strict" {
+		this.has_faulted = true
+		return wram_address, mram_address, size, false
+	}
+
+	wram_address -= wram_address % dma_alignment
+	mram_address -= mram_address % dma_alignment
+	size -= size % dma_alignment
+	if size > dma_max_size {
+		size = dma_max_size
+	}
+
+	this.stat_factory.Increment("num_truncations", 1)
+
+	return wram_address, mram_address, size, size > 0
+}
+
+func (this *DmaChecker) Report(
+	thread *Thread,
+	pc int64,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+	violations []string,
+) {
+	var name string
+	if is_ldma {
+		name = "ldma"
+	} else {
+		name = "sdma"
+	}
+
+	symbol_ := fmt.Sprintf("%d", pc)
+	if this.symbol_table != nil {
+		symbol_ = this.symbol_table.Symbolize(pc)
+	}
+
+	line := fmt.Sprintf(
+		"DPU%d-%d-%d tasklet %d at %d (%s): %s of %d bytes with WRAM address %d and MRAM address %d: %s",
+		this.channel_id,
+		this.rank_id,
+		this.dpu_id,
+		thread.ThreadId(),
+		pc,
+		symbol_,
+		name,
+		size,
+		wram_address,
+		mram_address,
+		strings.Join(violations, ", "),
+	)
+
+	fmt.Printf("DMA checker: %s\n", line)
+	this.lines = append(this.lines, line)
+
+	if this.NumViolations() == dma_checker_max_num_reports {
+		fmt.Printf(
+			"DMA checker: DPU%d-%d-%d: further violations are only counted\n",
+			this.channel_id,
+			this.rank_id,
+			this.dpu_id,
+		)
+	}
+}
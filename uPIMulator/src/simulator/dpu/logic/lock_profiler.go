@@ -0,0 +1,162 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+// LockProfiler keeps contention statistics of the atomic bits of a single DPU. Per atomic bit, it
+// counts acquire attempts and failures, the cycles the bit is held (acquire to release) and the
+// cycles tasklets spin on it (first failed acquire to successful acquire). Per tasklet, it counts
+// the cycles spent spinning and the cycles spent sleeping between a stop and the resume/boot that
+// wakes it up, which is how the SDK barrier and semaphore wait. Atomic bits are named after the
+// mutex, barrier or semaphore symbol they belong to.
+type LockProfiler struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	symbol_table *symbol.SymbolTable
+
+	cycles int64
+
+	acquire_cycles map[int64]int64
+	spin_cycles    []int64
+	sleep_cycles   []int64
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *LockProfiler) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.symbol_table = nil
+
+	this.cycles = 0
+
+	name := fmt.Sprintf("LockProfiler[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+
+	this.Boot()
+}
+
+func (this *LockProfiler) Fini() {
+}
+
+func (this *LockProfiler) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.symbol_table != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	this.symbol_table = symbol_table
+}
+
+func (this *LockProfiler) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Boot forgets the locks held and the tasklets waiting in the previous launch.
+func (this *LockProfiler) Boot() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.acquire_cycles = make(map[int64]int64, 0)
+
+	this.spin_cycles = make([]int64, config_loader.MaxNumTasklets())
+	this.sleep_cycles = make([]int64, config_loader.MaxNumTasklets())
+	for i := 0; i < config_loader.MaxNumTasklets(); i++ {
+		this.spin_cycles[i] = -1
+		this.sleep_cycles[i] = -1
+	}
+}
+
+func (this *LockProfiler) Cycle() {
+	this.cycles++
+}
+
+func (this *LockProfiler) Acquire(thread_id int, atomic_address int64, can_acquire bool) {
+	name := this.LockName(atomic_address)
+
+	this.stat_factory.Increment(name+"_acquire_attempts", 1)
+
+	if !can_acquire {
+		this.stat_factory.Increment(name+"_acquire_failures", 1)
+
+		if this.spin_cycles[thread_id] < 0 {
+			this.spin_cycles[thread_id] = this.cycles
+		}
+		return
+	}
+
+	if this.spin_cycles[thread_id] >= 0 {
+		spin_cycles := this.cycles - this.spin_cycles[thread_id]
+
+		this.stat_factory.Increment(name+"_spin_cycles", spin_cycles)
+		this.stat_factory.Increment(fmt.Sprintf("tasklet%d_spin_cycles", thread_id), spin_cycles)
+		this.stat_factory.Increment(fmt.Sprintf("tasklet%d_sync_cycles", thread_id), spin_cycles)
+
+		this.spin_cycles[thread_id] = -1
+	}
+
+	this.acquire_cycles[atomic_address] = this.cycles
+}
+
+func (this *LockProfiler) Release(thread_id int, atomic_address int64) {
+	if acquire_cycle, found := this.acquire_cycles[atomic_address]; found {
+		name := this.LockName(atomic_address)
+
+		this.stat_factory.Increment(name+"_held_cycles", this.cycles-acquire_cycle)
+
+		delete(this.acquire_cycles, atomic_address)
+	}
+}
+
+func (this *LockProfiler) Stop(thread_id int) {
+	this.sleep_cycles[thread_id] = this.cycles
+}
+
+// Awake is called when a tasklet is resumed or booted by another tasklet.
+func (this *LockProfiler) Awake(thread_id int) {
+	if this.sleep_cycles[thread_id] >= 0 {
+		sleep_cycles := this.cycles - this.sleep_cycles[thread_id]
+
+		this.stat_factory.Increment(fmt.Sprintf("tasklet%d_sleep_cycles", thread_id), sleep_cycles)
+		this.stat_factory.Increment(fmt.Sprintf("tasklet%d_sync_cycles", thread_id), sleep_cycles)
+
+		this.sleep_cycles[thread_id] = -1
+	}
+}
+
+// LockName maps an atomic address to the mutex, barrier or semaphore it belongs to, e.g.
+// __atomic_bit_mutex_my_mutex to mutex_my_mutex.
+func (this *LockProfiler) LockName(atomic_address int64) string {
+	if this.symbol_table == nil {
+		return fmt.Sprintf("atomic_%d", atomic_address)
+	}
+
+	name := this.symbol_table.Symbolize(atomic_address)
+	if _, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return fmt.Sprintf("atomic_%d", atomic_address)
+	}
+
+	return strings.TrimPrefix(name, "__atomic_bit_")
+}
@@ -23,14 +23,22 @@ type Logic struct {
 	iram              *sram.Iram
 	operand_collector *OperandCollector
 	dma               *Dma
+	debug_unit        *DebugUnit
+	sanitizer         *Sanitizer
+	dma_checker       *DmaChecker
+	race_detector     *RaceDetector
+	lock_profiler     *LockProfiler
+
+	soft_float_profiler *SoftFloatProfiler
 
 	scoreboard map[*instruction.Instruction]*Thread
 
 	pipeline   *Pipeline
 	cycle_rule *CycleRule
 
-	alu    *Alu
-	wait_q *InstructionQ
+	alu          *Alu
+	perf_counter *PerfCounter
+	wait_q       *InstructionQ
 
 	stat_factory *misc.StatFactory
 }
@@ -60,6 +68,12 @@ func (this *Logic) Init(
 	this.iram = nil
 	this.operand_collector = nil
 	this.dma = nil
+	this.debug_unit = nil
+	this.sanitizer = nil
+	this.dma_checker = nil
+	this.race_detector = nil
+	this.lock_profiler = nil
+	this.soft_float_profiler = nil
 
 	this.scoreboard = make(map[*instruction.Instruction]*Thread, 0)
 
@@ -72,6 +86,9 @@ func (this *Logic) Init(
 	this.alu = new(Alu)
 	this.alu.Init()
 
+	this.perf_counter = new(PerfCounter)
+	this.perf_counter.Init()
+
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
@@ -88,6 +105,7 @@ func (this *Logic) Fini() {
 	this.cycle_rule.Fini()
 
 	this.alu.Fini()
+	this.perf_counter.Fini()
 	this.wait_q.Fini()
 }
 
@@ -136,6 +154,60 @@ func (this *Logic) ConnectDma(dma *Dma) {
 	this.dma = dma
 }
 
+func (this *Logic) ConnectDebugUnit(debug_unit *DebugUnit) {
+	if this.debug_unit != nil {
+		err := errors.New("debug unit is already set")
+		panic(err)
+	}
+
+	this.debug_unit = debug_unit
+}
+
+func (this *Logic) ConnectDmaChecker(dma_checker *DmaChecker) {
+	if this.dma_checker != nil {
+		err := errors.New("DMA checker is already set")
+		panic(err)
+	}
+
+	this.dma_checker = dma_checker
+}
+
+func (this *Logic) ConnectSanitizer(sanitizer *Sanitizer) {
+	if this.sanitizer != nil {
+		err := errors.New("sanitizer is already set")
+		panic(err)
+	}
+
+	this.sanitizer = sanitizer
+}
+
+func (this *Logic) ConnectRaceDetector(race_detector *RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+}
+
+func (this *Logic) ConnectLockProfiler(lock_profiler *LockProfiler) {
+	if this.lock_profiler != nil {
+		err := errors.New("lock profiler is already set")
+		panic(err)
+	}
+
+	this.lock_profiler = lock_profiler
+}
+
+func (this *Logic) ConnectSoftFloatProfiler(soft_float_profiler *SoftFloatProfiler) {
+	if this.soft_float_profiler != nil {
+		err := errors.New("soft-float profiler is already set")
+		panic(err)
+	}
+
+	this.soft_float_profiler = soft_float_profiler
+}
+
 func (this *Logic) CycleRule() *CycleRule {
 	return this.cycle_rule
 }
@@ -160,6 +232,9 @@ func (this *Logic) Cycle() {
 
 	this.wait_q.Cycle()
 
+	this.lock_profiler.Cycle()
+	this.perf_counter.Cycle()
+
 	this.stat_factory.Increment("logic_cycle", 1)
 }
 
@@ -177,12 +252,23 @@ func (this *Logic) ServiceThreadScheduler() {
 
 			if instruction_.Suffix() != instruction.DMA_RRI {
 				this.ExecuteInstruction(instruction_, pc)
+
+				if this.sanitizer != nil {
+					this.sanitizer.CheckStackPointer(thread, pc)
+				}
 			} else {
 				this.thread_scheduler.Block(thread.ThreadId())
 				thread.RegFile().IncrementPcReg()
 				this.wait_q.Push(instruction_)
 			}
 
+			if this.debug_unit != nil {
+				this.debug_unit.Issue(thread.ThreadId())
+			}
+
+			this.perf_counter.Issue()
+			this.soft_float_profiler.Issue(pc)
+
 			this.stat_factory.Increment("num_instructions", 1)
 		}
 
@@ -438,8 +524,14 @@ func (this *Logic) ExecuteAcquireRici(instruction_ *instruction.Instruction) {
 	atomic_address := this.alu.AtomicAddressHash(ra, imm)
 
 	can_acquire := this.atomic.CanAcquire(atomic_address)
+	this.lock_profiler.Acquire(thread.ThreadId(), atomic_address, can_acquire)
+
 	if can_acquire {
 		this.atomic.Acquire(atomic_address, thread.ThreadId())
+
+		if this.race_detector != nil {
+			this.race_detector.Acquire(thread.ThreadId(), atomic_address)
+		}
 	}
 
 	thread.RegFile().ClearConditions()
@@ -447,6 +539,8 @@ func (this *Logic) ExecuteAcquireRici(instruction_ *instruction.Instruction) {
 		this.SetAcquireCc(instruction_, 0)
 	} else {
 		this.SetAcquireCc(instruction_, 1)
+
+		this.stat_factory.Increment("num_failed_acquires", 1)
 	}
 
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
@@ -481,6 +575,12 @@ func (this *Logic) ExecuteReleaseRici(instruction_ *instruction.Instruction) {
 	can_release := this.atomic.CanRelease(atomic_address, thread.ThreadId())
 	if can_release {
 		this.atomic.Release(atomic_address, thread.ThreadId())
+
+		this.lock_profiler.Release(thread.ThreadId(), atomic_address)
+
+		if this.race_detector != nil {
+			this.race_detector.Release(thread.ThreadId(), atomic_address)
+		}
 	}
 
 	thread.RegFile().ClearConditions()
@@ -524,6 +624,14 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 	op_code := instruction_.OpCode()
 	if op_code == instruction.BOOT {
 		can_boot := this.thread_scheduler.Boot(thread_id)
+		if can_boot {
+			this.lock_profiler.Awake(thread_id)
+		}
+
+		if can_boot && this.race_detector != nil {
+			this.race_detector.Signal(thread.ThreadId(), thread_id)
+		}
+
 		if can_boot {
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
@@ -533,6 +641,14 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 		}
 	} else if op_code == instruction.RESUME {
 		can_resume := this.thread_scheduler.Awake(thread_id)
+		if can_resume {
+			this.lock_profiler.Awake(thread_id)
+		}
+
+		if can_resume && this.race_detector != nil {
+			this.race_detector.Signal(thread.ThreadId(), thread_id)
+		}
+
 		if can_resume {
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
@@ -3696,8 +3812,7 @@ func (this *Logic) ExecuteRr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
+		result = this.perf_counter.Configure(ra)
 	} else {
 		err := errors.New("op code is not valid")
 		panic(err)
@@ -3916,8 +4031,7 @@ func (this *Logic) ExecuteZr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
+		result = this.perf_counter.Configure(ra)
 	} else {
 		err := errors.New("op code is not valid")
 		panic(err)
@@ -4644,8 +4758,31 @@ func (this *Logic) ExecuteSRirci(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteR(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteR is not yet implemented")
-	panic(err)
+	if _, found := instruction_.ROpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid R op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.R {
+		err := errors.New("suffix is not R")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.TIME {
+		result = this.perf_counter.Read()
+	} else {
+		err := errors.New("op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, false)
 }
 
 func (this *Logic) ExecuteRci(instruction_ *instruction.Instruction) {
@@ -4695,6 +4832,11 @@ func (this *Logic) ExecuteCi(instruction_ *instruction.Instruction) {
 	thread := this.scoreboard[instruction_]
 
 	this.thread_scheduler.Sleep(thread.ThreadId())
+	this.lock_profiler.Stop(thread.ThreadId())
+
+	if this.race_detector != nil {
+		this.race_detector.Stop(thread.ThreadId())
+	}
 
 	thread.RegFile().ClearConditions()
 
@@ -4801,6 +4943,13 @@ func (this *Logic) ExecuteErri(instruction_ *instruction.Instruction) {
 	var result int64
 
 	op_code := instruction_.OpCode()
+	if !this.AccessWram(instruction_, address, this.AccessSize(op_code), false) {
+		thread.RegFile().ClearConditions()
+		thread.RegFile().WriteGpReg(instruction_.Rc(), 0)
+		thread.RegFile().IncrementPcReg()
+		return
+	}
+
 	if op_code == instruction.LBS {
 		result = this.operand_collector.Lbs(address)
 	} else if op_code == instruction.LBU {
@@ -4846,6 +4995,13 @@ func (this *Logic) ExecuteEdri(instruction_ *instruction.Instruction) {
 	var odd int64
 
 	op_code := instruction_.OpCode()
+	if !this.AccessWram(instruction_, address, this.AccessSize(op_code), false) {
+		thread.RegFile().ClearConditions()
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+		thread.RegFile().IncrementPcReg()
+		return
+	}
+
 	if op_code == instruction.LD {
 		even, odd = this.operand_collector.Ld(address)
 	} else {
@@ -4876,6 +5032,12 @@ func (this *Logic) ExecuteErii(instruction_ *instruction.Instruction) {
 	address, _, _ := this.alu.Add(ra, off)
 
 	op_code := instruction_.OpCode()
+	if !this.AccessWram(instruction_, address, this.AccessSize(op_code), true) {
+		thread.RegFile().ClearConditions()
+		thread.RegFile().IncrementPcReg()
+		return
+	}
+
 	if op_code == instruction.SB {
 		this.operand_collector.Sb(address, imm)
 	} else if op_code == instruction.SB_ID {
@@ -4928,6 +5090,12 @@ func (this *Logic) ExecuteErir(instruction_ *instruction.Instruction) {
 	rb_word.SetValue(rb)
 
 	op_code := instruction_.OpCode()
+	if !this.AccessWram(instruction_, address, this.AccessSize(op_code), true) {
+		thread.RegFile().ClearConditions()
+		thread.RegFile().IncrementPcReg()
+		return
+	}
+
 	if op_code == instruction.SB {
 		this.operand_collector.Sb(address, rb_word.BitSlice(word.UNSIGNED, 0, 8))
 	} else if op_code == instruction.SH {
@@ -4961,6 +5129,12 @@ func (this *Logic) ExecuteErid(instruction_ *instruction.Instruction) {
 	address, _, _ := this.alu.Add(ra, off)
 
 	op_code := instruction_.OpCode()
+	if !this.AccessWram(instruction_, address, this.AccessSize(op_code), true) {
+		thread.RegFile().ClearConditions()
+		thread.RegFile().IncrementPcReg()
+		return
+	}
+
 	if op_code == instruction.SD {
 		this.operand_collector.Sd(address, even, odd)
 	} else {
@@ -5024,7 +5198,38 @@ func (this *Logic) ExecuteLdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	size := (1 + this.alu.And(imm+this.alu.And(this.alu.Lsr(ra, 24), 255), 255)) * global.MinAccessGranularity
 
-	this.dma.TransferFromMramToWram(wram_address, mram_address, size, instruction_)
+	if this.dma_checker != nil {
+		var is_valid bool
+		wram_address, mram_address, size, is_valid = this.dma_checker.Check(
+			thread,
+			this.Pc(instruction_),
+			wram_address,
+			mram_address,
+			size,
+			true,
+		)
+
+		if !is_valid {
+			this.dma.Discard(thread.ThreadId(), instruction_)
+			thread.RegFile().ClearConditions()
+			return
+		}
+	}
+
+	if !this.AccessDma(instruction_, wram_address, mram_address, size, true) {
+		this.dma.Discard(thread.ThreadId(), instruction_)
+		thread.RegFile().ClearConditions()
+		return
+	}
+
+	this.dma.TransferFromMramToWram(
+		wram_address,
+		mram_address,
+		size,
+		thread.ThreadId(),
+		this.Pc(instruction_),
+		instruction_,
+	)
 
 	thread.RegFile().ClearConditions()
 }
@@ -5064,11 +5269,132 @@ func (this *Logic) ExecuteSdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	size := (1 + this.alu.And(imm+this.alu.And(this.alu.Lsr(ra, 24), 255), 255)) * global.MinAccessGranularity
 
-	this.dma.TransferFromWramToMram(wram_address, mram_address, size, instruction_)
+	if this.dma_checker != nil {
+		var is_valid bool
+		wram_address, mram_address, size, is_valid = this.dma_checker.Check(
+			thread,
+			this.Pc(instruction_),
+			wram_address,
+			mram_address,
+			size,
+			false,
+		)
+
+		if !is_valid {
+			this.dma.Discard(thread.ThreadId(), instruction_)
+			thread.RegFile().ClearConditions()
+			return
+		}
+	}
+
+	if !this.AccessDma(instruction_, wram_address, mram_address, size, false) {
+		this.dma.Discard(thread.ThreadId(), instruction_)
+		thread.RegFile().ClearConditions()
+		return
+	}
+
+	this.dma.TransferFromWramToMram(
+		wram_address,
+		mram_address,
+		size,
+		thread.ThreadId(),
+		this.Pc(instruction_),
+		instruction_,
+	)
 
 	thread.RegFile().ClearConditions()
 }
 
+// AccessWram is called right before a load or store reads or writes size bytes at address. It
+// returns false if the access must be suppressed.
+func (this *Logic) AccessWram(
+	instruction_ *instruction.Instruction,
+	address int64,
+	size int64,
+	is_write bool,
+) bool {
+	thread := this.scoreboard[instruction_]
+
+	if this.debug_unit != nil {
+		this.debug_unit.Access(thread.ThreadId(), address, size, is_write)
+	}
+
+	if this.sanitizer != nil &&
+		!this.sanitizer.CheckWram(thread, this.Pc(instruction_), address, size, is_write) {
+		return false
+	}
+
+	if this.race_detector != nil {
+		this.race_detector.AccessWram(thread.ThreadId(), this.Pc(instruction_), address, size, is_write)
+	}
+
+	return true
+}
+
+// AccessDma is called right before an ldma or sdma is handed to the DMA engine. It returns false
+// if the transfer must be suppressed.
+func (this *Logic) AccessDma(
+	instruction_ *instruction.Instruction,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) bool {
+	thread := this.scoreboard[instruction_]
+
+	if this.debug_unit != nil {
+		this.debug_unit.Access(thread.ThreadId(), mram_address, size, !is_ldma)
+		this.debug_unit.Access(thread.ThreadId(), wram_address, size, is_ldma)
+	}
+
+	if this.sanitizer != nil &&
+		!this.sanitizer.CheckDma(thread, this.Pc(instruction_), wram_address, mram_address, size, is_ldma) {
+		return false
+	}
+
+	if this.race_detector != nil {
+		pc := this.Pc(instruction_)
+
+		this.race_detector.AccessMram(thread.ThreadId(), pc, mram_address, size, !is_ldma)
+		this.race_detector.AccessWram(thread.ThreadId(), pc, wram_address, size, is_ldma)
+	}
+
+	return true
+}
+
+// Pc returns the IRAM address of an instruction that is being executed. DMA instructions are
+// executed after their thread's PC has already been incremented at issue.
+func (this *Logic) Pc(instruction_ *instruction.Instruction) int64 {
+	thread := this.scoreboard[instruction_]
+	pc := thread.RegFile().ReadPcReg()
+
+	if instruction_.Suffix() == instruction.DMA_RRI {
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		pc -= int64(config_loader.IramDataWidth() / 8)
+	}
+
+	return pc
+}
+
+func (this *Logic) AccessSize(op_code instruction.OpCode) int64 {
+	if op_code == instruction.LBS || op_code == instruction.LBU || op_code == instruction.SB ||
+		op_code == instruction.SB_ID {
+		return 1
+	} else if op_code == instruction.LHS || op_code == instruction.LHU || op_code == instruction.SH ||
+		op_code == instruction.SH_ID {
+		return 2
+	} else if op_code == instruction.LW || op_code == instruction.SW || op_code == instruction.SW_ID {
+		return 4
+	} else if op_code == instruction.LD || op_code == instruction.SD || op_code == instruction.SD_ID {
+		return 8
+	} else {
+		err := errors.New("op code is not a valid load/store op code")
+		panic(err)
+	}
+}
+
 func (this *Logic) SetAcquireCc(instruction_ *instruction.Instruction, result int64) {
 	thread := this.scoreboard[instruction_]
 
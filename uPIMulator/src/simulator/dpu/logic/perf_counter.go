@@ -0,0 +1,72 @@
+package logic
+
+import (
+	"errors"
+)
+
+type PerfCounterConfig int
+
+const (
+	COUNT_SAME PerfCounterConfig = iota
+	COUNT_CYCLES
+	COUNT_INSTRUCTIONS
+	COUNT_NOTHING
+)
+
+// PerfCounter is the performance counter register of a DPU that perfcounter_config and
+// perfcounter_get of the SDK use through time_cfg and time. It counts logic cycles or issued
+// instructions. Like the hardware, both instructions return the counter without its lowest 4
+// bits, which the SDK shifts back.
+type PerfCounter struct {
+	config PerfCounterConfig
+	value  int64
+}
+
+// NOTE: the SDK shifts the values returned by time and time_cfg back by this many bits
+const perf_counter_imprecision = 4
+
+func (this *PerfCounter) Init() {
+	this.config = COUNT_NOTHING
+	this.value = 0
+}
+
+func (this *PerfCounter) Fini() {
+}
+
+// Configure applies a time_cfg operand, whose bit 0 resets the counter and whose bits 1-2 select
+// what to count, and returns the counter from before the reset.
+func (this *PerfCounter) Configure(operand int64) int64 {
+	result := this.Read()
+
+	config := PerfCounterConfig((operand >> 1) & 0x3)
+	if config < COUNT_SAME || config > COUNT_NOTHING {
+		err := errors.New("perf counter config is not valid")
+		panic(err)
+	}
+
+	if config != COUNT_SAME {
+		this.config = config
+	}
+
+	if operand&0x1 != 0 {
+		this.value = 0
+	}
+
+	return result
+}
+
+func (this *PerfCounter) Read() int64 {
+	return (this.value >> perf_counter_imprecision) & 0xffffffff
+}
+
+func (this *PerfCounter) Cycle() {
+	if this.config == COUNT_CYCLES {
+		this.value++
+	}
+}
+
+func (this *PerfCounter) Issue() {
+	if this.config == COUNT_INSTRUCTIONS {
+		this.value++
+	}
+}
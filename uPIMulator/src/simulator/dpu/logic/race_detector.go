@@ -0,0 +1,293 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+type VectorClock []int64
+
+func (this VectorClock) Copy() VectorClock {
+	vector_clock := make(VectorClock, len(this))
+	copy(vector_clock, this)
+	return vector_clock
+}
+
+func (this VectorClock) Join(other VectorClock) {
+	for i := range this {
+		if other[i] > this[i] {
+			this[i] = other[i]
+		}
+	}
+}
+
+// Epoch is a single access of a tasklet at its own clock.
+type Epoch struct {
+	thread_id int
+	clock     int64
+	pc        int64
+}
+
+// Shadow is the access history of a byte of WRAM or a granule of MRAM since its last write.
+type Shadow struct {
+	write *Epoch
+	reads []*Epoch
+}
+
+// RaceDetector is a happens-before data race detector for the tasklets of a single DPU. Each
+// tasklet has a vector clock which is synchronized by
+//
+//	acquire/release on an atomic bit (the lock carries the releaser's clock to the next acquirer)
+//	boot/resume (the target tasklet inherits the clock of the booting/resuming tasklet)
+//	stop (the stopping tasklet starts a new epoch)
+//
+// which covers mutex, barrier, semaphore and handshake of the SDK. WRAM is shadowed per byte and
+// MRAM per min_access_granularity; MRAM is only accessed through ldma/sdma. The history is reset
+// at every launch since the host synchronizes with all tasklets in between.
+type RaceDetector struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	symbol_table *symbol.SymbolTable
+
+	thread_clocks []VectorClock
+	lock_clocks   map[int64]VectorClock
+
+	wram_shadows []*Shadow
+	mram_shadows map[int64]*Shadow
+
+	reported map[string]bool
+	lines    []string
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *RaceDetector) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.symbol_table = nil
+
+	this.reported = make(map[string]bool, 0)
+	this.lines = make([]string, 0)
+
+	name := fmt.Sprintf("RaceDetector[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+
+	this.Boot()
+}
+
+func (this *RaceDetector) Fini() {
+}
+
+func (this *RaceDetector) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.symbol_table != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	this.symbol_table = symbol_table
+}
+
+func (this *RaceDetector) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *RaceDetector) Lines() []string {
+	return this.lines
+}
+
+// Boot forgets the clocks and the access history of the previous launch.
+func (this *RaceDetector) Boot() {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	max_num_tasklets := config_loader.MaxNumTasklets()
+
+	this.thread_clocks = make([]VectorClock, 0)
+	for i := 0; i < max_num_tasklets; i++ {
+		vector_clock := make(VectorClock, max_num_tasklets)
+		vector_clock[i] = 1
+
+		this.thread_clocks = append(this.thread_clocks, vector_clock)
+	}
+
+	this.lock_clocks = make(map[int64]VectorClock, 0)
+
+	this.wram_shadows = make([]*Shadow, config_loader.WramSize())
+	this.mram_shadows = make(map[int64]*Shadow, 0)
+}
+
+func (this *RaceDetector) Acquire(thread_id int, atomic_address int64) {
+	if lock_clock, found := this.lock_clocks[atomic_address]; found {
+		this.thread_clocks[thread_id].Join(lock_clock)
+	}
+}
+
+func (this *RaceDetector) Release(thread_id int, atomic_address int64) {
+	this.lock_clocks[atomic_address] = this.thread_clocks[thread_id].Copy()
+	this.thread_clocks[thread_id][thread_id]++
+}
+
+// Signal is called when thread_id successfully boots or resumes target_thread_id.
+func (this *RaceDetector) Signal(thread_id int, target_thread_id int) {
+	this.thread_clocks[target_thread_id].Join(this.thread_clocks[thread_id])
+	this.thread_clocks[thread_id][thread_id]++
+}
+
+func (this *RaceDetector) Stop(thread_id int) {
+	this.thread_clocks[thread_id][thread_id]++
+}
+
+func (this *RaceDetector) AccessWram(thread_id int, pc int64, address int64, size int64, is_write bool) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for i := address; i < address+size; i++ {
+		index := i - config_loader.WramOffset()
+		if index < 0 || index >= int64(len(this.wram_shadows)) {
+			continue
+		}
+
+		if this.wram_shadows[index] == nil {
+			this.wram_shadows[index] = new(Shadow)
+		}
+
+		if this.Check(this.wram_shadows[index], thread_id, pc, is_write, i) {
+			break
+		}
+	}
+}
+
+func (this *RaceDetector) AccessMram(thread_id int, pc int64, address int64, size int64, is_write bool) {
+	begin_address := address / global.MinAccessGranularity * global.MinAccessGranularity
+
+	for i := begin_address; i < address+size; i += global.MinAccessGranularity {
+		if _, found := this.mram_shadows[i]; !found {
+			this.mram_shadows[i] = new(Shadow)
+		}
+
+		if this.Check(this.mram_shadows[i], thread_id, pc, is_write, i) {
+			break
+		}
+	}
+}
+
+// Check updates the shadow with an access and returns true if the access races with a previous
+// one, in which case the rest of the access is not checked to avoid one report per byte.
+func (this *RaceDetector) Check(
+	shadow *Shadow,
+	thread_id int,
+	pc int64,
+	is_write bool,
+	address int64,
+) bool {
+	vector_clock := this.thread_clocks[thread_id]
+
+	epoch := new(Epoch)
+	epoch.thread_id = thread_id
+	epoch.clock = vector_clock[thread_id]
+	epoch.pc = pc
+
+	has_raced := false
+
+	if shadow.write != nil && shadow.write.thread_id != thread_id &&
+		shadow.write.clock > vector_clock[shadow.write.thread_id] {
+		this.Report(address, shadow.write, true, epoch, is_write)
+		has_raced = true
+	}
+
+	if is_write {
+		for _, read := range shadow.reads {
+			if read.thread_id != thread_id && read.clock > vector_clock[read.thread_id] {
+				this.Report(address, read, false, epoch, is_write)
+				has_raced = true
+				break
+			}
+		}
+
+		shadow.write = epoch
+		shadow.reads = nil
+	} else {
+		for i, read := range shadow.reads {
+			if read.thread_id == thread_id {
+				shadow.reads = append(shadow.reads[:i], shadow.reads[i+1:]...)
+				break
+			}
+		}
+
+		shadow.reads = append(shadow.reads, epoch)
+	}
+
+	return has_raced
+}
+
+// Report records a race once per pair of PCs.
+func (this *RaceDetector) Report(
+	address int64,
+	previous *Epoch,
+	is_previous_write bool,
+	current *Epoch,
+	is_current_write bool,
+) {
+	this.stat_factory.Increment("num_races", 1)
+
+	key := fmt.Sprintf("%d:%d", previous.pc, current.pc)
+	if this.reported[key] {
+		return
+	}
+	this.reported[key] = true
+
+	line := fmt.Sprintf(
+		"DPU%d-%d-%d data race on %s: %s by tasklet %d at %d (%s) and %s by tasklet %d at %d (%s)",
+		this.channel_id,
+		this.rank_id,
+		this.dpu_id,
+		this.Symbolize(address),
+		this.StringifyAccess(is_previous_write),
+		previous.thread_id,
+		previous.pc,
+		this.Symbolize(previous.pc),
+		this.StringifyAccess(is_current_write),
+		current.thread_id,
+		current.pc,
+		this.Symbolize(current.pc),
+	)
+
+	fmt.Printf("race detector: %s\n", line)
+	this.lines = append(this.lines, line)
+}
+
+func (this *RaceDetector) StringifyAccess(is_write bool) string {
+	if is_write {
+		return "write"
+	} else {
+		return "read"
+	}
+}
+
+func (this *RaceDetector) Symbolize(address int64) string {
+	if this.symbol_table == nil {
+		return strconv.FormatInt(address, 10)
+	}
+
+	return this.symbol_table.Symbolize(address)
+}
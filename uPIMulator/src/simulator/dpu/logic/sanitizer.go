@@ -0,0 +1,638 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/global"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+type SanitizerViolation int
+
+const (
+	STACK_OVERFLOW SanitizerViolation = iota
+	CROSS_TASKLET_STACK_ACCESS
+	UNINITIALIZED_READ
+	OUT_OF_BOUNDS_WRAM_ACCESS
+	OUT_OF_BOUNDS_DMA
+	MISALIGNED_DMA
+	OUT_OF_HEAP_ACCESS
+	OUT_OF_REGION_DMA
+)
+
+// NOTE: the DPU ABI uses r22 as the stack pointer
+const stack_pointer_index = 22
+
+// Sanitizer checks the memory accesses of a single DPU. The stack of tasklet N is
+// [__sys_stack_thread_N, __sys_stack_thread_N + STACK_SIZE_TASKLET_N). The WRAM heap is
+// [__sys_heap_pointer_reset, __sys_heap_pointer), i.e., what mem_alloc has handed out so far, and
+// the MRAM heap starts at __sys_used_mram_end. Initialized bytes are tracked per byte for WRAM and
+// per min_access_granularity for MRAM; loading the program initializes the sections that the
+// linker lays out, the host's transfers initialize memory, stores and DMAs propagate the
+// initialization state, and only loads into registers are reported.
+//
+// The MRAM heap is also tracked as the regions that the host has written. An ldma that reads MRAM
+// heap that neither the host nor the DPU has written, and an sdma that runs past the end of a
+// region that the host has written, are reported.
+//
+// Out-of-bounds accesses, which would otherwise end the simulation with a panic, are reported and
+// suppressed: loads return 0, stores are dropped and DMAs complete without transferring a byte.
+type Sanitizer struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	symbol_table      *symbol.SymbolTable
+	operand_collector *OperandCollector
+
+	stack_begin_addresses   []int64
+	stack_end_addresses     []int64
+	data_end_address        int64
+	heap_begin_address      int64
+	heap_pointer_address    int64
+	mram_heap_begin_address int64
+	is_stack_pointer_armed  []bool
+
+	mram_region_begin_addresses []int64
+	mram_region_end_addresses   []int64
+
+	wram_initialized []bool
+	mram_initialized []uint64
+
+	reported map[string]bool
+	lines    []string
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *Sanitizer) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.symbol_table = nil
+	this.operand_collector = nil
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.stack_begin_addresses = make([]int64, 0)
+	this.stack_end_addresses = make([]int64, 0)
+	this.data_end_address = config_loader.WramOffset() + config_loader.WramSize()
+	this.heap_begin_address = config_loader.WramOffset() + config_loader.WramSize()
+	this.heap_pointer_address = -1
+	this.mram_heap_begin_address = config_loader.MramOffset() + config_loader.MramSize()
+	this.is_stack_pointer_armed = make([]bool, config_loader.MaxNumTasklets())
+
+	this.mram_region_begin_addresses = make([]int64, 0)
+	this.mram_region_end_addresses = make([]int64, 0)
+
+	this.wram_initialized = make([]bool, config_loader.WramSize())
+
+	num_mram_granules := config_loader.MramSize() / global.MinAccessGranularity
+	this.mram_initialized = make([]uint64, (num_mram_granules+63)/64)
+
+	this.reported = make(map[string]bool, 0)
+	this.lines = make([]string, 0)
+
+	name := fmt.Sprintf("Sanitizer[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *Sanitizer) Fini() {
+}
+
+func (this *Sanitizer) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.symbol_table != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	this.symbol_table = symbol_table
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for i := 0; i < config_loader.MaxNumTasklets(); i++ {
+		sys_stack_thread := "__sys_stack_thread_" + strconv.Itoa(i)
+		stack_size_tasklet := "STACK_SIZE_TASKLET_" + strconv.Itoa(i)
+
+		if !symbol_table.HasValue(sys_stack_thread) || !symbol_table.HasValue(stack_size_tasklet) {
+			break
+		}
+
+		stack_begin_address := symbol_table.Value(sys_stack_thread)
+		stack_end_address := stack_begin_address + symbol_table.Value(stack_size_tasklet)
+
+		this.stack_begin_addresses = append(this.stack_begin_addresses, stack_begin_address)
+		this.stack_end_addresses = append(this.stack_end_addresses, stack_end_address)
+	}
+
+	// NOTE: the linker lays out the data sections right below the stack of tasklet 0
+	if len(this.stack_begin_addresses) > 0 {
+		this.data_end_address = this.stack_begin_addresses[0]
+	}
+
+	if symbol_table.HasValue("__sys_heap_pointer_reset") {
+		this.heap_begin_address = symbol_table.Value("__sys_heap_pointer_reset")
+	}
+
+	// NOTE: __sys_heap_pointer is only linked if the program calls mem_alloc or mem_reset
+	if symbol_table.HasAddress("__sys_heap_pointer") {
+		this.heap_pointer_address = symbol_table.Address("__sys_heap_pointer")
+	}
+
+	if symbol_table.HasValue("__sys_used_mram_end") {
+		this.mram_heap_begin_address = symbol_table.Value("__sys_used_mram_end")
+	}
+}
+
+func (this *Sanitizer) ConnectOperandCollector(operand_collector *OperandCollector) {
+	if this.operand_collector != nil {
+		err := errors.New("operand collector is already set")
+		panic(err)
+	}
+
+	this.operand_collector = operand_collector
+}
+
+func (this *Sanitizer) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *Sanitizer) Lines() []string {
+	return this.lines
+}
+
+// Boot disarms the stack pointer checks until the bootstrap code has set up each tasklet's stack.
+func (this *Sanitizer) Boot() {
+	for i := range this.is_stack_pointer_armed {
+		this.is_stack_pointer_armed[i] = false
+	}
+}
+
+// LoadWram marks the bytes of the WRAM image that the host loads as initialized. Only the sections
+// that the linker lays out are initialized: the loader also writes the zeros of .bss, so a global
+// without an initializer reads as 0, while the stacks, the software cache and the heap are left
+// uninitialized.
+func (this *Sanitizer) LoadWram(address int64, size int64) {
+	for i := range this.wram_initialized {
+		this.wram_initialized[i] = false
+	}
+
+	this.InitializeWram(address, min(address+size, this.data_end_address)-address)
+}
+
+func (this *Sanitizer) InitializeWram(address int64, size int64) {
+	for i := address; i < address+size; i++ {
+		if this.IsInWram(i, 1) {
+			this.wram_initialized[this.WramIndex(i)] = true
+		}
+	}
+}
+
+func (this *Sanitizer) InitializeMram(address int64, size int64) {
+	for i := address; i < address+size; i += global.MinAccessGranularity {
+		this.SetMramInitialized(i, true)
+	}
+
+	if address+size > this.mram_heap_begin_address {
+		this.AddMramRegion(max(address, this.mram_heap_begin_address), address+size)
+	}
+}
+
+// AddMramRegion adds a region of the MRAM heap that the host has written. Overlapping and adjacent
+// regions are merged, so that a buffer that the host writes in several transfers is one region.
+func (this *Sanitizer) AddMramRegion(begin_address int64, end_address int64) {
+	begin_addresses := make([]int64, 0)
+	end_addresses := make([]int64, 0)
+
+	is_added := false
+	for i, region_begin_address := range this.mram_region_begin_addresses {
+		region_end_address := this.mram_region_end_addresses[i]
+
+		if region_end_address < begin_address {
+			begin_addresses = append(begin_addresses, region_begin_address)
+			end_addresses = append(end_addresses, region_end_address)
+		} else if end_address < region_begin_address {
+			if !is_added {
+				begin_addresses = append(begin_addresses, begin_address)
+				end_addresses = append(end_addresses, end_address)
+				is_added = true
+			}
+
+			begin_addresses = append(begin_addresses, region_begin_address)
+			end_addresses = append(end_addresses, region_end_address)
+		} else {
+			begin_address = min(begin_address, region_begin_address)
+			end_address = max(end_address, region_end_address)
+		}
+	}
+
+	if !is_added {
+		begin_addresses = append(begin_addresses, begin_address)
+		end_addresses = append(end_addresses, end_address)
+	}
+
+	this.mram_region_begin_addresses = begin_addresses
+	this.mram_region_end_addresses = end_addresses
+}
+
+// MramRegion returns the region of the MRAM heap that the host has written and that holds address.
+func (this *Sanitizer) MramRegion(address int64) (int64, int64, bool) {
+	for i, region_begin_address := range this.mram_region_begin_addresses {
+		region_end_address := this.mram_region_end_addresses[i]
+
+		if region_begin_address <= address && address < region_end_address {
+			return region_begin_address, region_end_address, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+func (this *Sanitizer) CopyWramToMram(wram_address int64, mram_address int64, size int64) {
+	for i := int64(0); i < size; i += global.MinAccessGranularity {
+		is_initialized := true
+		for j := i; j < i+global.MinAccessGranularity && j < size; j++ {
+			is_initialized = is_initialized && this.wram_initialized[this.WramIndex(wram_address+j)]
+		}
+
+		this.SetMramInitialized(mram_address+i, is_initialized)
+	}
+}
+
+func (this *Sanitizer) CopyMramToWram(wram_address int64, mram_address int64, size int64) {
+	for i := int64(0); i < size; i++ {
+		this.wram_initialized[this.WramIndex(wram_address+i)] = this.IsMramInitialized(mram_address + i)
+	}
+}
+
+// CheckWram checks a load or store of size bytes at address and returns false if the access must
+// be suppressed.
+func (this *Sanitizer) CheckWram(
+	thread *Thread,
+	pc int64,
+	address int64,
+	size int64,
+	is_write bool,
+) bool {
+	if !this.IsInWram(address, size) {
+		this.Report(
+			OUT_OF_BOUNDS_WRAM_ACCESS,
+			thread,
+			pc,
+			fmt.Sprintf("%d-byte access at %s is out of WRAM", size, this.Symbolize(address)),
+		)
+		return false
+	}
+
+	if heap_end_address, ok := this.HeapEndAddress(); ok && address+size > heap_end_address {
+		this.Report(
+			OUT_OF_HEAP_ACCESS,
+			thread,
+			pc,
+			fmt.Sprintf(
+				"%d-byte access at %s is past the end of the heap (heap+%d)",
+				size,
+				this.Symbolize(address),
+				heap_end_address-this.heap_begin_address,
+			),
+		)
+	}
+
+	for i, stack_begin_address := range this.stack_begin_addresses {
+		stack_end_address := this.stack_end_addresses[i]
+
+		if i != thread.ThreadId() && address < stack_end_address && stack_begin_address < address+size {
+			this.Report(
+				CROSS_TASKLET_STACK_ACCESS,
+				thread,
+				pc,
+				fmt.Sprintf("%d-byte access at %d is in the stack of tasklet %d", size, address, i),
+			)
+		}
+	}
+
+	if is_write {
+		this.InitializeWram(address, size)
+	} else {
+		for i := address; i < address+size; i++ {
+			if !this.wram_initialized[this.WramIndex(i)] {
+				this.Report(
+					UNINITIALIZED_READ,
+					thread,
+					pc,
+					fmt.Sprintf("%d-byte load at %s reads uninitialized memory", size, this.Symbolize(address)),
+				)
+				break
+			}
+		}
+	}
+
+	return true
+}
+
+// CheckDma checks an ldma or sdma and returns false if the transfer must be suppressed.
+func (this *Sanitizer) CheckDma(
+	thread *Thread,
+	pc int64,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) bool {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	var name string
+	if is_ldma {
+		name = "ldma"
+	} else {
+		name = "sdma"
+	}
+
+	is_valid := true
+
+	if !this.IsInWram(wram_address, size) {
+		this.Report(
+			OUT_OF_BOUNDS_DMA,
+			thread,
+			pc,
+			fmt.Sprintf("%s of %d bytes at WRAM address %d is out of WRAM", name, size, wram_address),
+		)
+		is_valid = false
+	}
+
+	if mram_address < config_loader.MramOffset() ||
+		mram_address+size > config_loader.MramOffset()+config_loader.MramSize() {
+		this.Report(
+			OUT_OF_BOUNDS_DMA,
+			thread,
+			pc,
+			fmt.Sprintf("%s of %d bytes at MRAM address %d is out of MRAM", name, size, mram_address),
+		)
+		is_valid = false
+	}
+
+	if wram_address%global.MinAccessGranularity != 0 || mram_address%global.MinAccessGranularity != 0 {
+		this.Report(
+			MISALIGNED_DMA,
+			thread,
+			pc,
+			fmt.Sprintf(
+				"%s with WRAM address %d and MRAM address %d is not aligned to %d bytes",
+				name,
+				wram_address,
+				mram_address,
+				global.MinAccessGranularity,
+			),
+		)
+	}
+
+	if is_valid && mram_address+size > this.mram_heap_begin_address {
+		this.CheckMramRegion(thread, pc, name, mram_address, size, is_ldma)
+	}
+
+	if is_valid && !is_ldma {
+		this.CopyWramToMram(wram_address, mram_address, size)
+	}
+
+	return is_valid
+}
+
+// CheckMramRegion checks a DMA that touches the MRAM heap against the regions that the host has
+// written.
+func (this *Sanitizer) CheckMramRegion(
+	thread *Thread,
+	pc int64,
+	name string,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) {
+	if is_ldma {
+		begin_address := mram_address - mram_address%global.MinAccessGranularity
+		for i := begin_address; i < mram_address+size; i += global.MinAccessGranularity {
+			if i+global.MinAccessGranularity > this.mram_heap_begin_address && !this.IsMramInitialized(i) {
+				this.Report(
+					OUT_OF_REGION_DMA,
+					thread,
+					pc,
+					fmt.Sprintf(
+						"%s of %d bytes at %s reads %s, which neither the host nor the DPU has written",
+						name,
+						size,
+						this.SymbolizeMram(mram_address),
+						this.SymbolizeMram(i),
+					),
+				)
+				return
+			}
+		}
+
+		return
+	}
+
+	region_begin_address, region_end_address, found := this.MramRegion(mram_address)
+	if found && mram_address+size > region_end_address {
+		this.Report(
+			OUT_OF_REGION_DMA,
+			thread,
+			pc,
+			fmt.Sprintf(
+				"%s of %d bytes at %s runs past the end of the region [%s, %s) that the host has written",
+				name,
+				size,
+				this.SymbolizeMram(mram_address),
+				this.SymbolizeMram(region_begin_address),
+				this.SymbolizeMram(region_end_address),
+			),
+		)
+	}
+}
+
+// CheckStackPointer reports a tasklet whose stack pointer has left its stack. The check is armed
+// once the stack pointer first points into the tasklet's stack.
+func (this *Sanitizer) CheckStackPointer(thread *Thread, pc int64) {
+	thread_id := thread.ThreadId()
+	if thread_id >= len(this.stack_begin_addresses) {
+		return
+	}
+
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(stack_pointer_index)
+
+	stack_pointer := thread.RegFile().ReadGpReg(gp_reg_descriptor, word.UNSIGNED)
+	stack_begin_address := this.stack_begin_addresses[thread_id]
+	stack_end_address := this.stack_end_addresses[thread_id]
+
+	is_in_stack := stack_begin_address <= stack_pointer && stack_pointer <= stack_end_address
+
+	if !this.is_stack_pointer_armed[thread_id] {
+		this.is_stack_pointer_armed[thread_id] = is_in_stack
+	} else if !is_in_stack {
+		this.Report(
+			STACK_OVERFLOW,
+			thread,
+			pc,
+			fmt.Sprintf(
+				"stack pointer r22 = %d is out of the stack [%d, %d)",
+				stack_pointer,
+				stack_begin_address,
+				stack_end_address,
+			),
+		)
+	}
+}
+
+// Report records a violation once per kind and PC.
+func (this *Sanitizer) Report(
+	sanitizer_violation SanitizerViolation,
+	thread *Thread,
+	pc int64,
+	message string,
+) {
+	name := this.StringifySanitizerViolation(sanitizer_violation)
+	this.stat_factory.Increment(name, 1)
+
+	key := fmt.Sprintf("%s:%d", name, pc)
+	if this.reported[key] {
+		return
+	}
+	this.reported[key] = true
+
+	line := fmt.Sprintf(
+		"DPU%d-%d-%d tasklet %d at %d (%s): %s: %s",
+		this.channel_id,
+		this.rank_id,
+		this.dpu_id,
+		thread.ThreadId(),
+		pc,
+		this.Symbolize(pc),
+		name,
+		message,
+	)
+
+	fmt.Printf("sanitizer: %s\n", line)
+	this.lines = append(this.lines, line)
+}
+
+func (this *Sanitizer) StringifySanitizerViolation(sanitizer_violation SanitizerViolation) string {
+	if sanitizer_violation == STACK_OVERFLOW {
+		return "stack_overflow"
+	} else if sanitizer_violation == CROSS_TASKLET_STACK_ACCESS {
+		return "cross_tasklet_stack_access"
+	} else if sanitizer_violation == UNINITIALIZED_READ {
+		return "uninitialized_read"
+	} else if sanitizer_violation == OUT_OF_BOUNDS_WRAM_ACCESS {
+		return "out_of_bounds_wram_access"
+	} else if sanitizer_violation == OUT_OF_BOUNDS_DMA {
+		return "out_of_bounds_dma"
+	} else if sanitizer_violation == MISALIGNED_DMA {
+		return "misaligned_dma"
+	} else if sanitizer_violation == OUT_OF_HEAP_ACCESS {
+		return "out_of_heap_access"
+	} else if sanitizer_violation == OUT_OF_REGION_DMA {
+		return "out_of_region_dma"
+	} else {
+		err := errors.New("sanitizer violation is not valid")
+		panic(err)
+	}
+}
+
+func (this *Sanitizer) Symbolize(address int64) string {
+	if this.symbol_table == nil {
+		return strconv.FormatInt(address, 10)
+	}
+
+	if address >= this.heap_begin_address && this.IsInWram(address, 1) {
+		return fmt.Sprintf("heap+%d", address-this.heap_begin_address)
+	}
+
+	return this.symbol_table.Symbolize(address)
+}
+
+// SymbolizeMram formats an MRAM address, as an offset into the MRAM heap if it is in the heap.
+func (this *Sanitizer) SymbolizeMram(address int64) string {
+	if address >= this.mram_heap_begin_address {
+		return fmt.Sprintf("mram_heap+%d", address-this.mram_heap_begin_address)
+	}
+
+	return strconv.FormatInt(address, 10)
+}
+
+// HeapEndAddress returns the current value of __sys_heap_pointer, i.e., the end of the WRAM heap
+// that mem_alloc has handed out so far.
+func (this *Sanitizer) HeapEndAddress() (int64, bool) {
+	if this.heap_pointer_address == -1 || this.operand_collector == nil {
+		return 0, false
+	}
+
+	return this.operand_collector.Lw(this.heap_pointer_address), true
+}
+
+func (this *Sanitizer) IsInWram(address int64, size int64) bool {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return config_loader.WramOffset() <= address &&
+		address+size <= config_loader.WramOffset()+config_loader.WramSize()
+}
+
+func (this *Sanitizer) WramIndex(address int64) int {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return int(address - config_loader.WramOffset())
+}
+
+func (this *Sanitizer) IsMramInitialized(address int64) bool {
+	index, ok := this.MramGranule(address)
+	if !ok {
+		return false
+	}
+
+	return this.mram_initialized[index/64]&(1<<(index%64)) != 0
+}
+
+func (this *Sanitizer) SetMramInitialized(address int64, is_initialized bool) {
+	index, ok := this.MramGranule(address)
+	if !ok {
+		return
+	}
+
+	if is_initialized {
+		this.mram_initialized[index/64] |= 1 << (index % 64)
+	} else {
+		this.mram_initialized[index/64] &^= 1 << (index % 64)
+	}
+}
+
+func (this *Sanitizer) MramGranule(address int64) (int64, bool) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if address < config_loader.MramOffset() ||
+		address >= config_loader.MramOffset()+config_loader.MramSize() {
+		return 0, false
+	}
+
+	return (address - config_loader.MramOffset()) / global.MinAccessGranularity, true
+}
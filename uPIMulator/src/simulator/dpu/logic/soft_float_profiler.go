@@ -0,0 +1,142 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/symbol"
+)
+
+type SoftFloatRoutine struct {
+	name          string
+	begin_address int64
+	end_address   int64
+}
+
+// SoftFloatProfiler counts the instructions that a single DPU issues in the soft-float routines of
+// the syslib (e.g., __addsf3, __muldf3 and __floatsisf). The DPU has no floating-point unit, so
+// the compiler lowers every float and double operation to a call to one of them. Comparing
+// num_instructions with the num_instructions of the logic gives the share of a FLOAT or DOUBLE run
+// that is spent emulating floating point; the count per routine shows which operations dominate.
+type SoftFloatProfiler struct {
+	channel_id int
+	rank_id    int
+	dpu_id     int
+
+	routines []*SoftFloatRoutine
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *SoftFloatProfiler) Init(channel_id int, rank_id int, dpu_id int) {
+	if channel_id < 0 {
+		err := errors.New("channel ID < 0")
+		panic(err)
+	} else if rank_id < 0 {
+		err := errors.New("rank ID < 0")
+		panic(err)
+	} else if dpu_id < 0 {
+		err := errors.New("DPU ID < 0")
+		panic(err)
+	}
+
+	this.channel_id = channel_id
+	this.rank_id = rank_id
+	this.dpu_id = dpu_id
+
+	this.routines = nil
+
+	name := fmt.Sprintf("SoftFloatProfiler[%d_%d_%d]", channel_id, rank_id, dpu_id)
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init(name)
+}
+
+func (this *SoftFloatProfiler) Fini() {
+}
+
+// ConnectSymbolTable finds the soft-float routines in IRAM. A routine ends where the next function
+// begins, so the static helpers that the compiler inlines are counted with their caller.
+func (this *SoftFloatProfiler) ConnectSymbolTable(symbol_table *symbol.SymbolTable) {
+	if this.routines != nil {
+		err := errors.New("symbol table is already set")
+		panic(err)
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_begin_address := config_loader.IramOffset()
+	iram_end_address := config_loader.IramOffset() + config_loader.IramSize()
+
+	function_addresses := make([]int64, 0)
+	for name, address := range symbol_table.Addresses() {
+		if !strings.Contains(name, ".L") && iram_begin_address <= address &&
+			address < iram_end_address {
+			function_addresses = append(function_addresses, address)
+		}
+	}
+	slices.Sort(function_addresses)
+
+	this.routines = make([]*SoftFloatRoutine, 0)
+	for name, address := range symbol_table.Addresses() {
+		if !this.IsSoftFloatRoutine(name) || address < iram_begin_address ||
+			address >= iram_end_address {
+			continue
+		}
+
+		index, _ := slices.BinarySearch(function_addresses, address+1)
+
+		routine := new(SoftFloatRoutine)
+		routine.name = name
+		routine.begin_address = address
+		if index < len(function_addresses) {
+			routine.end_address = function_addresses[index]
+		} else {
+			routine.end_address = iram_end_address
+		}
+
+		this.routines = append(this.routines, routine)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if this.routines[i].begin_address == this.routines[j].begin_address {
+			return this.routines[i].name < this.routines[j].name
+		}
+		return this.routines[i].begin_address < this.routines[j].begin_address
+	}
+
+	sort.Slice(this.routines, sort_fn)
+}
+
+// IsSoftFloatRoutine returns whether a symbol is one of the compiler-rt routines of sdk/syslib,
+// which are named after the modes of their operands (sf for float, df for double, hf for half).
+func (this *SoftFloatProfiler) IsSoftFloatRoutine(name string) bool {
+	if !strings.HasPrefix(name, "__") {
+		return false
+	}
+
+	mode := name[2:]
+	return strings.Contains(mode, "sf") || strings.Contains(mode, "df") ||
+		strings.Contains(mode, "hf")
+}
+
+func (this *SoftFloatProfiler) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Issue counts an instruction issued at pc if pc is in a soft-float routine.
+func (this *SoftFloatProfiler) Issue(pc int64) {
+	index := sort.Search(len(this.routines), func(i int) bool {
+		return this.routines[i].begin_address > pc
+	}) - 1
+
+	if index < 0 || pc >= this.routines[index].end_address {
+		return
+	}
+
+	this.stat_factory.Increment("num_instructions", 1)
+	this.stat_factory.Increment(this.routines[index].name, 1)
+}
@@ -63,6 +63,23 @@ func (this *Thread) SetThreadState(thread_state ThreadState) {
 	this.thread_state = thread_state
 }
 
+func (this *Thread) StringifyThreadState() string {
+	if this.thread_state == EMBRYO {
+		return "EMBRYO"
+	} else if this.thread_state == RUNNABLE {
+		return "RUNNABLE"
+	} else if this.thread_state == SLEEP {
+		return "SLEEP"
+	} else if this.thread_state == BLOCK {
+		return "BLOCK"
+	} else if this.thread_state == ZOMBIE {
+		return "ZOMBIE"
+	} else {
+		err := errors.New("thread state is not valid")
+		panic(err)
+	}
+}
+
 func (this *Thread) RegFile() *reg.RegFile {
 	return this.reg_file
 }
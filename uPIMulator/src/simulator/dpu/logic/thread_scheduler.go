@@ -16,6 +16,8 @@ type ThreadScheduler struct {
 	threads  []*Thread
 	thread_q *ThreadQ
 
+	debug_unit *DebugUnit
+
 	stat_factory *misc.StatFactory
 }
 
@@ -48,6 +50,8 @@ func (this *ThreadScheduler) Init(
 		this.thread_q.Push(thread)
 	}
 
+	this.debug_unit = nil
+
 	name := fmt.Sprintf("ThreadScheduler[%d_%d_%d]", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
@@ -61,6 +65,15 @@ func (this *ThreadScheduler) Fini() {
 	this.thread_q.Fini()
 }
 
+func (this *ThreadScheduler) ConnectDebugUnit(debug_unit *DebugUnit) {
+	if this.debug_unit != nil {
+		err := errors.New("debug unit is already set")
+		panic(err)
+	}
+
+	this.debug_unit = debug_unit
+}
+
 func (this *ThreadScheduler) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
@@ -86,6 +99,13 @@ func (this *ThreadScheduler) Schedule() *Thread {
 
 		if thread.IssueCycle() >= this.num_revolver_scheduling_cycles {
 			if thread.ThreadState() == RUNNABLE {
+				// NOTE: a thread stopped at a breakpoint is passed over before it takes its
+				// revolver slot or a run cycle, so a breakpoint changes neither timing nor stats
+				if this.debug_unit != nil &&
+					this.debug_unit.ShouldBreak(thread.ThreadId(), thread.RegFile().ReadPcReg()) {
+					continue
+				}
+
 				thread.ResetIssueCycle()
 
 				this.stat_factory.Increment("breakdown_run", 1)
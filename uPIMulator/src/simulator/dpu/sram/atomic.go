@@ -58,6 +58,11 @@ func (this *Atomic) Release(address int64, thread_id int) {
 	this.locks[this.Index(address)].Release(thread_id)
 }
 
+// Holder returns the ID of the thread holding the lock at address, or nil if it is free.
+func (this *Atomic) Holder(address int64) *int {
+	return this.locks[this.Index(address)].ThreadId()
+}
+
 func (this *Atomic) Index(address int64) int {
 	if address < this.address {
 		err := errors.New("address < atomic offset")
@@ -94,6 +94,34 @@ func (this *Iram) Write(address int64, byte_stream *encoding.ByteStream) {
 	file_dumper.WriteLines(lines)
 }
 
+// Peek reads raw bytes of IRAM, which need not be aligned with instructions.
+func (this *Iram) Peek(address int64, size int64) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for i := int64(0); i < size; i++ {
+		if address+i < this.address || address+i >= this.address+this.size {
+			err := errors.New("address is out of IRAM")
+			panic(err)
+		}
+
+		byte_stream.Append(this.byte_stream.Get(int(address + i - this.address)))
+	}
+
+	return byte_stream
+}
+
+func (this *Iram) Poke(address int64, byte_stream *encoding.ByteStream) {
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		if address+i < this.address || address+i >= this.address+this.size {
+			err := errors.New("address is out of IRAM")
+			panic(err)
+		}
+
+		this.byte_stream.Set(int(address+i-this.address), byte_stream.Get(int(i)))
+	}
+}
+
 func (this *Iram) Index(address int64) int {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -19,6 +19,10 @@ func (this *Lock) Fini() {
 	}
 }
 
+func (this *Lock) ThreadId() *int {
+	return this.thread_id
+}
+
 func (this *Lock) CanAcquire() bool {
 	return this.thread_id == nil
 }
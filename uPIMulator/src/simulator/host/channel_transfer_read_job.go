@@ -40,34 +40,34 @@ func (this *ChannelTransferReadJob) Execute() {
 
 	this.channel.PopChannelMessage(this.channel_message)
 
-	//this.CompareByteStreams(this.byte_streams, this.channel_message.ByteStreams())
-
 	this.channel.Unlock()
 }
 
-func (this *ChannelTransferReadJob) CompareByteStreams(
-	byte_streams_1 []*encoding.ByteStream,
-	byte_streams_2 []*encoding.ByteStream,
+// Verify compares the bytes read from every DPU with the bytes the benchmark expects, which are
+// at offset from the symbol name.
+func (this *ChannelTransferReadJob) Verify(
+	verifier *Verifier,
+	execution int,
+	name string,
+	offset int64,
 ) {
-	if len(byte_streams_1) != len(byte_streams_2) {
-		err := errors.New("byte streams 1's length != byte streams 2's length")
+	byte_streams := this.channel_message.ByteStreams()
+
+	if len(this.byte_streams) != len(byte_streams) {
+		err := errors.New("expected byte streams' length != read byte streams' length")
 		panic(err)
 	}
 
-	for i := 0; i < len(byte_streams_1); i++ {
-		byte_stream_1 := byte_streams_1[i]
-		byte_stream_2 := byte_streams_2[i]
-
-		if byte_stream_1.Size() != byte_stream_2.Size() {
-			err := errors.New("byte stream 1's size != byte stream 2's size")
-			panic(err)
-		}
-
-		for j := int64(0); j < byte_stream_1.Size(); j++ {
-			if byte_stream_1.Get(int(j)) != byte_stream_2.Get(int(j)) {
-				err := errors.New("bytes are different")
-				panic(err)
-			}
-		}
+	for i, dpu_id := range this.channel_message.DpuIds() {
+		verifier.Compare(
+			execution,
+			this.channel_message.ChannelId(),
+			this.channel_message.RankId(),
+			dpu_id,
+			name,
+			offset,
+			this.byte_streams[i],
+			byte_streams[i],
+		)
 	}
 }
@@ -21,5 +21,5 @@ func (this *DmaTransferToWramJob) Execute() {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
-	this.dpu.Dma().TransferToWram(config_loader.WramOffset(), this.wram)
+	this.dpu.Dma().LoadWram(config_loader.WramOffset(), this.wram)
 }
@@ -0,0 +1,200 @@
+package host
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// DpuLog captures what a DPU prints. After each launch, or while the DPU runs with dpu_log_live,
+// it decodes the part of __stdout_buffer written since the last read and prints it line by line
+// or appends it to dpu_log/dpu<index>.txt in bin_dirpath.
+//
+// NOTE: reading the log takes no simulated time, peeks at the memories without going through
+// the memory controller and never writes them, so it does not change the simulation.
+type DpuLog struct {
+	dpu_  *dpu.Dpu
+	index int
+
+	buffer_address      int64
+	state_address       int64
+	buffer_size_address int64
+	lock_address        int64
+
+	stdout_decoder *StdoutDecoder
+
+	is_launched  bool
+	is_booted    bool
+	launch_state []byte
+	read_pointer int64
+	line         string
+	path         string
+}
+
+func (this *DpuLog) Init(dpu_ *dpu.Dpu, index int, addresses map[string]int64) {
+	this.dpu_ = dpu_
+	this.index = index
+
+	this.buffer_address = addresses["__stdout_buffer"]
+	this.state_address = addresses["__stdout_buffer_state"]
+	this.buffer_size_address = addresses["__stdout_buffer_size"]
+	this.lock_address = addresses["__stdout_buffer_lock"]
+
+	this.stdout_decoder = new(StdoutDecoder)
+
+	this.is_launched = false
+	this.is_booted = false
+	this.launch_state = nil
+	this.read_pointer = 0
+	this.line = ""
+	this.path = ""
+
+	if global.DpuLog == "save" {
+		dirpath := filepath.Join(global.BinDirpath, "dpu_log")
+		if err := os.MkdirAll(dirpath, 0755); err != nil {
+			panic(err)
+		}
+
+		this.path = filepath.Join(dirpath, fmt.Sprintf("dpu%d.txt", index))
+		if err := os.WriteFile(this.path, []byte{}, 0644); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (this *DpuLog) Dpu() *dpu.Dpu {
+	return this.dpu_
+}
+
+func (this *DpuLog) IsLaunched() bool {
+	return this.is_launched
+}
+
+// Launch starts a new log. The bootstrap zeroes __stdout_buffer_state, but only once the DPU has
+// run its first instructions, until which a live read would see the previous launch's state. That
+// state is remembered instead, and the log is not read until the state has changed.
+func (this *DpuLog) Launch() {
+	// NOTE: the host may relaunch a DPU in the cycle it notices that the DPU has finished
+	if this.is_launched {
+		this.Finish()
+	}
+
+	this.is_launched = true
+	this.is_booted = false
+	this.launch_state = this.Bytes(this.dpu_.Wram().Read(this.state_address, 8))
+	this.read_pointer = 0
+	this.line = ""
+}
+
+// IsPrinting returns whether a tasklet is in the middle of a print, whose bytes may not all have
+// reached MRAM yet.
+func (this *DpuLog) IsPrinting() bool {
+	return this.dpu_.Atomic().Holder(this.lock_address) != nil
+}
+
+// Read decodes the prints written since the last read.
+func (this *DpuLog) Read() {
+	state := this.Bytes(this.dpu_.Wram().Read(this.state_address, 8))
+
+	if !this.is_booted {
+		if slices.Equal(state, this.launch_state) {
+			return
+		}
+
+		this.is_booted = true
+	}
+
+	write_pointer := int64(binary.LittleEndian.Uint32(state[:4]))
+
+	if write_pointer == this.read_pointer {
+		return
+	}
+
+	var data []byte
+	if write_pointer > this.read_pointer {
+		data = this.ReadBuffer(this.read_pointer, write_pointer-this.read_pointer)
+	} else {
+		buffer_size := this.Bytes(this.dpu_.Wram().Read(this.buffer_size_address, 4))
+		size := int64(binary.LittleEndian.Uint32(buffer_size))
+
+		data = this.ReadBuffer(this.read_pointer, size-this.read_pointer)
+		data = append(data, this.ReadBuffer(0, write_pointer)...)
+	}
+
+	text, num_bytes := this.stdout_decoder.Decode(data)
+	this.read_pointer = write_pointer - int64(len(data)-num_bytes)
+	if this.read_pointer < 0 {
+		buffer_size := this.Bytes(this.dpu_.Wram().Read(this.buffer_size_address, 4))
+		this.read_pointer += int64(binary.LittleEndian.Uint32(buffer_size))
+	}
+
+	this.Write(text)
+}
+
+// Finish reads the rest of the log once the DPU has finished.
+func (this *DpuLog) Finish() {
+	// NOTE: a DPU that has finished has run its bootstrap, even if it has printed as many bytes as
+	// the previous launch
+	if this.dpu_.IsZombie() {
+		this.is_booted = true
+	}
+
+	this.Read()
+
+	if this.line != "" {
+		this.Write("\n")
+	}
+
+	this.is_launched = false
+}
+
+func (this *DpuLog) Write(text string) {
+	if text == "" {
+		return
+	}
+
+	if this.path != "" {
+		file, err := os.OpenFile(this.path, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			panic(err)
+		}
+
+		if _, err := file.WriteString(text); err != nil {
+			panic(err)
+		}
+
+		file.Close()
+		return
+	}
+
+	// NOTE: a line is printed once it is complete so that the lines of DPUs do not interleave
+	this.line += text
+	for {
+		index := strings.Index(this.line, "\n")
+		if index == -1 {
+			break
+		}
+
+		fmt.Printf("[dpu %d] %s\n", this.index, this.line[:index])
+		this.line = this.line[index+1:]
+	}
+}
+
+func (this *DpuLog) ReadBuffer(offset int64, size int64) []byte {
+	return this.Bytes(this.dpu_.MemoryController().Peek(this.buffer_address+offset, size))
+}
+
+func (this *DpuLog) Bytes(byte_stream *encoding.ByteStream) []byte {
+	data := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		data = append(data, byte_stream.Get(int(i)))
+	}
+
+	return data
+}
@@ -2,8 +2,10 @@ package host
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"uPIMulator/src/abi/encoding"
@@ -12,6 +14,7 @@ import (
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
 	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/rank"
 )
 
 type Host struct {
@@ -32,12 +35,51 @@ type Host struct {
 	output_dpu_mram_heap_pointer_name []*Chunk
 
 	channels []*channel.Channel
+
+	transfer_timeline *TransferTimeline
+	execution         int
+
+	transfer_rank *rank.Rank
+
+	pending_dpus []*dpu.Dpu
+	boot_cycles  []int64
+	cycles       int64
+	launch_cycle int64
+
+	dpu_logs []*DpuLog
+
+	verifier *Verifier
+
+	stat_factory *misc.StatFactory
 }
 
 func (this *Host) Init() {
 
 	this.channels = make([]*channel.Channel, 0)
 
+	this.transfer_timeline = new(TransferTimeline)
+	this.transfer_timeline.Init(this.channels)
+	this.execution = 0
+
+	this.pending_dpus = make([]*dpu.Dpu, 0)
+	this.boot_cycles = make([]int64, 0)
+	this.transfer_rank = nil
+
+	this.cycles = 0
+	this.launch_cycle = 0
+
+	this.dpu_logs = make([]*DpuLog, 0)
+
+	if global.Verify != "none" {
+		this.verifier = new(Verifier)
+		this.verifier.Init()
+	} else {
+		this.verifier = nil
+	}
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("Host")
+
 	this.InitAddresses()
 	this.InitValues()
 	this.InitAtomic()
@@ -213,6 +255,39 @@ func (this *Host) Fini() {
 
 func (this *Host) ConnectChannels(channels []*channel.Channel) {
 	this.channels = channels
+
+	this.transfer_timeline.Init(channels)
+
+	this.InitDpuLogs()
+}
+
+// InitDpuLogs captures the prints of every DPU if dpu_log is set and the DPU program prints.
+func (this *Host) InitDpuLogs() {
+	if global.DpuLog == "none" {
+		return
+	}
+
+	symbols := []string{"__stdout_buffer", "__stdout_buffer_state", "__stdout_buffer_size", "__stdout_buffer_lock"}
+	for _, symbol := range symbols {
+		if _, found := this.addresses[symbol]; !found {
+			return
+		}
+	}
+
+	for i, dpu_ := range this.Dpus() {
+		dpu_log := new(DpuLog)
+		dpu_log.Init(dpu_, i, this.addresses)
+
+		this.dpu_logs = append(this.dpu_logs, dpu_log)
+	}
+}
+
+func (this *Host) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *Host) Verifier() *Verifier {
+	return this.verifier
 }
 
 func (this *Host) NumExecutions() int {
@@ -255,35 +330,203 @@ func (this *Host) Schedule(execution int) {
 		this.Load()
 	}
 
+	this.execution = execution
+
+	this.ScheduleRank(execution, nil)
+}
+
+func (this *Host) Check(execution int) {
+	this.CheckRank(execution, nil)
+}
+
+// ScheduleRank transfers the inputs of an execution to the DPUs of a rank, or of every rank if
+// rank_ is nil, and returns the cycles the transfer takes.
+func (this *Host) ScheduleRank(execution int, rank_ *rank.Rank) int64 {
+	this.transfer_rank = rank_
+	this.transfer_timeline.Begin()
+
 	this.ChannelTransferInputDpuHost(execution)
 	this.ChannelTransferInputDpuMramHeapPointerName(execution)
+
+	this.transfer_rank = nil
+	return this.RecordTransfer(execution, "cpu_dpu")
 }
 
-func (this *Host) Check(execution int) {
+// CheckRank transfers the outputs of an execution from the DPUs of a rank, or of every rank if
+// rank_ is nil, and returns the cycles the transfer takes.
+func (this *Host) CheckRank(execution int, rank_ *rank.Rank) int64 {
+	this.transfer_rank = rank_
+	this.transfer_timeline.Begin()
+
 	this.ChannelTransferOutputDpuHost(execution)
 	this.ChannelTransferOutputDpuMramHeapPointerName(execution)
+
+	this.transfer_rank = nil
+	return this.RecordTransfer(execution, "dpu_cpu")
+}
+
+func (this *Host) IsTransferred(rank_ *rank.Rank) bool {
+	return this.transfer_rank == nil || this.transfer_rank == rank_
+}
+
+// RecordTransfer accounts the CPU-DPU or DPU-CPU transfer that has just finished, in total and
+// per execution.
+func (this *Host) RecordTransfer(execution int, direction string) int64 {
+	bus_cycles, transpose_cycles := this.transfer_timeline.End()
+
+	this.stat_factory.Increment(direction+"_cycles", bus_cycles+transpose_cycles)
+	this.stat_factory.Increment(direction+"_transpose_cycles", transpose_cycles)
+	this.stat_factory.Increment(
+		fmt.Sprintf("execution%d_%s_cycles", execution, direction),
+		bus_cycles+transpose_cycles,
+	)
+
+	return bus_cycles + transpose_cycles
+}
+
+func (this *Host) Cycles() int64 {
+	return this.cycles
 }
 
 func (this *Host) Launch() {
+	this.launch_cycle = this.cycles
+
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			// NOTE: the launch reaches the ranks over the control interface after launch_latency
+			// cycles, one rank boot_skew cycles after the other
+			rank_index := int64(channel_.ChannelId()*global.NumRanksPerChannel + rank_.RankId())
+
+			this.LaunchRank(rank_, this.cycles+global.LaunchLatency+rank_index*global.BootSkew)
+		}
+	}
+}
+
+// LaunchRank resets the PCs of the DPUs of a rank and boots them at boot_cycle.
+func (this *Host) LaunchRank(rank_ *rank.Rank, boot_cycle int64) {
+	for _, dpu_ := range rank_.Dpus() {
+		this.LaunchDpu(dpu_, boot_cycle)
+		// if global.LoadLocal == 1 {
+		// 	dpu_.Replace()
+		// }
+	}
+}
+
+// LaunchDpus launches a set of DPUs like Launch does with every DPU.
+func (this *Host) LaunchDpus(dpus []*dpu.Dpu) {
+	this.launch_cycle = this.cycles
+
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			rank_index := int64(channel_.ChannelId()*global.NumRanksPerChannel + rank_.RankId())
+
+			for _, dpu_ := range rank_.Dpus() {
+				if slices.Contains(dpus, dpu_) {
+					this.LaunchDpu(dpu_, this.cycles+global.LaunchLatency+rank_index*global.BootSkew)
+				}
+			}
+		}
+	}
+}
+
+func (this *Host) LaunchDpu(dpu_ *dpu.Dpu, boot_cycle int64) {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
-	dpus := this.Dpus()
+	threads := dpu_.Threads()
 
-	for _, dpu_ := range dpus {
-		threads := dpu_.Threads()
+	for _, thread := range threads {
+		bootstrap := config_loader.IramOffset()
+		thread.RegFile().WritePcReg(bootstrap)
+	}
 
-		for _, thread := range threads {
-			bootstrap := config_loader.IramOffset()
-			thread.RegFile().WritePcReg(bootstrap)
+	for _, dpu_log := range this.dpu_logs {
+		if dpu_log.Dpu() == dpu_ {
+			dpu_log.Launch()
 		}
+	}
 
+	if boot_cycle <= this.cycles {
 		dpu_.Boot()
-		// if global.LoadLocal == 1 {
-		// 	dpu_.Replace()
-		// }
+	} else {
+		this.pending_dpus = append(this.pending_dpus, dpu_)
+		this.boot_cycles = append(this.boot_cycles, boot_cycle)
+	}
+}
 
+// IsComplete returns whether the host has observed that the launched DPUs have finished. With
+// DPU_SYNCHRONOUS, the host polls the DPUs every poll_interval cycles.
+func (this *Host) IsComplete() bool {
+	if len(this.pending_dpus) != 0 || !this.IsZombie() {
+		return false
 	}
+
+	return this.IsPolled(this.launch_cycle)
+}
+
+// IsRankComplete returns whether the host has observed that a rank launched at launch_cycle has
+// finished.
+func (this *Host) IsRankComplete(rank_ *rank.Rank, launch_cycle int64) bool {
+	return this.IsDpusComplete(rank_.Dpus(), launch_cycle)
+}
+
+// IsDpusComplete returns whether the host has observed that a set of DPUs launched at
+// launch_cycle has finished.
+func (this *Host) IsDpusComplete(dpus []*dpu.Dpu, launch_cycle int64) bool {
+	for _, dpu_ := range dpus {
+		if !dpu_.IsZombie() || slices.Contains(this.pending_dpus, dpu_) {
+			return false
+		}
+	}
+
+	return this.IsPolled(launch_cycle)
+}
+
+func (this *Host) IsPolled(launch_cycle int64) bool {
+	return global.PollInterval == 0 || (this.cycles-launch_cycle)%global.PollInterval == 0
+}
+
+// ReadDpuLogs reads the log of each DPU that has finished and, with dpu_log_live, of each running
+// DPU that is not in the middle of a print.
+func (this *Host) ReadDpuLogs() {
+	for _, dpu_log := range this.dpu_logs {
+		if !dpu_log.IsLaunched() || slices.Contains(this.pending_dpus, dpu_log.Dpu()) {
+			continue
+		}
+
+		if dpu_log.Dpu().IsZombie() {
+			dpu_log.Finish()
+		} else if global.DpuLogLive && !dpu_log.IsPrinting() {
+			dpu_log.Read()
+		}
+	}
+}
+
+// FinishDpuLogs reads what the DPUs still running have printed, for a simulation that ends
+// before they finish.
+func (this *Host) FinishDpuLogs() {
+	for _, dpu_log := range this.dpu_logs {
+		if dpu_log.IsLaunched() && !slices.Contains(this.pending_dpus, dpu_log.Dpu()) {
+			dpu_log.Finish()
+		}
+	}
+}
+
+func (this *Host) BootPendingDpus() {
+	pending_dpus := make([]*dpu.Dpu, 0)
+	boot_cycles := make([]int64, 0)
+
+	for i, dpu_ := range this.pending_dpus {
+		if this.boot_cycles[i] <= this.cycles {
+			dpu_.Boot()
+		} else {
+			pending_dpus = append(pending_dpus, dpu_)
+			boot_cycles = append(boot_cycles, this.boot_cycles[i])
+		}
+	}
+
+	this.pending_dpus = pending_dpus
+	this.boot_cycles = boot_cycles
 }
 
 func (this *Host) DmaTransferToAtomic() {
@@ -364,30 +607,42 @@ func (this *Host) ChannelTransferInputDpuHost(execution int) {
 
 		address := this.addresses[pointer]
 
+		is_broadcast := this.IsBroadcast(this.FindInputDpuHostChunks(pointer, execution))
+		if is_broadcast {
+			this.stat_factory.Increment("num_broadcasts", 1)
+		}
+
 		for _, channel_ := range this.channels {
 			channel_id := channel_.ChannelId()
 			ranks := channel_.Ranks()
 
 			for _, rank_ := range ranks {
+				if !this.IsTransferred(rank_) {
+					continue
+				}
+
 				rank_id := rank_.RankId()
-				dpus := rank_.Dpus()
 
-				for i := 0; i < 8; i++ {
+				for _, dpus := range this.DpuGroups(rank_.Dpus(), is_broadcast) {
 					dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
 					for _, dpu_ := range dpus {
 						dpu_id := dpu_.DpuId()
 						unique_dpu_id := channel_id*global.NumRanksPerChannel*global.NumDpusPerRank + rank_id*global.NumDpusPerRank + dpu_id
-						if dpu_id%8 == i {
-							chunk := this.FindInputDpuHostChunk(pointer, execution, unique_dpu_id)
 
-							dpu_ids = append(dpu_ids, dpu_id)
-							byte_streams = append(byte_streams, chunk.ByteStream())
-						}
+						chunk := this.FindInputDpuHostChunk(pointer, execution, unique_dpu_id)
+
+						dpu_ids = append(dpu_ids, dpu_id)
+						byte_streams = append(byte_streams, chunk.ByteStream())
 					}
 
 					if len(byte_streams) != 0 && byte_streams[0].Size() != 0 {
+						this.stat_factory.Increment(
+							"cpu_dpu_bytes",
+							byte_streams[0].Size()*int64(len(dpu_ids)),
+						)
+
 						channel_message := new(channel.ChannelMessage)
 						channel_message.InitWrite(
 							channel_.ChannelId(),
@@ -398,6 +653,10 @@ func (this *Host) ChannelTransferInputDpuHost(execution int) {
 							byte_streams,
 						)
 
+						if !is_broadcast {
+							this.transfer_timeline.Transpose(byte_streams[0].Size() * int64(len(dpu_ids)))
+						}
+
 						channel_transfer_write_job := new(ChannelTransferWriteJob)
 						channel_transfer_write_job.Init(channel_message, channel_)
 
@@ -406,6 +665,10 @@ func (this *Host) ChannelTransferInputDpuHost(execution int) {
 				}
 			}
 		}
+
+		if is_broadcast {
+			this.transfer_timeline.Transpose(this.FindInputDpuHostChunks(pointer, execution)[0].ByteStream().Size())
+		}
 	}
 
 	thread_pool.Start()
@@ -417,6 +680,9 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 
 	pointers := this.FindOutputDpuHostPointers(execution)
 
+	channel_transfer_read_jobs := make([]*ChannelTransferReadJob, 0)
+	job_pointers := make([]string, 0)
+
 	for pointer, _ := range pointers {
 		if _, found := this.addresses[pointer]; !found {
 			err := errors.New("pointer is not found")
@@ -430,10 +696,13 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 			ranks := channel_.Ranks()
 
 			for _, rank_ := range ranks {
+				if !this.IsTransferred(rank_) {
+					continue
+				}
+
 				rank_id := rank_.RankId()
-				dpus := rank_.Dpus()
 
-				for i := 0; i < 8; i++ {
+				for _, dpus := range this.DpuGroups(rank_.Dpus(), false) {
 					dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
@@ -441,28 +710,36 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 						dpu_id := dpu_.DpuId()
 						unique_dpu_id := channel_id*global.NumRanksPerChannel*global.NumDpusPerRank + rank_id*global.NumDpusPerRank + dpu_id
 
-						if dpu_id%8 == i {
-							chunk := this.FindOutputDpuHostChunk(pointer, execution, unique_dpu_id)
+						chunk := this.FindOutputDpuHostChunk(pointer, execution, unique_dpu_id)
 
-							dpu_ids = append(dpu_ids, dpu_id)
-							byte_streams = append(byte_streams, chunk.ByteStream())
-						}
+						dpu_ids = append(dpu_ids, dpu_id)
+						byte_streams = append(byte_streams, chunk.ByteStream())
 					}
 
-					if len(byte_streams) != 0 && byte_streams[0].Size() != 0 {
+					// NOTE: a transfer reads the same size from every DPU of its group, so it reads the
+					// longest expected output for the output of every DPU to be read in full
+					size := this.MaxSize(byte_streams)
+
+					if size != 0 {
+						this.stat_factory.Increment("dpu_cpu_bytes", size*int64(len(dpu_ids)))
+
 						channel_message := new(channel.ChannelMessage)
 						channel_message.InitRead(
 							channel_.ChannelId(),
 							rank_.RankId(),
 							dpu_ids,
 							address,
-							byte_streams[0].Size(),
+							size,
 						)
 
+						this.transfer_timeline.Transpose(size * int64(len(dpu_ids)))
+
 						channel_transfer_read_job := new(ChannelTransferReadJob)
 						channel_transfer_read_job.Init(channel_message, byte_streams, channel_)
 
 						thread_pool.Enque(channel_transfer_read_job)
+						channel_transfer_read_jobs = append(channel_transfer_read_jobs, channel_transfer_read_job)
+						job_pointers = append(job_pointers, pointer)
 					}
 				}
 			}
@@ -470,6 +747,12 @@ func (this *Host) ChannelTransferOutputDpuHost(execution int) {
 	}
 
 	thread_pool.Start()
+
+	if this.verifier != nil {
+		for i, channel_transfer_read_job := range channel_transfer_read_jobs {
+			channel_transfer_read_job.Verify(this.verifier, execution, job_pointers[i], 0)
+		}
+	}
 }
 
 func (this *Host) ChannelTransferInputDpuMramHeapPointerName(execution int) {
@@ -488,15 +771,25 @@ func (this *Host) ChannelTransferInputDpuMramHeapPointerName(execution int) {
 	for offset, _ := range offsets {
 		address := sys_used_mram_end + offset
 
+		chunks := this.FindInputDpuMramHeapPointerNameChunks(offset, execution)
+
+		is_broadcast := this.IsBroadcast(chunks)
+		if is_broadcast {
+			this.stat_factory.Increment("num_broadcasts", 1)
+		}
+
 		for _, channel_ := range this.channels {
 			channel_id := channel_.ChannelId()
 			ranks := channel_.Ranks()
 
 			for _, rank_ := range ranks {
+				if !this.IsTransferred(rank_) {
+					continue
+				}
+
 				rank_id := rank_.RankId()
-				dpus := rank_.Dpus()
 
-				for i := 0; i < 8; i++ {
+				for _, dpus := range this.DpuGroups(rank_.Dpus(), is_broadcast) {
 					dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
@@ -504,19 +797,22 @@ func (this *Host) ChannelTransferInputDpuMramHeapPointerName(execution int) {
 						dpu_id := dpu_.DpuId()
 						unique_dpu_id := channel_id*global.NumRanksPerChannel*global.NumDpusPerRank + rank_id*global.NumDpusPerRank + dpu_id
 
-						if dpu_id%8 == i {
-							chunk := this.FindInputDpuMramHeapPointerNameChunk(
-								offset,
-								execution,
-								unique_dpu_id,
-							)
+						chunk := this.FindInputDpuMramHeapPointerNameChunk(
+							offset,
+							execution,
+							unique_dpu_id,
+						)
 
-							dpu_ids = append(dpu_ids, dpu_id)
-							byte_streams = append(byte_streams, chunk.ByteStream())
-						}
+						dpu_ids = append(dpu_ids, dpu_id)
+						byte_streams = append(byte_streams, chunk.ByteStream())
 					}
 
 					if len(byte_streams) != 0 && byte_streams[0].Size() != 0 {
+						this.stat_factory.Increment(
+							"cpu_dpu_bytes",
+							byte_streams[0].Size()*int64(len(dpu_ids)),
+						)
+
 						channel_message := new(channel.ChannelMessage)
 						channel_message.InitWrite(
 							channel_.ChannelId(),
@@ -527,6 +823,10 @@ func (this *Host) ChannelTransferInputDpuMramHeapPointerName(execution int) {
 							byte_streams,
 						)
 
+						if !is_broadcast {
+							this.transfer_timeline.Transpose(byte_streams[0].Size() * int64(len(dpu_ids)))
+						}
+
 						channel_transfer_write_job := new(ChannelTransferWriteJob)
 						channel_transfer_write_job.Init(channel_message, channel_)
 
@@ -535,6 +835,10 @@ func (this *Host) ChannelTransferInputDpuMramHeapPointerName(execution int) {
 				}
 			}
 		}
+
+		if is_broadcast {
+			this.transfer_timeline.Transpose(chunks[0].ByteStream().Size())
+		}
 	}
 
 	thread_pool.Start()
@@ -556,15 +860,20 @@ func (this *Host) ChannelTransferOutputDpuMramHeapPointerName(execution int) {
 	for offset, _ := range offsets {
 		address := sys_used_mram_end + offset
 
+		channel_transfer_read_jobs := make([]*ChannelTransferReadJob, 0)
+
 		for _, channel_ := range this.channels {
 			channel_id := channel_.ChannelId()
 			ranks := channel_.Ranks()
 
 			for _, rank_ := range ranks {
+				if !this.IsTransferred(rank_) {
+					continue
+				}
+
 				rank_id := rank_.RankId()
-				dpus := rank_.Dpus()
 
-				for i := 0; i < 8; i++ {
+				for _, dpus := range this.DpuGroups(rank_.Dpus(), false) {
 					dpu_ids := make([]int, 0)
 					byte_streams := make([]*encoding.ByteStream, 0)
 
@@ -572,39 +881,285 @@ func (this *Host) ChannelTransferOutputDpuMramHeapPointerName(execution int) {
 						dpu_id := dpu_.DpuId()
 						unique_dpu_id := channel_id*global.NumRanksPerChannel*global.NumDpusPerRank + rank_id*global.NumDpusPerRank + dpu_id
 
-						if dpu_id%8 == i {
-							chunk := this.FindOutputDpuMramHeapPointerNameChunk(
-								offset,
-								execution,
-								unique_dpu_id,
-							)
+						chunk := this.FindOutputDpuMramHeapPointerNameChunk(
+							offset,
+							execution,
+							unique_dpu_id,
+						)
 
-							dpu_ids = append(dpu_ids, dpu_id)
-							byte_streams = append(byte_streams, chunk.ByteStream())
-						}
+						dpu_ids = append(dpu_ids, dpu_id)
+						byte_streams = append(byte_streams, chunk.ByteStream())
 					}
 
-					if len(byte_streams) != 0 && byte_streams[0].Size() != 0 {
+					// NOTE: a transfer reads the same size from every DPU of its group, so it reads the
+					// longest expected output for the output of every DPU to be read in full
+					size := this.MaxSize(byte_streams)
+
+					if size != 0 {
+						this.stat_factory.Increment("dpu_cpu_bytes", size*int64(len(dpu_ids)))
+
 						channel_message := new(channel.ChannelMessage)
 						channel_message.InitRead(
 							channel_.ChannelId(),
 							rank_.RankId(),
 							dpu_ids,
 							address,
-							byte_streams[0].Size(),
+							size,
 						)
 
+						this.transfer_timeline.Transpose(size * int64(len(dpu_ids)))
+
 						channel_transfer_read_job := new(ChannelTransferReadJob)
 						channel_transfer_read_job.Init(channel_message, byte_streams, channel_)
 
 						thread_pool.Enque(channel_transfer_read_job)
+						channel_transfer_read_jobs = append(channel_transfer_read_jobs, channel_transfer_read_job)
 					}
 				}
 			}
 		}
 
 		thread_pool.Start()
+
+		if this.verifier != nil {
+			for _, channel_transfer_read_job := range channel_transfer_read_jobs {
+				channel_transfer_read_job.Verify(
+					this.verifier,
+					execution,
+					"DPU_MRAM_HEAP_POINTER_NAME",
+					offset,
+				)
+			}
+		}
+	}
+}
+
+func (this *Host) MaxSize(byte_streams []*encoding.ByteStream) int64 {
+	size := int64(0)
+	for _, byte_stream := range byte_streams {
+		size = max(size, byte_stream.Size())
+	}
+	return size
+}
+
+// HasSymbol returns whether SymbolAddress can resolve a symbol.
+func (this *Host) HasSymbol(symbol string) bool {
+	if symbol == "DPU_MRAM_HEAP_POINTER_NAME" {
+		_, found := this.values["__sys_used_mram_end"]
+		return found
+	}
+
+	_, found := this.addresses[symbol]
+	return found
+}
+
+// SymbolAddress returns the address of a host-visible symbol. DPU_MRAM_HEAP_POINTER_NAME stands
+// for the start of the MRAM heap.
+func (this *Host) SymbolAddress(symbol string) int64 {
+	if symbol == "DPU_MRAM_HEAP_POINTER_NAME" {
+		if _, found := this.values["__sys_used_mram_end"]; !found {
+			err := errors.New("__sys_used_mram_end is not found")
+			panic(err)
+		}
+
+		return this.values["__sys_used_mram_end"]
+	}
+
+	if _, found := this.addresses[symbol]; !found {
+		err_msg := fmt.Sprintf("symbol %s is not found", symbol)
+		err := errors.New(err_msg)
+		panic(err)
 	}
+
+	return this.addresses[symbol]
+}
+
+// CopyTo writes a buffer to each DPU of byte_streams at address and returns the cycles the
+// transfer takes. The buffers must all have the same size, as with dpu_push_xfer. A broadcast
+// (dpu_broadcast_to) sends the same buffer to every DPU and transposes it only once.
+func (this *Host) CopyTo(
+	execution int,
+	address int64,
+	byte_streams map[*dpu.Dpu]*encoding.ByteStream,
+	is_broadcast bool,
+) int64 {
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init(global.NumSimulationtThreads)
+
+	this.transfer_timeline.Begin()
+
+	if is_broadcast {
+		this.stat_factory.Increment("num_broadcasts", 1)
+	}
+
+	size := int64(-1)
+	for _, byte_stream := range byte_streams {
+		if size != -1 && byte_stream.Size() != size {
+			err := errors.New("buffers of a transfer have different sizes")
+			panic(err)
+		}
+
+		size = byte_stream.Size()
+	}
+
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			for _, dpus := range this.DpuGroups(rank_.Dpus(), is_broadcast) {
+				dpu_ids := make([]int, 0)
+				dpu_byte_streams := make([]*encoding.ByteStream, 0)
+
+				for _, dpu_ := range dpus {
+					if byte_stream, found := byte_streams[dpu_]; found {
+						dpu_ids = append(dpu_ids, dpu_.DpuId())
+						dpu_byte_streams = append(dpu_byte_streams, byte_stream)
+					}
+				}
+
+				if len(dpu_ids) != 0 && size != 0 {
+					channel_message := new(channel.ChannelMessage)
+					channel_message.InitWrite(
+						channel_.ChannelId(),
+						rank_.RankId(),
+						dpu_ids,
+						address,
+						size,
+						dpu_byte_streams,
+					)
+
+					if !is_broadcast {
+						this.transfer_timeline.Transpose(size * int64(len(dpu_ids)))
+					}
+
+					channel_transfer_write_job := new(ChannelTransferWriteJob)
+					channel_transfer_write_job.Init(channel_message, channel_)
+
+					thread_pool.Enque(channel_transfer_write_job)
+				}
+			}
+		}
+	}
+
+	thread_pool.Start()
+
+	if is_broadcast && size > 0 {
+		this.transfer_timeline.Transpose(size)
+	}
+
+	return this.RecordTransfer(execution, "cpu_dpu")
+}
+
+// CopyFrom reads size bytes at address from each DPU of dpus and returns the buffers and the
+// cycles the transfer takes.
+func (this *Host) CopyFrom(
+	execution int,
+	address int64,
+	size int64,
+	dpus []*dpu.Dpu,
+) (map[*dpu.Dpu]*encoding.ByteStream, int64) {
+	thread_pool := new(core.ThreadPool)
+	thread_pool.Init(global.NumSimulationtThreads)
+
+	this.transfer_timeline.Begin()
+
+	channel_messages := make([]*channel.ChannelMessage, 0)
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			for _, dpu_group := range this.DpuGroups(rank_.Dpus(), false) {
+				dpu_ids := make([]int, 0)
+				for _, dpu_ := range dpu_group {
+					if slices.Contains(dpus, dpu_) {
+						dpu_ids = append(dpu_ids, dpu_.DpuId())
+					}
+				}
+
+				if len(dpu_ids) != 0 && size != 0 {
+					channel_message := new(channel.ChannelMessage)
+					channel_message.InitRead(channel_.ChannelId(), rank_.RankId(), dpu_ids, address, size)
+
+					this.transfer_timeline.Transpose(size * int64(len(dpu_ids)))
+
+					channel_transfer_read_job := new(ChannelTransferReadJob)
+					channel_transfer_read_job.Init(channel_message, nil, channel_)
+
+					thread_pool.Enque(channel_transfer_read_job)
+					channel_messages = append(channel_messages, channel_message)
+				}
+			}
+		}
+	}
+
+	thread_pool.Start()
+
+	byte_streams := make(map[*dpu.Dpu]*encoding.ByteStream, 0)
+	for _, channel_message := range channel_messages {
+		rank_ := this.channels[channel_message.ChannelId()].Ranks()[channel_message.RankId()]
+
+		for i, dpu_id := range channel_message.DpuIds() {
+			byte_streams[rank_.Dpus()[dpu_id]] = channel_message.ByteStreams()[i]
+		}
+	}
+
+	for _, dpu_ := range dpus {
+		if _, found := byte_streams[dpu_]; !found {
+			byte_stream := new(encoding.ByteStream)
+			byte_stream.Init()
+
+			byte_streams[dpu_] = byte_stream
+		}
+	}
+
+	return byte_streams, this.RecordTransfer(execution, "dpu_cpu")
+}
+
+// DpuGroups splits the DPUs of a rank into the groups that one channel message transfers. A
+// rank-parallel transfer (dpu_push_xfer, dpu_broadcast_to) moves the DPUs with the same
+// dpu_id % 8 together, whereas a serial transfer (dpu_copy_to/dpu_copy_from) moves one DPU at a
+// time.
+func (this *Host) DpuGroups(dpus []*dpu.Dpu, is_broadcast bool) [][]*dpu.Dpu {
+	dpu_groups := make([][]*dpu.Dpu, 0)
+
+	if global.HostTransferMode == "serial" && !is_broadcast {
+		for _, dpu_ := range dpus {
+			dpu_groups = append(dpu_groups, []*dpu.Dpu{dpu_})
+		}
+	} else {
+		for i := 0; i < 8; i++ {
+			dpu_group := make([]*dpu.Dpu, 0)
+
+			for _, dpu_ := range dpus {
+				if dpu_.DpuId()%8 == i {
+					dpu_group = append(dpu_group, dpu_)
+				}
+			}
+
+			dpu_groups = append(dpu_groups, dpu_group)
+		}
+	}
+
+	return dpu_groups
+}
+
+// IsBroadcast returns whether every DPU receives the same bytes, which the host transfers with
+// dpu_broadcast_to.
+func (this *Host) IsBroadcast(chunks []*Chunk) bool {
+	if len(chunks) < 2 || len(chunks) != len(this.Dpus()) {
+		return false
+	}
+
+	byte_stream := chunks[0].ByteStream()
+	for _, chunk := range chunks[1:] {
+		if chunk.ByteStream().Size() != byte_stream.Size() {
+			return false
+		}
+
+		for i := int64(0); i < byte_stream.Size(); i++ {
+			if chunk.ByteStream().Get(int(i)) != byte_stream.Get(int(i)) {
+				return false
+			}
+		}
+	}
+
+	return true
 }
 
 func (this *Host) FindInputDpuHostPointers(execution int) map[string]bool {
@@ -655,6 +1210,18 @@ func (this *Host) FindOutputDpuMramHeapPointerNameOffsets(execution int) map[int
 	return offsets
 }
 
+func (this *Host) FindInputDpuHostChunks(pointer string, execution int) []*Chunk {
+	chunks := make([]*Chunk, 0)
+
+	for _, chunk := range this.input_dpu_host {
+		if chunk.Name() == pointer && chunk.Execution() == execution {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	return chunks
+}
+
 func (this *Host) FindInputDpuHostChunk(pointer string, execution int, dpu_id int) *Chunk {
 	for _, chunk := range this.input_dpu_host {
 		if chunk.Name() == pointer && chunk.Execution() == execution && chunk.DpuId() == dpu_id {
@@ -677,6 +1244,18 @@ func (this *Host) FindOutputDpuHostChunk(pointer string, execution int, dpu_id i
 	panic(err)
 }
 
+func (this *Host) FindInputDpuMramHeapPointerNameChunks(offset int64, execution int) []*Chunk {
+	chunks := make([]*Chunk, 0)
+
+	for _, chunk := range this.input_dpu_mram_heap_pointer_name {
+		if chunk.Offset() == offset && chunk.Execution() == execution {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	return chunks
+}
+
 func (this *Host) FindInputDpuMramHeapPointerNameChunk(
 	offset int64,
 	execution int,
@@ -715,6 +1294,28 @@ func (this *Host) Cycle() {
 
 	sys_end := this.addresses["__sys_end"]
 
+	this.BootPendingDpus()
+	this.ReadDpuLogs()
+
+	// NOTE: a cycle is a launch cycle until the last rank has booted and a poll cycle once every
+	// DPU has finished but the host has not noticed yet; asynchronous launches, host scripts and
+	// the host API server do their own accounting
+	if global.LaunchMode == "sync" && global.HostScript == "" && global.HostSocket == "" {
+		var phase string
+		if len(this.pending_dpus) != 0 {
+			phase = "launch"
+		} else if this.IsZombie() {
+			phase = "poll"
+		} else {
+			phase = "kernel"
+		}
+
+		this.stat_factory.Increment(phase+"_cycles", 1)
+		this.stat_factory.Increment(fmt.Sprintf("execution%d_%s_cycles", this.execution, phase), 1)
+	}
+
+	this.cycles++
+
 	thread_pool := new(core.ThreadPool)
 	thread_pool.Init(global.NumSimulationtThreads)
 
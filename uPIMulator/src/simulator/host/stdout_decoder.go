@@ -0,0 +1,150 @@
+package host
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// StdoutDecoder decodes the stdout buffer that printf, puts and putchar of sdk/stdlib/stdio.c
+// fill, the way dpu_log_read does. Each print is a record starting at a multiple of 8 bytes: a
+// format string in which every run of literal characters has been replaced by %s, its NUL, then
+// the arguments in order. A string (including a literal run) is NUL-terminated, a character takes
+// 1 byte, a floating-point number 8 bytes and an integer 4 bytes, or 8 bytes with an l modifier.
+// The record is padded with zeros up to the next multiple of 8, and a record whose length is
+// already a multiple of 8 is followed by another 8 zeros.
+type StdoutDecoder struct {
+}
+
+// Decode decodes the complete records at the start of data and returns their text and the
+// number of bytes they take. A record cut short by the end of data is left for a later call.
+func (this *StdoutDecoder) Decode(data []byte) (string, int) {
+	var builder strings.Builder
+
+	pos := 0
+	for {
+		text, size, is_complete := this.DecodeRecord(data[pos:])
+		if !is_complete {
+			return builder.String(), pos
+		}
+
+		builder.WriteString(text)
+		pos += size
+	}
+}
+
+func (this *StdoutDecoder) DecodeRecord(data []byte) (string, int, bool) {
+	format, end, is_complete := this.ReadString(data, 0)
+	if !is_complete {
+		return "", 0, false
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			builder.WriteByte(format[i])
+			continue
+		}
+
+		// NOTE: printf keeps flags, width, precision and l modifiers and ends a specifier at the
+		// first letter other than l
+		spec := "%"
+		is_64_bits := false
+		conversion := byte(0)
+		for i++; i < len(format); i++ {
+			if format[i] == 'l' {
+				is_64_bits = true
+			} else if ('A' <= format[i] && format[i] <= 'Z') || ('a' <= format[i] && format[i] <= 'z') {
+				conversion = format[i]
+				break
+			} else {
+				spec += string(format[i])
+			}
+		}
+
+		switch conversion {
+		case 's':
+			var arg string
+			arg, end, is_complete = this.ReadString(data, end)
+			if !is_complete {
+				return "", 0, false
+			}
+
+			builder.WriteString(fmt.Sprintf(spec+"s", arg))
+		case 'c':
+			if end+1 > len(data) {
+				return "", 0, false
+			}
+
+			builder.WriteString(fmt.Sprintf(spec+"c", rune(data[end])))
+			end++
+		case 'f', 'e', 'E', 'g', 'G':
+			if end+8 > len(data) {
+				return "", 0, false
+			}
+
+			arg := math.Float64frombits(binary.LittleEndian.Uint64(data[end : end+8]))
+			builder.WriteString(fmt.Sprintf(spec+string(conversion), arg))
+			end += 8
+		default:
+			size := 4
+			if is_64_bits {
+				size = 8
+			}
+
+			if end+size > len(data) {
+				return "", 0, false
+			}
+
+			var arg uint64
+			if is_64_bits {
+				arg = binary.LittleEndian.Uint64(data[end : end+8])
+			} else {
+				arg = uint64(binary.LittleEndian.Uint32(data[end : end+4]))
+			}
+			end += size
+
+			builder.WriteString(this.FormatInt(spec, conversion, arg, is_64_bits))
+		}
+	}
+
+	// NOTE: the last flush of a print always writes a block of 8 bytes, even if it is all padding
+	size := (end/8 + 1) * 8
+	if size > len(data) {
+		return "", 0, false
+	}
+
+	return builder.String(), size, true
+}
+
+func (this *StdoutDecoder) FormatInt(spec string, conversion byte, arg uint64, is_64_bits bool) string {
+	switch conversion {
+	case 'd':
+		if is_64_bits {
+			return fmt.Sprintf(spec+"d", int64(arg))
+		}
+
+		return fmt.Sprintf(spec+"d", int32(arg))
+	case 'u':
+		return fmt.Sprintf(spec+"d", arg)
+	case 'x', 'X', 'o':
+		return fmt.Sprintf(spec+string(conversion), arg)
+	case 'p':
+		return fmt.Sprintf("0x%x", arg)
+	default:
+		return fmt.Sprintf(spec+"v", arg)
+	}
+}
+
+// ReadString reads a NUL-terminated string at pos and returns it and the position after its NUL.
+func (this *StdoutDecoder) ReadString(data []byte, pos int) (string, int, bool) {
+	for i := pos; i < len(data); i++ {
+		if data[i] == 0 {
+			return string(data[pos:i]), i + 1, true
+		}
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,93 @@
+package host_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"uPIMulator/src/simulator/host"
+)
+
+// Record lays out a print the way sdk/stdlib/stdio.c does: its parts, then zeros up to the next
+// multiple of 8, or 8 more zeros if the parts already end at one.
+func Record(parts ...[]byte) []byte {
+	data := make([]byte, 0)
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+
+	size := (len(data)/8 + 1) * 8
+	for len(data) < size {
+		data = append(data, 0)
+	}
+
+	return data
+}
+
+func String(str string) []byte {
+	return append([]byte(str), 0)
+}
+
+func Int32(value int32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, uint32(value))
+}
+
+func Int64(value int64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, uint64(value))
+}
+
+func Concat(records ...[]byte) []byte {
+	data := make([]byte, 0)
+	for _, record := range records {
+		data = append(data, record...)
+	}
+
+	return data
+}
+
+func TestStdoutDecoderDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want_text string
+		want_size int
+	}{
+		{"empty", nil, "", 0},
+		{"literal run", Record(String("%s"), String("hello\n")), "hello\n", 16},
+		// NOTE: "%s\0" and "abcd\0" end at 8 bytes, so the record takes another 8 zeros
+		{"record ending at 8 bytes", Record(String("%s"), String("abcd")), "abcd", 16},
+		{
+			"literal runs around an integer",
+			Record(String("%s%d%s"), String("x = "), Int32(42), String("\n")),
+			"x = 42\n",
+			24,
+		},
+		{"negative integer", Record(String("%d"), Int32(-7)), "-7", 8},
+		{"64-bit integer", Record(String("%ld"), Int64(1<<40)), "1099511627776", 16},
+		{"character", Record(String("%c%c"), []byte{'o', 'k'}), "ok", 8},
+		{"width and flags", Record(String("%08x"), Int32(0xbeef)), "0000beef", 16},
+		{
+			"two records",
+			Concat(Record(String("%s"), String("a")), Record(String("%s"), String("b"))),
+			"ab",
+			16,
+		},
+		{
+			"second record cut short",
+			Concat(Record(String("%s"), String("a")), String("%s"), []byte("b")),
+			"a",
+			8,
+		},
+		{"padding cut short", Record(String("%s"), String("abcd"))[:8], "", 0},
+		{"argument cut short", Concat(String("%ld"), Int32(1)), "", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout_decoder := new(host.StdoutDecoder)
+
+			text, size := stdout_decoder.Decode(test.data)
+			if text != test.want_text || size != test.want_size {
+				t.Errorf("Decode() = %q, %d, want %q, %d", text, size, test.want_text, test.want_size)
+			}
+		})
+	}
+}
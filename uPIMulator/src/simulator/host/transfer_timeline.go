@@ -0,0 +1,59 @@
+package host
+
+import (
+	"uPIMulator/src/global"
+	"uPIMulator/src/simulator/channel"
+)
+
+// TransferTimeline measures how long the host takes to transfer data between the CPU and the
+// DPUs. The channels model the bus time; the host additionally transposes every buffer it sends
+// to or receives from a DPU (once per broadcast buffer) at transpose_bandwidth bytes/cycle. In the
+// parallel mode the channels transfer concurrently, so the bus time of a transfer is that of the
+// busiest channel; in the serial mode the host copies to one DPU at a time, so the channels are
+// used one after another.
+type TransferTimeline struct {
+	channels []*channel.Channel
+
+	channel_cycles  []int64
+	transpose_bytes int64
+}
+
+func (this *TransferTimeline) Init(channels []*channel.Channel) {
+	this.channels = channels
+
+	this.channel_cycles = make([]int64, len(channels))
+	this.transpose_bytes = 0
+}
+
+func (this *TransferTimeline) Begin() {
+	for i, channel_ := range this.channels {
+		this.channel_cycles[i] = channel_.Cycles()
+	}
+
+	this.transpose_bytes = 0
+}
+
+func (this *TransferTimeline) Transpose(size int64) {
+	this.transpose_bytes += size
+}
+
+// End returns the bus cycles and the transposition cycles of the transfer since Begin.
+func (this *TransferTimeline) End() (int64, int64) {
+	bus_cycles := int64(0)
+	for i, channel_ := range this.channels {
+		channel_cycles := channel_.Cycles() - this.channel_cycles[i]
+
+		if global.HostTransferMode == "serial" {
+			bus_cycles += channel_cycles
+		} else if channel_cycles > bus_cycles {
+			bus_cycles = channel_cycles
+		}
+	}
+
+	transpose_cycles := int64(0)
+	if global.TransposeBandwidth > 0 {
+		transpose_cycles = this.transpose_bytes / global.TransposeBandwidth
+	}
+
+	return bus_cycles, transpose_cycles
+}
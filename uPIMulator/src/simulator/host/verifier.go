@@ -0,0 +1,264 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+)
+
+// OutputLayout is the size of the elements of an output and the bytes [begin, end) of every
+// element that are not compared.
+type OutputLayout struct {
+	element_size int64
+	begin        int64
+	end          int64
+}
+
+func (this *OutputLayout) Init(element_size int64, begin int64, end int64) {
+	if element_size <= 0 {
+		err := errors.New("element size <= 0")
+		panic(err)
+	} else if begin < 0 || begin > end || end > element_size {
+		err := errors.New("skipped bytes are not in the element")
+		panic(err)
+	}
+
+	this.element_size = element_size
+	this.begin = begin
+	this.end = end
+}
+
+func (this *OutputLayout) ElementSize() int64 {
+	return this.element_size
+}
+
+func (this *OutputLayout) IsSkipped(pos int64) bool {
+	byte_offset := pos % this.element_size
+	return this.begin <= byte_offset && byte_offset < this.end
+}
+
+// Verifier compares the outputs that the host reads from every DPU after an execution with the
+// outputs that the benchmark expects. Every output of every DPU passes, fails or is incomplete. A
+// failure is reported with its first mismatching byte, as a symbol and offset and as the element
+// of the output that holds it, with the expected and actual values of that element. An output
+// that is read shorter than expected is incomplete, since its tail is not checked. In the strict
+// mode, the first failure or incomplete output stops the simulation.
+type Verifier struct {
+	layouts map[string]*OutputLayout
+
+	lines []string
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *Verifier) Init() {
+	this.layouts = make(map[string]*OutputLayout, 0)
+	this.lines = make([]string, 0)
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("Verifier")
+
+	// NOTE: the summary lists the counts of a run without any output or failure as well
+	this.stat_factory.Increment("num_outputs", 0)
+	this.stat_factory.Increment("num_passes", 0)
+	this.stat_factory.Increment("num_failures", 0)
+	this.stat_factory.Increment("num_incomplete", 0)
+
+	this.InitLayouts()
+}
+
+// InitLayouts reads the layouts of the outputs that the assembler writes as
+// "name: element_size begin end".
+func (this *Verifier) InitLayouts() {
+	path := filepath.Join(global.BinDirpath, "output_layouts.txt")
+
+	// NOTE: binaries assembled before the layouts were written are compared byte by byte
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	for _, line := range file_scanner.ReadLines() {
+		name, values, found := strings.Cut(line, ": ")
+		if !found {
+			err_msg := fmt.Sprintf("output layout (%s) is not valid", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		words := strings.Fields(values)
+		if len(words) != 3 {
+			err_msg := fmt.Sprintf("output layout (%s) is not valid", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		fields := make([]int64, 0)
+		for _, word := range words {
+			field, err := strconv.ParseInt(word, 10, 64)
+			if err != nil {
+				panic(err)
+			}
+
+			fields = append(fields, field)
+		}
+
+		layout := new(OutputLayout)
+		layout.Init(fields[0], fields[1], fields[2])
+		this.layouts[name] = layout
+	}
+}
+
+func (this *Verifier) Layout(name string) *OutputLayout {
+	if layout, found := this.layouts[name]; found {
+		return layout
+	}
+
+	layout := new(OutputLayout)
+	layout.Init(1, 0, 0)
+	return layout
+}
+
+func (this *Verifier) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Lines returns a line per output, sorted so that the report does not depend on the order in which
+// the outputs are transferred.
+func (this *Verifier) Lines() []string {
+	lines := slices.Clone(this.lines)
+	slices.Sort(lines)
+	return lines
+}
+
+func (this *Verifier) NumOutputs() int64 {
+	return this.stat_factory.Value("num_outputs")
+}
+
+func (this *Verifier) NumFailures() int64 {
+	return this.stat_factory.Value("num_failures")
+}
+
+func (this *Verifier) NumIncomplete() int64 {
+	return this.stat_factory.Value("num_incomplete")
+}
+
+// Compare compares an output of a DPU, which is at offset from the symbol name.
+func (this *Verifier) Compare(
+	execution int,
+	channel_id int,
+	rank_id int,
+	dpu_id int,
+	name string,
+	offset int64,
+	expected *encoding.ByteStream,
+	actual *encoding.ByteStream,
+) {
+	layout := this.Layout(name)
+
+	// NOTE: the bytes that are expected but not read are reported as incomplete rather than
+	// compared
+	size := min(expected.Size(), actual.Size())
+
+	first_mismatch := int64(-1)
+	num_mismatches := int64(0)
+	for i := int64(0); i < size; i++ {
+		if !layout.IsSkipped(i) && expected.Get(int(i)) != actual.Get(int(i)) {
+			if first_mismatch == -1 {
+				first_mismatch = i
+			}
+
+			num_mismatches++
+		}
+	}
+
+	this.stat_factory.Increment("num_outputs", 1)
+
+	header := fmt.Sprintf(
+		"execution %d DPU%d-%d-%d %s",
+		execution,
+		channel_id,
+		rank_id,
+		dpu_id,
+		name,
+	)
+
+	if first_mismatch == -1 && size < expected.Size() {
+		this.stat_factory.Increment("num_incomplete", 1)
+		this.stat_factory.Increment(fmt.Sprintf("execution%d_num_incomplete", execution), 1)
+
+		line := fmt.Sprintf(
+			"%s: INCOMPLETE (%d of %d bytes read, the read bytes match)",
+			header,
+			size,
+			expected.Size(),
+		)
+		this.lines = append(this.lines, line)
+
+		if global.Verify == "strict" {
+			err := errors.New(line)
+			panic(err)
+		}
+		return
+	} else if first_mismatch == -1 {
+		this.stat_factory.Increment("num_passes", 1)
+		this.lines = append(this.lines, fmt.Sprintf("%s: PASS (%d bytes compared)", header, size))
+		return
+	}
+
+	this.stat_factory.Increment("num_failures", 1)
+	this.stat_factory.Increment(fmt.Sprintf("execution%d_num_failures", execution), 1)
+	this.stat_factory.Increment("num_mismatched_bytes", num_mismatches)
+
+	element := first_mismatch / layout.ElementSize()
+
+	line := fmt.Sprintf(
+		"%s: FAIL at %s+%d (element %d of %d bytes): expected %s, actual %s (%d of %d bytes differ)",
+		header,
+		name,
+		offset+first_mismatch,
+		element,
+		layout.ElementSize(),
+		this.StringifyElement(expected, layout, element),
+		this.StringifyElement(actual, layout, element),
+		num_mismatches,
+		size,
+	)
+
+	this.lines = append(this.lines, line)
+
+	if global.Verify == "strict" {
+		err := errors.New(line)
+		panic(err)
+	}
+}
+
+// StringifyElement formats an element as a little-endian hexadecimal value, with the skipped
+// bytes as "??".
+func (this *Verifier) StringifyElement(
+	byte_stream *encoding.ByteStream,
+	layout *OutputLayout,
+	element int64,
+) string {
+	begin := element * layout.ElementSize()
+	end := min(begin+layout.ElementSize(), byte_stream.Size())
+
+	str := "0x"
+	for i := end - 1; i >= begin; i-- {
+		if layout.IsSkipped(i) {
+			str += "??"
+		} else {
+			str += fmt.Sprintf("%02x", byte_stream.Get(int(i)))
+		}
+	}
+	return str
+}
@@ -0,0 +1,214 @@
+package host_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/simulator/host"
+)
+
+func NewVerifier(t *testing.T, output_layouts string) *host.Verifier {
+	global.BinDirpath = t.TempDir()
+
+	if output_layouts != "" {
+		path := filepath.Join(global.BinDirpath, "output_layouts.txt")
+		if err := os.WriteFile(path, []byte(output_layouts), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	verifier := new(host.Verifier)
+	verifier.Init()
+	return verifier
+}
+
+func ByteStream(bytes ...byte) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for _, byte_ := range bytes {
+		byte_stream.Append(byte_)
+	}
+	return byte_stream
+}
+
+func TestVerifierCompare(t *testing.T) {
+	global.Verify = "report"
+
+	// NOTE: the second half of every 4-byte element of DPU_RESULTS is skipped
+	output_layouts := "DPU_RESULTS: 4 2 4\n"
+
+	tests := []struct {
+		name          string
+		symbol        string
+		expected      *encoding.ByteStream
+		actual        *encoding.ByteStream
+		want_line     string
+		want_passes   int64
+		want_failures int64
+	}{
+		{
+			"pass",
+			"output",
+			ByteStream(1, 2, 3),
+			ByteStream(1, 2, 3),
+			"execution 0 DPU0-0-1 output: PASS (3 bytes compared)",
+			1,
+			0,
+		},
+		{
+			"longer read passes",
+			"output",
+			ByteStream(1, 2),
+			ByteStream(1, 2, 9, 9),
+			"execution 0 DPU0-0-1 output: PASS (2 bytes compared)",
+			1,
+			0,
+		},
+		{
+			"byte by byte failure",
+			"output",
+			ByteStream(1, 2, 3, 4),
+			ByteStream(1, 2, 7, 8),
+			"execution 0 DPU0-0-1 output: FAIL at output+2 (element 2 of 1 bytes): " +
+				"expected 0x03, actual 0x07 (2 of 4 bytes differ)",
+			0,
+			1,
+		},
+		{
+			"skipped bytes pass",
+			"DPU_RESULTS",
+			ByteStream(1, 0, 5, 5, 2, 0, 5, 5),
+			ByteStream(1, 0, 6, 6, 2, 0, 7, 7),
+			"execution 0 DPU0-0-1 DPU_RESULTS: PASS (8 bytes compared)",
+			1,
+			0,
+		},
+		{
+			"failure in a layout element",
+			"DPU_RESULTS",
+			ByteStream(1, 0, 5, 5, 2, 0, 5, 5),
+			ByteStream(1, 0, 6, 6, 3, 0, 7, 7),
+			"execution 0 DPU0-0-1 DPU_RESULTS: FAIL at DPU_RESULTS+4 (element 1 of 4 bytes): " +
+				"expected 0x????0002, actual 0x????0003 (1 of 8 bytes differ)",
+			0,
+			1,
+		},
+		{
+			"short read is incomplete",
+			"output",
+			ByteStream(1, 2, 3, 4),
+			ByteStream(1, 2),
+			"execution 0 DPU0-0-1 output: INCOMPLETE (2 of 4 bytes read, the read bytes match)",
+			0,
+			0,
+		},
+		{
+			"short read with a mismatch fails",
+			"output",
+			ByteStream(1, 2, 3, 4),
+			ByteStream(9, 2),
+			"execution 0 DPU0-0-1 output: FAIL at output+0 (element 0 of 1 bytes): " +
+				"expected 0x01, actual 0x09 (1 of 2 bytes differ)",
+			0,
+			1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifier := NewVerifier(t, output_layouts)
+			verifier.Compare(0, 0, 0, 1, test.symbol, 0, test.expected, test.actual)
+
+			if lines := verifier.Lines(); !slices.Equal(lines, []string{test.want_line}) {
+				t.Errorf("Lines() = %q, want %q", lines, test.want_line)
+			}
+
+			stat_factory := verifier.StatFactory()
+			if num_passes := stat_factory.Value("num_passes"); num_passes != test.want_passes {
+				t.Errorf("num_passes = %d, want %d", num_passes, test.want_passes)
+			}
+
+			if verifier.NumFailures() != test.want_failures {
+				t.Errorf("NumFailures() = %d, want %d", verifier.NumFailures(), test.want_failures)
+			}
+
+			if verifier.NumOutputs() != 1 {
+				t.Errorf("NumOutputs() = %d, want 1", verifier.NumOutputs())
+			}
+		})
+	}
+}
+
+func TestVerifierStrict(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   *encoding.ByteStream
+		actual     *encoding.ByteStream
+		want_panic bool
+	}{
+		{"pass", ByteStream(1, 2), ByteStream(1, 2), false},
+		{"failure", ByteStream(1, 2), ByteStream(1, 3), true},
+		{"incomplete", ByteStream(1, 2), ByteStream(1), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			global.Verify = "strict"
+			defer func() {
+				global.Verify = "report"
+
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			verifier := NewVerifier(t, "")
+			verifier.Compare(0, 0, 0, 0, "output", 0, test.expected, test.actual)
+		})
+	}
+}
+
+func TestVerifierLayouts(t *testing.T) {
+	tests := []struct {
+		name            string
+		output_layouts  string
+		want_panic      bool
+		want_element    int64
+		want_is_skipped []bool
+	}{
+		{"no file", "", false, 1, []bool{false, false}},
+		{"layout", "DPU_RESULTS: 4 1 3\n", false, 4, []bool{false, true, true, false, false, true}},
+		{"whole element skipped", "DPU_RESULTS: 2 0 2\n", false, 2, []bool{true, true, true}},
+		{"missing colon", "DPU_RESULTS 4 1 3\n", true, 0, nil},
+		{"missing field", "DPU_RESULTS: 4 1\n", true, 0, nil},
+		{"not a number", "DPU_RESULTS: four 1 3\n", true, 0, nil},
+		{"zero element size", "DPU_RESULTS: 0 0 0\n", true, 0, nil},
+		{"skipped bytes past the element", "DPU_RESULTS: 4 2 5\n", true, 0, nil},
+		{"skipped bytes reversed", "DPU_RESULTS: 4 3 1\n", true, 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if is_panic := recover() != nil; is_panic != test.want_panic {
+					t.Errorf("panic = %v, want %v", is_panic, test.want_panic)
+				}
+			}()
+
+			layout := NewVerifier(t, test.output_layouts).Layout("DPU_RESULTS")
+
+			if layout.ElementSize() != test.want_element {
+				t.Errorf("ElementSize() = %d, want %d", layout.ElementSize(), test.want_element)
+			}
+
+			for pos, want := range test.want_is_skipped {
+				if layout.IsSkipped(int64(pos)) != want {
+					t.Errorf("IsSkipped(%d) = %v, want %v", pos, !want, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+type HostStatementType int
+
+const (
+	ALLOC HostStatementType = iota
+	LOAD
+	COPY_TO
+	BROADCAST
+	COPY_FROM
+	LAUNCH
+	SYNC
+	COMPUTE
+	LOOP
+	WHILE
+	END
+)
+
+// HostStatement is a statement of a host script. A loop or while statement knows the index of its
+// end statement and an end statement the index of its loop or while statement.
+type HostStatement struct {
+	host_statement_type HostStatementType
+	line                int
+
+	symbol string
+	offset int64
+	size   int64
+	file   string
+	value  int64
+	name   string
+	target int
+}
+
+func (this *HostStatement) HostStatementType() HostStatementType {
+	return this.host_statement_type
+}
+
+func (this *HostStatement) Line() int {
+	return this.line
+}
+
+func (this *HostStatement) Symbol() string {
+	return this.symbol
+}
+
+func (this *HostStatement) Offset() int64 {
+	return this.offset
+}
+
+func (this *HostStatement) Size() int64 {
+	return this.size
+}
+
+func (this *HostStatement) File() string {
+	return this.file
+}
+
+func (this *HostStatement) Value() int64 {
+	return this.value
+}
+
+func (this *HostStatement) Name() string {
+	return this.name
+}
+
+func (this *HostStatement) Target() int {
+	return this.target
+}
+
+// HostScript is a host program made of one statement per line; # starts a comment:
+//
+//	alloc <num_dpus>                           use the first num_dpus DPUs (default: all)
+//	load                                       load the DPU program again
+//	copy_to <symbol>[+<offset>] <file>         write a buffer per DPU (dpu_push_xfer)
+//	broadcast <symbol>[+<offset>] <file>       write the same buffer to every DPU
+//	copy_from <symbol>[+<offset>] <size> [<file>]
+//	                                           read a buffer per DPU, optionally into bin_dirpath
+//	launch [sync|async]                        launch the DPUs (default: sync)
+//	sync                                       wait for an asynchronous launch
+//	compute <cycles>                           host-side computation
+//	loop <name> <count> ... end                repeat count times
+//	while <symbol>[+<offset>] ... end          repeat while a 32-bit word is nonzero on any DPU
+//
+// A file name may contain {dpu} (the DPU ID), {execution} (the number of finished launches) and
+// {<name>} (the iteration of an enclosing loop). Input files are relative to the script.
+type HostScript struct {
+	dirpath    string
+	statements []*HostStatement
+}
+
+func (this *HostScript) Init(path string) {
+	this.dirpath = filepath.Dir(path)
+	this.statements = make([]*HostStatement, 0)
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	blocks := make([]int, 0)
+	for i, line := range file_scanner.ReadLines() {
+		if index := strings.Index(line, "#"); index != -1 {
+			line = line[:index]
+		}
+
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+
+		host_statement := this.Parse(i+1, words)
+
+		if host_statement.host_statement_type == LOOP || host_statement.host_statement_type == WHILE {
+			blocks = append(blocks, len(this.statements))
+		} else if host_statement.host_statement_type == END {
+			if len(blocks) == 0 {
+				err_msg := fmt.Sprintf("line %d: end without loop or while", i+1)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			begin := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+
+			host_statement.target = begin
+			this.statements[begin].target = len(this.statements)
+
+			if this.statements[begin].host_statement_type == WHILE &&
+				!this.IsBlocking(begin+1, len(this.statements)) {
+				err_msg := fmt.Sprintf(
+					"line %d: while without launch, sync, transfer or compute",
+					this.statements[begin].line,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+		}
+
+		this.statements = append(this.statements, host_statement)
+	}
+
+	if len(blocks) != 0 {
+		err_msg := fmt.Sprintf(
+			"line %d: loop or while without end",
+			this.statements[blocks[0]].line,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+}
+
+// IsBlocking returns whether any of the statements [begin, end) keeps the host busy or touches the
+// DPUs. The symbol of a while statement only changes when the DPUs run or the host transfers, so a
+// while body without such a statement would never end.
+func (this *HostScript) IsBlocking(begin int, end int) bool {
+	for _, host_statement := range this.statements[begin:end] {
+		switch host_statement.host_statement_type {
+		case LAUNCH, SYNC, COPY_TO, BROADCAST, COPY_FROM:
+			return true
+		case COMPUTE:
+			if host_statement.value > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (this *HostScript) Statements() []*HostStatement {
+	return this.statements
+}
+
+func (this *HostScript) Dirpath() string {
+	return this.dirpath
+}
+
+func (this *HostScript) Parse(line int, words []string) *HostStatement {
+	host_statement := new(HostStatement)
+	host_statement.line = line
+	host_statement.target = -1
+
+	num_operands := map[string][]int{
+		"alloc":     {1},
+		"load":      {0},
+		"copy_to":   {2},
+		"broadcast": {2},
+		"copy_from": {2, 3},
+		"launch":    {0, 1},
+		"sync":      {0},
+		"compute":   {1},
+		"loop":      {2},
+		"while":     {1},
+		"end":       {0},
+	}
+
+	counts, found := num_operands[words[0]]
+	if !found {
+		err_msg := fmt.Sprintf("line %d: unknown statement %s", line, words[0])
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	is_valid := false
+	for _, count := range counts {
+		if len(words)-1 == count {
+			is_valid = true
+		}
+	}
+
+	if !is_valid {
+		err_msg := fmt.Sprintf("line %d: wrong number of operands for %s", line, words[0])
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	switch words[0] {
+	case "alloc":
+		host_statement.host_statement_type = ALLOC
+		host_statement.value = this.ParseInt(line, words[1])
+	case "load":
+		host_statement.host_statement_type = LOAD
+	case "copy_to":
+		host_statement.host_statement_type = COPY_TO
+		host_statement.symbol, host_statement.offset = this.ParseSymbol(line, words[1])
+		host_statement.file = words[2]
+	case "broadcast":
+		host_statement.host_statement_type = BROADCAST
+		host_statement.symbol, host_statement.offset = this.ParseSymbol(line, words[1])
+		host_statement.file = words[2]
+	case "copy_from":
+		host_statement.host_statement_type = COPY_FROM
+		host_statement.symbol, host_statement.offset = this.ParseSymbol(line, words[1])
+		host_statement.size = this.ParseInt(line, words[2])
+
+		if len(words) == 4 {
+			host_statement.file = words[3]
+		}
+	case "launch":
+		host_statement.host_statement_type = LAUNCH
+		host_statement.name = "sync"
+
+		if len(words) == 2 {
+			if words[1] != "sync" && words[1] != "async" {
+				err_msg := fmt.Sprintf("line %d: launch mode %s is not valid", line, words[1])
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			host_statement.name = words[1]
+		}
+	case "sync":
+		host_statement.host_statement_type = SYNC
+	case "compute":
+		host_statement.host_statement_type = COMPUTE
+		host_statement.value = this.ParseInt(line, words[1])
+	case "loop":
+		host_statement.host_statement_type = LOOP
+		host_statement.name = words[1]
+		host_statement.value = this.ParseInt(line, words[2])
+	case "while":
+		host_statement.host_statement_type = WHILE
+		host_statement.symbol, host_statement.offset = this.ParseSymbol(line, words[1])
+	case "end":
+		host_statement.host_statement_type = END
+	}
+
+	return host_statement
+}
+
+func (this *HostScript) ParseInt(line int, word string) int64 {
+	value, parse_err := strconv.ParseInt(word, 0, 64)
+	if parse_err != nil {
+		err_msg := fmt.Sprintf("line %d: %s is not a number", line, word)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if value < 0 {
+		err_msg := fmt.Sprintf("line %d: %s < 0", line, word)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return value
+}
+
+// ParseSymbol splits symbol+offset into the symbol and the byte offset.
+func (this *HostScript) ParseSymbol(line int, word string) (string, int64) {
+	words := strings.SplitN(word, "+", 2)
+
+	if len(words) == 1 {
+		return words[0], 0
+	}
+
+	return words[0], this.ParseInt(line, words[1])
+}
@@ -0,0 +1,166 @@
+package simulator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"uPIMulator/src/simulator"
+)
+
+func NewHostScript(t *testing.T, script string) *simulator.HostScript {
+	path := filepath.Join(t.TempDir(), "host.script")
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	host_script := new(simulator.HostScript)
+	host_script.Init(path)
+	return host_script
+}
+
+func TestHostScriptParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		want_types  []simulator.HostStatementType
+		want_target []int
+	}{
+		{"empty", "", nil, nil},
+		{
+			"comments and blank lines",
+			"# setup\n\nalloc 4 # four DPUs\n",
+			[]simulator.HostStatementType{simulator.ALLOC},
+			[]int{-1},
+		},
+		{
+			"straight line",
+			"load\ncopy_to input in.bin\nbroadcast n+4 n.bin\nlaunch async\nsync\n" +
+				"copy_from output 64 out.bin\ncompute 100\n",
+			[]simulator.HostStatementType{
+				simulator.LOAD,
+				simulator.COPY_TO,
+				simulator.BROADCAST,
+				simulator.LAUNCH,
+				simulator.SYNC,
+				simulator.COPY_FROM,
+				simulator.COMPUTE,
+			},
+			[]int{-1, -1, -1, -1, -1, -1, -1},
+		},
+		{
+			"loop",
+			"loop i 3\nlaunch\nend\n",
+			[]simulator.HostStatementType{simulator.LOOP, simulator.LAUNCH, simulator.END},
+			[]int{2, -1, 0},
+		},
+		{
+			"nested blocks",
+			"loop i 2\nwhile flag\nlaunch\nend\ncompute 0\nend\n",
+			[]simulator.HostStatementType{
+				simulator.LOOP,
+				simulator.WHILE,
+				simulator.LAUNCH,
+				simulator.END,
+				simulator.COMPUTE,
+				simulator.END,
+			},
+			[]int{5, 3, -1, 1, -1, 0},
+		},
+		{
+			"while with compute",
+			"while done+8\ncompute 10\nend\n",
+			[]simulator.HostStatementType{simulator.WHILE, simulator.COMPUTE, simulator.END},
+			[]int{2, -1, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statements := NewHostScript(t, test.script).Statements()
+
+			if len(statements) != len(test.want_types) {
+				t.Fatalf("len(Statements()) = %d, want %d", len(statements), len(test.want_types))
+			}
+
+			for i, host_statement := range statements {
+				if host_statement.HostStatementType() != test.want_types[i] {
+					t.Errorf(
+						"statement %d: type = %d, want %d",
+						i,
+						host_statement.HostStatementType(),
+						test.want_types[i],
+					)
+				}
+
+				if host_statement.Target() != test.want_target[i] {
+					t.Errorf(
+						"statement %d: target = %d, want %d",
+						i,
+						host_statement.Target(),
+						test.want_target[i],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestHostScriptOperands(t *testing.T) {
+	script := "alloc 0x10\ncopy_from output+16 64\nlaunch\nloop iteration 5\nend\n"
+	statements := NewHostScript(t, script).Statements()
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"alloc value", statements[0].Value(), int64(16)},
+		{"copy_from symbol", statements[1].Symbol(), "output"},
+		{"copy_from offset", statements[1].Offset(), int64(16)},
+		{"copy_from size", statements[1].Size(), int64(64)},
+		{"copy_from without file", statements[1].File(), ""},
+		{"default launch mode", statements[2].Name(), "sync"},
+		{"loop name", statements[3].Name(), "iteration"},
+		{"loop count", statements[3].Value(), int64(5)},
+		{"line", statements[4].Line(), 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.got != test.want {
+				t.Errorf("got %v, want %v", test.got, test.want)
+			}
+		})
+	}
+}
+
+func TestHostScriptInit(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"unknown statement", "free\n"},
+		{"missing operand", "copy_to input\n"},
+		{"extra operand", "sync now\n"},
+		{"not a number", "compute many\n"},
+		{"negative number", "alloc -1\n"},
+		{"negative offset", "copy_to input+-4 in.bin\n"},
+		{"invalid launch mode", "launch later\n"},
+		{"end without loop", "launch\nend\n"},
+		{"loop without end", "loop i 2\nlaunch\n"},
+		{"while that never blocks", "while flag\ncompute 0\nend\n"},
+		{"empty while", "while flag\nend\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Init() did not panic")
+				}
+			}()
+
+			NewHostScript(t, test.script)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package simulator
+
+import (
+	"fmt"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/host"
+)
+
+// MicrobenchmarkReport prints the measurements the hosts of the PrIM microbenchmarks print, so
+// that the simulated pipeline, revolver and DRAM parameters can be checked against hardware runs.
+//
+// The tasklets of a microbenchmark count their cycles with the performance counter and store them
+// in DPU_RESULTS, an array of uint64_t per tasklet, which is read after each execution without
+// taking simulated time. Like the PrIM hosts, the DPU cycles of an execution are the maximum over
+// the DPUs of the maximum over the tasklets, and the printed values are averages over the
+// executions.
+type MicrobenchmarkReport struct {
+	host *host.Host
+
+	has_results     bool
+	results_address int64
+
+	num_executions int64
+
+	stat_factory *misc.StatFactory
+}
+
+var microbenchmarks = map[string]bool{
+	"Arithmetic-Throughput": true,
+	"CPU-DPU":               true,
+	"MRAM-Latency":          true,
+	"Operational-Intensity": true,
+	"Random-GUPS":           true,
+	"STREAM":                true,
+	"STRIDED":               true,
+	"WRAM":                  true,
+}
+
+func IsMicrobenchmark(benchmark string) bool {
+	return microbenchmarks[benchmark]
+}
+
+func (this *MicrobenchmarkReport) Init(host_ *host.Host) {
+	this.host = host_
+
+	// NOTE: the kernel of CPU-DPU is empty, so it only reports the transfer bandwidths
+	this.has_results = host_.HasSymbol("DPU_RESULTS")
+	if this.has_results {
+		this.results_address = host_.SymbolAddress("DPU_RESULTS")
+	}
+
+	this.num_executions = 0
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("Microbenchmark")
+}
+
+func (this *MicrobenchmarkReport) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Record reads the DPU cycles of an execution that has just finished.
+func (this *MicrobenchmarkReport) Record(execution int) {
+	this.num_executions++
+
+	if !this.has_results {
+		return
+	}
+
+	max_cycles := int64(0)
+	min_cycles := int64(-1)
+	for _, dpu_ := range this.host.Dpus() {
+		dpu_cycles := int64(0)
+		for tasklet_id := 0; tasklet_id < global.NumTasklets; tasklet_id++ {
+			byte_stream := dpu_.Wram().Read(this.results_address+int64(8*tasklet_id), 8)
+
+			cycles := int64(0)
+			for i := 0; i < 8; i++ {
+				cycles |= int64(byte_stream.Get(i)) << (8 * i)
+			}
+
+			dpu_cycles = max(dpu_cycles, cycles)
+		}
+
+		max_cycles = max(max_cycles, dpu_cycles)
+		if min_cycles == -1 || dpu_cycles < min_cycles {
+			min_cycles = dpu_cycles
+		}
+	}
+
+	this.stat_factory.Increment("dpu_cycles", max_cycles)
+	this.stat_factory.Increment("min_dpu_cycles", min_cycles)
+	this.stat_factory.Increment(fmt.Sprintf("execution%d_dpu_cycles", execution), max_cycles)
+}
+
+func (this *MicrobenchmarkReport) Print() {
+	if this.num_executions == 0 {
+		return
+	}
+
+	if this.has_results {
+		fmt.Printf(
+			"DPU cycles  = %g cc\n",
+			float64(this.stat_factory.Value("dpu_cycles"))/float64(this.num_executions),
+		)
+	}
+
+	parts := map[string]string{"cpu_dpu": "CPU-DPU", "kernel": "DPU Kernel", "dpu_cpu": "DPU-CPU"}
+
+	host_stat_factory := this.host.StatFactory()
+	for _, stat := range []string{"cpu_dpu", "kernel", "dpu_cpu"} {
+		time_ms := this.Milliseconds(host_stat_factory.Value(stat + "_cycles"))
+		fmt.Printf("%s Time (ms): %f\n", parts[stat], time_ms)
+
+		// NOTE: like the PrIM host, the bandwidth counts the bytes of every DPU, even when the
+		// host broadcasts them
+		if !this.has_results && stat != "kernel" && time_ms > 0 {
+			num_bytes := float64(host_stat_factory.Value(stat+"_bytes")) / float64(this.num_executions)
+			fmt.Printf("%s Bandwidth (GB/s): %f\n", parts[stat], num_bytes/(time_ms*1e6))
+		}
+	}
+}
+
+// Milliseconds returns the average time of an execution in milliseconds at logic_frequency.
+func (this *MicrobenchmarkReport) Milliseconds(cycles int64) float64 {
+	return float64(cycles) / float64(this.num_executions) / float64(global.LogicFrequency) / 1000
+}
@@ -0,0 +1,149 @@
+package simulator
+
+import (
+	"fmt"
+	"uPIMulator/src/global"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
+	"uPIMulator/src/simulator/symbol"
+)
+
+// ProgressMonitor aborts the simulation when the DPUs stop making forward progress or when the
+// maximum number of cycles is reached. A DPU makes progress when it retires an instruction other
+// than a failed acquire (the SDK spins on "acquire ..., nz, ." while a lock is taken) or when it
+// has an outstanding DMA, so both a deadlock (every tasklet sleeps) and a livelock (every tasklet
+// spins on a lock that is never released) are detected. A DMA fault under --dma_check strict also
+// aborts the simulation.
+type ProgressMonitor struct {
+	dpus         []*dpu.Dpu
+	symbol_table *symbol.SymbolTable
+
+	cycles              int64
+	num_progresses      int64
+	last_progress_cycle int64
+
+	reason *string
+}
+
+func (this *ProgressMonitor) Init(dpus []*dpu.Dpu, symbol_table *symbol.SymbolTable) {
+	this.dpus = dpus
+	this.symbol_table = symbol_table
+
+	this.cycles = 0
+	this.num_progresses = 0
+	this.last_progress_cycle = 0
+
+	this.reason = nil
+}
+
+func (this *ProgressMonitor) Fini() {
+}
+
+func (this *ProgressMonitor) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *ProgressMonitor) HasAborted() bool {
+	return this.reason != nil
+}
+
+func (this *ProgressMonitor) Reason() string {
+	return *this.reason
+}
+
+func (this *ProgressMonitor) Cycle() {
+	this.cycles++
+
+	num_progresses := int64(0)
+	has_outstanding_dma := false
+	has_faulted := false
+	is_idle := true
+	for _, dpu_ := range this.dpus {
+		for _, thread := range dpu_.Threads() {
+			if thread.ThreadState() != logic.EMBRYO && thread.ThreadState() != logic.ZOMBIE {
+				is_idle = false
+			}
+		}
+
+		if dpu_.DmaChecker() != nil && dpu_.DmaChecker().HasFaulted() {
+			has_faulted = true
+		}
+
+		stat_factory := dpu_.Logic().StatFactory()
+		num_progresses += stat_factory.Value("num_instructions") - stat_factory.Value("num_failed_acquires")
+
+		if dpu_.HasOutstandingDma() {
+			has_outstanding_dma = true
+		}
+	}
+
+	// NOTE: while no tasklet is alive, the host transfers, launches or computes, which is progress
+	if num_progresses != this.num_progresses || has_outstanding_dma || is_idle {
+		this.num_progresses = num_progresses
+		this.last_progress_cycle = this.cycles
+	}
+
+	if has_faulted {
+		reason := "DMA fault (ldma/sdma violates the DMA engine constraints)"
+		this.reason = &reason
+	} else if global.DeadlockWindow > 0 && this.cycles-this.last_progress_cycle >= global.DeadlockWindow {
+		reason := fmt.Sprintf(
+			"no forward progress for %d cycles (deadlock or livelock)",
+			this.cycles-this.last_progress_cycle,
+		)
+		this.reason = &reason
+	} else if global.MaxCycles > 0 && this.cycles >= global.MaxCycles {
+		reason := fmt.Sprintf("reached max_cycles (%d)", global.MaxCycles)
+		this.reason = &reason
+	}
+}
+
+// Lines describes the state of every non-zombie tasklet and every held atomic bit.
+func (this *ProgressMonitor) Lines() []string {
+	lines := make([]string, 0)
+	lines = append(lines, fmt.Sprintf("simulation aborted at cycle %d: %s", this.cycles, this.Reason()))
+
+	for _, dpu_ := range this.dpus {
+		if dpu_.IsZombie() {
+			continue
+		}
+
+		name := fmt.Sprintf("DPU%d-%d-%d", dpu_.ChannelId(), dpu_.RankId(), dpu_.DpuId())
+
+		for _, thread := range dpu_.Threads() {
+			if thread.ThreadState() == logic.ZOMBIE {
+				continue
+			}
+
+			pc := thread.RegFile().ReadPcReg()
+
+			lines = append(lines, fmt.Sprintf(
+				"%s tasklet %d %s at %d (%s): %s",
+				name,
+				thread.ThreadId(),
+				thread.StringifyThreadState(),
+				pc,
+				this.symbol_table.Symbolize(pc),
+				dpu_.Iram().Read(pc).Stringify(),
+			))
+		}
+
+		atomic := dpu_.Atomic()
+		for address := atomic.Address(); address < atomic.Address()+atomic.Size(); address++ {
+			holder := atomic.Holder(address)
+			if holder == nil {
+				continue
+			}
+
+			lines = append(lines, fmt.Sprintf(
+				"%s atomic bit %d (%s) is held by tasklet %d",
+				name,
+				address,
+				this.symbol_table.Symbolize(address),
+				*holder,
+			))
+		}
+	}
+
+	return lines
+}
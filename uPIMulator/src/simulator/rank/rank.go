@@ -42,6 +42,10 @@ func (this *Rank) Fini() {
 	}
 }
 
+func (this *Rank) ChannelId() int {
+	return this.channel_id
+}
+
 func (this *Rank) RankId() int {
 	return this.rank_id
 }
@@ -0,0 +1,299 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/host"
+)
+
+// ScriptRunner executes a host script against the simulated channels. A transfer or a host-side
+// computation keeps the host busy for the cycles it takes, and a synchronous launch until the host
+// has noticed that the DPUs have finished. After an asynchronous launch, the host goes on with
+// its computations; like the UPMEM SDK, it waits for the launch to finish before it touches the
+// DPUs again.
+type ScriptRunner struct {
+	host     *host.Host
+	channels []*channel.Channel
+
+	host_script *HostScript
+	dpus        []*dpu.Dpu
+
+	pc           int
+	iterations   map[int]int64
+	ready_cycle  int64
+	phase        *string
+	is_launched  bool
+	launch_cycle int64
+	execution    int
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *ScriptRunner) Init(host_ *host.Host, channels []*channel.Channel, path string) {
+	this.host = host_
+	this.channels = channels
+
+	this.host_script = new(HostScript)
+	this.host_script.Init(path)
+
+	this.dpus = host_.Dpus()
+
+	this.pc = 0
+	this.iterations = make(map[int]int64, 0)
+	this.ready_cycle = 0
+	this.phase = nil
+	this.is_launched = false
+	this.launch_cycle = 0
+	this.execution = 0
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("ScriptRunner")
+}
+
+func (this *ScriptRunner) Fini() {
+}
+
+func (this *ScriptRunner) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+func (this *ScriptRunner) IsFinished() bool {
+	return this.pc == len(this.host_script.Statements()) && this.phase == nil && !this.is_launched
+}
+
+func (this *ScriptRunner) Cycle() {
+	this.stat_factory.Increment("cycles", 1)
+
+	for {
+		if this.phase != nil {
+			if !this.IsReady() {
+				this.stat_factory.Increment(*this.phase+"_cycles", 1)
+				return
+			}
+
+			this.phase = nil
+		}
+
+		if this.pc == len(this.host_script.Statements()) {
+			if this.is_launched {
+				this.Wait()
+				continue
+			}
+
+			return
+		}
+
+		host_statement := this.host_script.Statements()[this.pc]
+		this.Execute(host_statement)
+
+		// NOTE: jumping back to a loop or while takes the rest of the cycle, so that an iteration
+		// takes at least one cycle and the other components get to run between iterations
+		if host_statement.HostStatementType() == END && this.phase == nil {
+			return
+		}
+	}
+}
+
+// IsReady returns whether the host is done with its current transfer, computation or launch.
+func (this *ScriptRunner) IsReady() bool {
+	if *this.phase == "kernel" {
+		if !this.host.IsDpusComplete(this.dpus, this.launch_cycle) {
+			return false
+		}
+
+		fmt.Printf("execution (%d) is finished...\n", this.execution)
+
+		this.is_launched = false
+		this.execution++
+		return true
+	}
+
+	return this.host.Cycles() >= this.ready_cycle
+}
+
+// Execute executes a statement, which may make the host busy.
+func (this *ScriptRunner) Execute(host_statement *HostStatement) {
+	host_statement_type := host_statement.HostStatementType()
+
+	// NOTE: every statement but compute and the loop control works on the DPUs, so it waits for an
+	// asynchronous launch to finish
+	is_control := host_statement_type == COMPUTE || host_statement_type == LOOP || host_statement_type == END
+	if this.is_launched && !is_control {
+		this.Wait()
+		return
+	}
+
+	this.pc++
+
+	switch host_statement_type {
+	case ALLOC:
+		if host_statement.Value() == 0 || host_statement.Value() > int64(len(this.host.Dpus())) {
+			err_msg := fmt.Sprintf(
+				"line %d: cannot allocate %d DPUs",
+				host_statement.Line(),
+				host_statement.Value(),
+			)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.dpus = this.host.Dpus()[:host_statement.Value()]
+	case LOAD:
+		this.host.Load()
+	case COPY_TO, BROADCAST:
+		is_broadcast := host_statement_type == BROADCAST
+
+		byte_streams := make(map[*dpu.Dpu]*encoding.ByteStream, 0)
+		for _, dpu_ := range this.dpus {
+			byte_streams[dpu_] = this.ReadFile(this.Substitute(host_statement.File(), dpu_))
+		}
+
+		address := this.host.SymbolAddress(host_statement.Symbol()) + host_statement.Offset()
+		this.Busy("cpu_dpu", this.host.CopyTo(this.execution, address, byte_streams, is_broadcast))
+	case COPY_FROM:
+		address := this.host.SymbolAddress(host_statement.Symbol()) + host_statement.Offset()
+		byte_streams, cycles := this.host.CopyFrom(this.execution, address, host_statement.Size(), this.dpus)
+
+		if host_statement.File() != "" {
+			for _, dpu_ := range this.dpus {
+				this.WriteFile(this.Substitute(host_statement.File(), dpu_), byte_streams[dpu_])
+			}
+		}
+
+		this.Busy("dpu_cpu", cycles)
+	case LAUNCH:
+		this.host.LaunchDpus(this.dpus)
+		this.is_launched = true
+		this.launch_cycle = this.host.Cycles()
+		this.stat_factory.Increment("num_launches", 1)
+
+		if host_statement.Name() == "sync" {
+			this.Wait()
+		}
+	case SYNC:
+	case COMPUTE:
+		this.Busy("compute", host_statement.Value())
+	case LOOP:
+		begin := this.pc - 1
+		if _, found := this.iterations[begin]; !found {
+			this.iterations[begin] = 0
+		}
+
+		if this.iterations[begin] >= host_statement.Value() {
+			delete(this.iterations, begin)
+			this.pc = host_statement.Target() + 1
+		}
+	case WHILE:
+		if !this.IsNonZero(host_statement) {
+			this.pc = host_statement.Target() + 1
+		}
+	case END:
+		begin := host_statement.Target()
+		if this.host_script.Statements()[begin].HostStatementType() == LOOP {
+			this.iterations[begin]++
+		}
+
+		this.pc = begin
+	default:
+		err := errors.New("host statement type is not valid")
+		panic(err)
+	}
+}
+
+// Busy keeps the host busy for the given cycles, the first of which is the current one.
+func (this *ScriptRunner) Busy(phase string, cycles int64) {
+	if cycles == 0 {
+		return
+	}
+
+	this.phase = &phase
+	this.ready_cycle = this.host.Cycles() + cycles
+
+	this.stat_factory.Increment(phase+"_cycles", 1)
+}
+
+// Wait keeps the host busy until the launched DPUs have finished.
+func (this *ScriptRunner) Wait() {
+	phase := "kernel"
+	this.phase = &phase
+}
+
+// IsNonZero returns whether the 32-bit word at the symbol of a while statement is nonzero on any
+// allocated DPU. The host is expected to have copied it back with copy_from, so reading it here
+// takes no time.
+func (this *ScriptRunner) IsNonZero(host_statement *HostStatement) bool {
+	address := this.host.SymbolAddress(host_statement.Symbol()) + host_statement.Offset()
+
+	for _, dpu_ := range this.dpus {
+		rank_ := this.channels[dpu_.ChannelId()].Ranks()[dpu_.RankId()]
+		byte_stream := rank_.Read(dpu_.DpuId(), address, 4)
+
+		bytes := make([]byte, 0)
+		for i := int64(0); i < byte_stream.Size(); i++ {
+			bytes = append(bytes, byte_stream.Get(int(i)))
+		}
+
+		if binary.LittleEndian.Uint32(bytes) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Substitute replaces {dpu}, {execution} and the names of the enclosing loops in a file name.
+func (this *ScriptRunner) Substitute(file string, dpu_ *dpu.Dpu) string {
+	dpu_id := dpu_.ChannelId()*global.NumRanksPerChannel*global.NumDpusPerRank +
+		dpu_.RankId()*global.NumDpusPerRank + dpu_.DpuId()
+
+	file = strings.ReplaceAll(file, "{dpu}", strconv.Itoa(dpu_id))
+	file = strings.ReplaceAll(file, "{execution}", strconv.Itoa(this.execution))
+
+	for begin, iteration := range this.iterations {
+		name := this.host_script.Statements()[begin].Name()
+		file = strings.ReplaceAll(file, "{"+name+"}", strconv.FormatInt(iteration, 10))
+	}
+
+	return file
+}
+
+func (this *ScriptRunner) ReadFile(file string) *encoding.ByteStream {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(this.host_script.Dirpath(), file)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, value := range data {
+		byte_stream.Append(value)
+	}
+
+	return byte_stream
+}
+
+func (this *ScriptRunner) WriteFile(file string, byte_stream *encoding.ByteStream) {
+	data := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		data = append(data, byte_stream.Get(int(i)))
+	}
+
+	if err := os.WriteFile(filepath.Join(global.BinDirpath, file), data, 0644); err != nil {
+		panic(err)
+	}
+}
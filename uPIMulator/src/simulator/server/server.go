@@ -0,0 +1,602 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/host"
+)
+
+// Request is a call of the host API. Which fields are used depends on the operation; see the
+// README for the wire protocol.
+type Request struct {
+	Id_        int64          `json:"id"`
+	Op_        string         `json:"op"`
+	NumDpus_   int            `json:"num_dpus"`
+	Dpu_       int            `json:"dpu"`
+	Dpus_      []int          `json:"dpus"`
+	Symbol_    string         `json:"symbol"`
+	Offset_    int64          `json:"offset"`
+	Size_      int64          `json:"size"`
+	Data_      []byte         `json:"data"`
+	Buffers_   map[int][]byte `json:"buffers"`
+	Direction_ string         `json:"direction"`
+	Mode_      string         `json:"mode"`
+	Path_      string         `json:"path"`
+}
+
+type Response struct {
+	Id_      int64          `json:"id"`
+	Ok_      bool           `json:"ok"`
+	Error_   string         `json:"error,omitempty"`
+	Cycles_  int64          `json:"cycles"`
+	NumDpus_ int            `json:"num_dpus,omitempty"`
+	Data_    []byte         `json:"data,omitempty"`
+	Text_    string         `json:"text,omitempty"`
+	Buffers_ map[int][]byte `json:"buffers,omitempty"`
+}
+
+// Server implements the operations behind the UPMEM host API (dpu_alloc, dpu_load,
+// dpu_copy_to/dpu_copy_from, dpu_push_xfer, dpu_broadcast_to, dpu_launch, dpu_sync and
+// dpu_log_read) on a Unix socket, so that a thin client library can run an unmodified host
+// program against the simulated DPUs.
+//
+// The simulator only cycles while the server serves a request: a transfer advances it by the
+// cycles the transfer takes and a synchronous launch until the host has noticed that the DPUs
+// have finished. Between requests, the simulated host is idle and no time passes.
+type Server struct {
+	simulator *simulator.Simulator
+	host      *host.Host
+
+	listener net.Listener
+	conn     net.Conn
+
+	dpus         []*dpu.Dpu
+	is_launched  bool
+	launch_cycle int64
+	execution    int
+	is_freed     bool
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *Server) Init(simulator_ *simulator.Simulator) {
+	this.simulator = simulator_
+	this.host = simulator_.Host()
+
+	// NOTE: a socket left behind by a previous run would make listen fail
+	if err := os.Remove(global.HostSocket); err != nil && !os.IsNotExist(err) {
+		panic(err)
+	}
+
+	listener, err := net.Listen("unix", global.HostSocket)
+	if err != nil {
+		panic(err)
+	}
+
+	this.listener = listener
+	this.conn = nil
+
+	this.dpus = nil
+	this.is_launched = false
+	this.launch_cycle = 0
+	this.execution = 0
+	this.is_freed = false
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("HostServer")
+}
+
+func (this *Server) Fini() {
+	if this.conn != nil {
+		this.conn.Close()
+	}
+
+	this.listener.Close()
+	os.Remove(global.HostSocket)
+}
+
+func (this *Server) StatFactory() *misc.StatFactory {
+	return this.stat_factory
+}
+
+// Run waits for a client and serves it until it frees its DPUs, disconnects or the simulation
+// aborts. An asynchronous launch still running then is waited for.
+func (this *Server) Run() {
+	fmt.Printf("waiting for a host on %s...\n", global.HostSocket)
+
+	conn, err := this.listener.Accept()
+	if err != nil {
+		panic(err)
+	}
+
+	this.conn = conn
+
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+
+	for !this.is_freed && !this.simulator.HasAborted() {
+		request := new(Request)
+		if err := decoder.Decode(request); err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Printf("host API server cannot decode a request: %v\n", err)
+			break
+		}
+
+		response := this.Handle(request)
+		if err := encoder.Encode(response); err != nil {
+			fmt.Printf("host API server cannot encode a response: %v\n", err)
+			break
+		}
+	}
+
+	if this.is_launched && !this.simulator.HasAborted() {
+		this.Wait()
+	}
+}
+
+func (this *Server) Handle(request *Request) *Response {
+	this.stat_factory.Increment("num_requests", 1)
+
+	response := new(Response)
+	response.Id_ = request.Id_
+
+	err := this.Serve(request, response)
+	if err == nil && this.simulator.HasAborted() {
+		err = errors.New("simulation has aborted")
+	}
+
+	if err != nil {
+		response.Ok_ = false
+		response.Error_ = err.Error()
+	} else {
+		response.Ok_ = true
+	}
+
+	response.Cycles_ = this.host.Cycles()
+	return response
+}
+
+// Serve executes a request. A malformed request is reported to the client rather than aborting
+// the simulation.
+func (this *Server) Serve(request *Request, response *Response) error {
+	if request.Op_ == "alloc" {
+		return this.Alloc(request, response)
+	}
+
+	if this.dpus == nil {
+		err_msg := fmt.Sprintf("%s before alloc", request.Op_)
+		return errors.New(err_msg)
+	}
+
+	// NOTE: like the UPMEM SDK, every operation on the DPUs waits for an asynchronous launch
+	if this.is_launched {
+		this.Wait()
+
+		if this.simulator.HasAborted() {
+			return errors.New("simulation has aborted")
+		}
+	}
+
+	switch request.Op_ {
+	case "free":
+		this.is_freed = true
+		return nil
+	case "load":
+		if err := this.CheckBinary(request.Path_); err != nil {
+			return err
+		}
+
+		this.host.Load()
+		return nil
+	case "copy_to":
+		dpu_, err := this.FindDpu(request.Dpu_)
+		if err != nil {
+			return err
+		}
+
+		byte_streams := map[*dpu.Dpu]*encoding.ByteStream{dpu_: this.ByteStream(request.Data_)}
+		return this.CopyTo(request, byte_streams, false)
+	case "broadcast_to":
+		byte_streams := make(map[*dpu.Dpu]*encoding.ByteStream, 0)
+		for _, dpu_ := range this.dpus {
+			byte_streams[dpu_] = this.ByteStream(request.Data_)
+		}
+
+		return this.CopyTo(request, byte_streams, true)
+	case "copy_from":
+		buffers, err := this.CopyFrom(request, []int{request.Dpu_}, request.Size_)
+		if err != nil {
+			return err
+		}
+
+		response.Data_ = buffers[request.Dpu_]
+		return nil
+	case "push_xfer":
+		return this.PushXfer(request, response)
+	case "launch":
+		return this.Launch(request)
+	case "sync":
+		return nil
+	case "log_read":
+		return this.LogRead(request, response)
+	default:
+		err_msg := fmt.Sprintf("unknown operation %s", request.Op_)
+		return errors.New(err_msg)
+	}
+}
+
+// Alloc allocates the first num_dpus DPUs, or every DPU if num_dpus is 0 (DPU_ALLOCATE_ALL).
+func (this *Server) Alloc(request *Request, response *Response) error {
+	if this.dpus != nil {
+		return errors.New("DPUs are already allocated")
+	}
+
+	num_dpus := request.NumDpus_
+	if num_dpus == 0 {
+		num_dpus = len(this.host.Dpus())
+	}
+
+	if num_dpus < 0 || num_dpus > len(this.host.Dpus()) {
+		err_msg := fmt.Sprintf(
+			"cannot allocate %d DPUs out of %d",
+			request.NumDpus_,
+			len(this.host.Dpus()),
+		)
+		return errors.New(err_msg)
+	}
+
+	this.dpus = this.host.Dpus()[:num_dpus]
+
+	response.NumDpus_ = num_dpus
+	return nil
+}
+
+func (this *Server) PushXfer(request *Request, response *Response) error {
+	if request.Direction_ == "to" {
+		if len(request.Buffers_) == 0 {
+			return errors.New("push_xfer to the DPUs has no buffers")
+		}
+
+		byte_streams := make(map[*dpu.Dpu]*encoding.ByteStream, 0)
+		for index, data := range request.Buffers_ {
+			dpu_, err := this.FindDpu(index)
+			if err != nil {
+				return err
+			}
+
+			byte_streams[dpu_] = this.ByteStream(data)
+		}
+
+		return this.CopyTo(request, byte_streams, false)
+	} else if request.Direction_ == "from" {
+		if len(request.Dpus_) == 0 {
+			return errors.New("push_xfer from the DPUs has no DPUs")
+		}
+
+		buffers, err := this.CopyFrom(request, request.Dpus_, request.Size_)
+		if err != nil {
+			return err
+		}
+
+		response.Buffers_ = buffers
+		return nil
+	} else {
+		err_msg := fmt.Sprintf("transfer direction %s is not valid", request.Direction_)
+		return errors.New(err_msg)
+	}
+}
+
+func (this *Server) CopyTo(
+	request *Request,
+	byte_streams map[*dpu.Dpu]*encoding.ByteStream,
+	is_broadcast bool,
+) error {
+	size := int64(-1)
+	for _, byte_stream := range byte_streams {
+		if size != -1 && byte_stream.Size() != size {
+			return errors.New("buffers of a transfer have different sizes")
+		}
+
+		size = byte_stream.Size()
+	}
+
+	address, err := this.Address(request.Symbol_, request.Offset_, max(size, 0))
+	if err != nil {
+		return err
+	}
+
+	this.Busy("cpu_dpu", this.host.CopyTo(this.execution, address, byte_streams, is_broadcast))
+	return nil
+}
+
+func (this *Server) CopyFrom(request *Request, indices []int, size int64) (map[int][]byte, error) {
+	dpus := make([]*dpu.Dpu, 0)
+	for _, index := range indices {
+		dpu_, err := this.FindDpu(index)
+		if err != nil {
+			return nil, err
+		}
+
+		dpus = append(dpus, dpu_)
+	}
+
+	address, err := this.Address(request.Symbol_, request.Offset_, size)
+	if err != nil {
+		return nil, err
+	}
+
+	byte_streams, cycles := this.host.CopyFrom(this.execution, address, size, dpus)
+	this.Busy("dpu_cpu", cycles)
+
+	buffers := make(map[int][]byte, 0)
+	for i, index := range indices {
+		buffers[index] = this.Bytes(byte_streams[dpus[i]])
+	}
+
+	return buffers, nil
+}
+
+// Launch boots the allocated DPUs. A synchronous launch returns once the host has noticed that
+// they have finished; an asynchronous one returns at once.
+func (this *Server) Launch(request *Request) error {
+	if request.Mode_ != "" && request.Mode_ != "sync" && request.Mode_ != "async" {
+		err_msg := fmt.Sprintf("launch mode %s is not valid", request.Mode_)
+		return errors.New(err_msg)
+	}
+
+	this.host.LaunchDpus(this.dpus)
+	this.is_launched = true
+	this.launch_cycle = this.host.Cycles()
+	this.stat_factory.Increment("num_launches", 1)
+
+	if request.Mode_ != "async" {
+		this.Wait()
+	}
+
+	return nil
+}
+
+// CheckBinary checks that the binary a client loads is the DPU program of --benchmark, the only one
+// that the simulator has compiled. The client may name it after the cmake target
+// (<benchmark>_device), after the benchmark, or dpu_code like the PrIM host programs do. Without a
+// path, the program of --benchmark is loaded.
+func (this *Server) CheckBinary(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	name := filepath.Base(path)
+	if name != global.Benchmark && name != global.Benchmark+"_device" && name != "dpu_code" {
+		err_msg := fmt.Sprintf("%s is not the DPU program of %s", path, global.Benchmark)
+		err := errors.New(err_msg)
+		return err
+	}
+
+	return nil
+}
+
+// LogRead returns the stdout buffer of a DPU (__stdout_buffer) in the order it was written, as
+// described by __stdout_buffer_state and __stdout_buffer_size, and its decoded text.
+func (this *Server) LogRead(request *Request, response *Response) error {
+	for _, symbol := range []string{"__stdout_buffer", "__stdout_buffer_state", "__stdout_buffer_size"} {
+		if !this.host.HasSymbol(symbol) {
+			err_msg := fmt.Sprintf("DPU program has no %s; it does not use printf", symbol)
+			return errors.New(err_msg)
+		}
+	}
+
+	log_request := new(Request)
+	*log_request = *request
+
+	log_request.Symbol_, log_request.Offset_ = "__stdout_buffer_state", 0
+	state, err := this.CopyFrom(log_request, []int{request.Dpu_}, 8)
+	if err != nil {
+		return err
+	}
+
+	log_request.Symbol_ = "__stdout_buffer_size"
+	buffer_size, err := this.CopyFrom(log_request, []int{request.Dpu_}, 4)
+	if err != nil {
+		return err
+	}
+
+	wp := int64(binary.LittleEndian.Uint32(state[request.Dpu_][:4]))
+	has_wrapped := binary.LittleEndian.Uint32(state[request.Dpu_][4:]) != 0
+	size := int64(binary.LittleEndian.Uint32(buffer_size[request.Dpu_]))
+
+	// NOTE: the state lives in DPU memory, so a faulty kernel can leave any write pointer there
+	if wp > size {
+		err_msg := fmt.Sprintf(
+			"write pointer %d of __stdout_buffer_state is beyond %d bytes",
+			wp,
+			size,
+		)
+		err := errors.New(err_msg)
+		return err
+	}
+
+	log_request.Symbol_ = "__stdout_buffer"
+	if !has_wrapped {
+		buffer, err := this.CopyFrom(log_request, []int{request.Dpu_}, wp)
+		if err != nil {
+			return err
+		}
+
+		response.Data_ = buffer[request.Dpu_]
+		response.Text_ = this.Decode(response.Data_)
+		return nil
+	}
+
+	buffer, err := this.CopyFrom(log_request, []int{request.Dpu_}, size)
+	if err != nil {
+		return err
+	}
+
+	response.Data_ = append(buffer[request.Dpu_][wp:], buffer[request.Dpu_][:wp]...)
+	response.Text_ = this.Decode(response.Data_)
+	return nil
+}
+
+func (this *Server) Decode(data []byte) string {
+	stdout_decoder := new(host.StdoutDecoder)
+	text, _ := stdout_decoder.Decode(data)
+	return text
+}
+
+func (this *Server) FindDpu(index int) (*dpu.Dpu, error) {
+	if index < 0 || index >= len(this.dpus) {
+		err_msg := fmt.Sprintf("DPU %d is not allocated", index)
+		return nil, errors.New(err_msg)
+	}
+
+	return this.dpus[index], nil
+}
+
+// Address returns the address of size bytes at offset from symbol. The bytes must lie in the WRAM
+// or the MRAM that holds the symbol; the host cannot transfer to or from the IRAM.
+func (this *Server) Address(symbol string, offset int64, size int64) (int64, error) {
+	if !this.host.HasSymbol(symbol) {
+		err_msg := fmt.Sprintf("DPU program has no symbol %s", symbol)
+		return 0, errors.New(err_msg)
+	} else if offset < 0 {
+		err_msg := fmt.Sprintf("offset %d < 0", offset)
+		return 0, errors.New(err_msg)
+	} else if size < 0 {
+		err_msg := fmt.Sprintf("size %d < 0", size)
+		return 0, errors.New(err_msg)
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	regions := []struct {
+		name   string
+		offset int64
+		size   int64
+	}{
+		{"WRAM", config_loader.WramOffset(), config_loader.WramSize()},
+		{"MRAM", config_loader.MramOffset(), config_loader.MramSize()},
+		{"IRAM", config_loader.IramOffset(), config_loader.IramSize()},
+	}
+
+	symbol_address := this.host.SymbolAddress(symbol)
+	for _, region := range regions {
+		if symbol_address < region.offset || symbol_address >= region.offset+region.size {
+			continue
+		}
+
+		if region.name == "IRAM" {
+			err_msg := fmt.Sprintf(
+				"%s is in IRAM, which the host cannot transfer to or from",
+				symbol,
+			)
+			return 0, errors.New(err_msg)
+		}
+
+		// NOTE: offset and size come from the client, so their sum is checked before it is added
+		if offset > region.offset+region.size-symbol_address-size {
+			err_msg := fmt.Sprintf(
+				"%d bytes at %s+%d are beyond %s (%d bytes at %d)",
+				size,
+				symbol,
+				offset,
+				region.name,
+				region.size,
+				region.offset,
+			)
+			return 0, errors.New(err_msg)
+		}
+
+		return symbol_address + offset, nil
+	}
+
+	err_msg := fmt.Sprintf("%s is in neither WRAM nor MRAM", symbol)
+	return 0, errors.New(err_msg)
+}
+
+// Busy cycles the simulator for the cycles a transfer takes.
+func (this *Server) Busy(phase string, cycles int64) {
+	ready_cycle := this.host.Cycles() + cycles
+
+	for this.host.Cycles() < ready_cycle && !this.simulator.HasAborted() {
+		this.Cycle(phase)
+	}
+}
+
+// Wait cycles the simulator until the host has noticed that the launched DPUs have finished.
+func (this *Server) Wait() {
+	for !this.host.IsDpusComplete(this.dpus, this.launch_cycle) && !this.simulator.HasAborted() {
+		this.Cycle("kernel")
+	}
+
+	if this.simulator.HasAborted() {
+		return
+	}
+
+	fmt.Printf("execution (%d) is finished...\n", this.execution)
+
+	this.is_launched = false
+	this.execution++
+}
+
+func (this *Server) Cycle(phase string) {
+	this.simulator.Cycle()
+
+	this.stat_factory.Increment("cycles", 1)
+	this.stat_factory.Increment(phase+"_cycles", 1)
+}
+
+func (this *Server) ByteStream(data []byte) *encoding.ByteStream {
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+
+	for _, value := range data {
+		byte_stream.Append(value)
+	}
+
+	return byte_stream
+}
+
+func (this *Server) Bytes(byte_stream *encoding.ByteStream) []byte {
+	data := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		data = append(data, byte_stream.Get(int(i)))
+	}
+
+	return data
+}
+
+// Dump writes the server's stats to host_server.txt and prints the time split into CPU-DPU
+// transfers, kernels and DPU-CPU transfers.
+func (this *Server) Dump() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "host_server.txt"))
+	file_dumper.WriteLines(this.stat_factory.ToLines())
+
+	fmt.Printf(
+		"cycles: %d (%.4f ms)\n",
+		this.stat_factory.Value("cycles"),
+		float64(this.stat_factory.Value("cycles"))/float64(global.LogicFrequency)/1000,
+	)
+
+	for _, stat := range []string{"cpu_dpu_cycles", "kernel_cycles", "dpu_cpu_cycles"} {
+		fmt.Printf(
+			"%s: %d (%.4f ms)\n",
+			stat,
+			this.stat_factory.Value(stat),
+			float64(this.stat_factory.Value(stat))/float64(global.LogicFrequency)/1000,
+		)
+	}
+}
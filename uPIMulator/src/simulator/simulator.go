@@ -9,13 +9,23 @@ import (
 	"uPIMulator/src/global"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/dpu"
 	"uPIMulator/src/simulator/host"
+	"uPIMulator/src/simulator/symbol"
 )
 
 type Simulator struct {
 	host     *host.Host
 	channels []*channel.Channel
 
+	symbol_table *symbol.SymbolTable
+
+	progress_monitor *ProgressMonitor
+	async_launcher   *AsyncLauncher
+	script_runner    *ScriptRunner
+
+	microbenchmark_report *MicrobenchmarkReport
+
 	execution int
 }
 
@@ -33,23 +43,99 @@ func (this *Simulator) Init() {
 
 	this.host.ConnectChannels(this.channels)
 
+	this.symbol_table = new(symbol.SymbolTable)
+	this.symbol_table.Init(
+		filepath.Join(global.BinDirpath, "addresses.txt"),
+		filepath.Join(global.BinDirpath, "values.txt"),
+	)
+
+	for _, dpu_ := range this.host.Dpus() {
+		dpu_.ConnectSymbolTable(this.symbol_table)
+	}
+
+	this.progress_monitor = new(ProgressMonitor)
+	this.progress_monitor.Init(this.host.Dpus(), this.symbol_table)
+
 	this.execution = 0
 
 	this.host.Load()
-	this.host.Schedule(this.execution)
-	this.host.Launch()
+
+	this.async_launcher = nil
+	this.script_runner = nil
+	this.microbenchmark_report = nil
+
+	if global.HostScript != "" {
+		this.script_runner = new(ScriptRunner)
+		this.script_runner.Init(this.host, this.channels, global.HostScript)
+	} else if global.HostSocket != "" {
+		// NOTE: the client of the host API server decides what the host does
+	} else if global.LaunchMode == "async" {
+		this.async_launcher = new(AsyncLauncher)
+		this.async_launcher.Init(this.host, this.channels)
+	} else {
+		if IsMicrobenchmark(global.Benchmark) {
+			this.microbenchmark_report = new(MicrobenchmarkReport)
+			this.microbenchmark_report.Init(this.host)
+		}
+
+		this.host.Schedule(this.execution)
+		this.host.Launch()
+	}
 }
 
 func (this *Simulator) Fini() {
-	this.host.Fini()
+	// NOTE: an aborted simulation leaves tasklets running and locks held, which the DPUs refuse
+	// to finalize
+	if !this.progress_monitor.HasAborted() {
+		this.host.Fini()
+	}
+
+	this.progress_monitor.Fini()
+
+	if this.async_launcher != nil {
+		this.async_launcher.Fini()
+	}
+
+	if this.script_runner != nil {
+		this.script_runner.Fini()
+	}
 
 	for _, channel_ := range this.channels {
 		channel_.Fini()
 	}
 }
 
+func (this *Simulator) Dpus() []*dpu.Dpu {
+	return this.host.Dpus()
+}
+
+func (this *Simulator) Host() *host.Host {
+	return this.host
+}
+
+func (this *Simulator) SymbolTable() *symbol.SymbolTable {
+	return this.symbol_table
+}
+
+func (this *Simulator) HasAborted() bool {
+	return this.progress_monitor.HasAborted()
+}
+
 func (this *Simulator) IsFinished() bool {
-	return this.execution == this.host.NumExecutions()
+	// NOTE: the host API server cycles the simulator itself until its client is done
+	if global.HostSocket != "" {
+		return true
+	}
+
+	if this.script_runner != nil {
+		return this.script_runner.IsFinished() || this.progress_monitor.HasAborted()
+	}
+
+	if this.async_launcher != nil {
+		return this.async_launcher.IsFinished() || this.progress_monitor.HasAborted()
+	}
+
+	return this.execution == this.host.NumExecutions() || this.progress_monitor.HasAborted()
 }
 
 func (this *Simulator) Cycle() {
@@ -68,10 +154,35 @@ func (this *Simulator) Cycle() {
 
 	thread_pool.Start()
 
-	if this.host.IsZombie() {
+	this.progress_monitor.Cycle()
+	if this.progress_monitor.HasAborted() {
+		fmt.Printf("execution (%d) is aborted: %s\n", this.execution, this.progress_monitor.Reason())
+		return
+	}
+
+	if this.script_runner != nil {
+		this.script_runner.Cycle()
+		return
+	}
+
+	if global.HostSocket != "" {
+		return
+	}
+
+	if this.async_launcher != nil {
+		this.async_launcher.Cycle()
+		return
+	}
+
+	if this.host.IsComplete() {
 		fmt.Printf("execution (%d) is finished...\n", this.execution)
 
 		this.host.Check(this.execution)
+
+		if this.microbenchmark_report != nil {
+			this.microbenchmark_report.Record(this.execution)
+		}
+
 		this.execution++
 
 		if !this.IsFinished() {
@@ -86,6 +197,8 @@ func (this *Simulator) Cycle() {
 }
 
 func (this *Simulator) Dump() {
+	this.host.FinishDpuLogs()
+
 	file_dumper := new(misc.FileDumper)
 	file_dumper.Init(filepath.Join(global.BinDirpath, "log.txt"))
 
@@ -98,17 +211,275 @@ func (this *Simulator) Dump() {
 		lines = append(lines, dpu_.Logic().StatFactory().ToLines()...)
 		lines = append(lines, dpu_.Logic().CycleRule().StatFactory().ToLines()...)
 		lines = append(lines, dpu_.MemoryController().StatFactory().ToLines()...)
+		lines = append(lines, dpu_.MemoryController().DmaProfiler().Lines(
+			dpu_.MemoryController().Cycles(),
+		)...)
 		lines = append(lines, dpu_.MemoryController().MemoryScheduler().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.MemoryController().RowBuffer().StatFactory().ToLines()...)
+
+		for _, row_buffer := range dpu_.MemoryController().RowBuffers() {
+			lines = append(lines, row_buffer.StatFactory().ToLines()...)
+		}
+
+		lines = append(lines, dpu_.LockProfiler().StatFactory().ToLines()...)
+		lines = append(lines, dpu_.SoftFloatProfiler().StatFactory().ToLines()...)
+
+		if dpu_.Sanitizer() != nil {
+			lines = append(lines, dpu_.Sanitizer().StatFactory().ToLines()...)
+		}
+
+		if dpu_.DmaChecker() != nil {
+			lines = append(lines, dpu_.DmaChecker().StatFactory().ToLines()...)
+		}
+
+		if dpu_.RaceDetector() != nil {
+			lines = append(lines, dpu_.RaceDetector().StatFactory().ToLines()...)
+		}
+
+		if dpu_.HammerTracker() != nil {
+			lines = append(lines, dpu_.HammerTracker().StatFactory().ToLines()...)
+		}
+
 		dpu_.SaveImage()
 	}
 
+	lines = append(lines, this.host.StatFactory().ToLines()...)
+
+	if this.host.Verifier() != nil {
+		lines = append(lines, this.host.Verifier().StatFactory().ToLines()...)
+	}
+
+	if this.async_launcher != nil {
+		lines = append(lines, this.async_launcher.StatFactory().ToLines()...)
+	}
+
+	if this.script_runner != nil {
+		lines = append(lines, this.script_runner.StatFactory().ToLines()...)
+	}
+
+	if this.microbenchmark_report != nil {
+		lines = append(lines, this.microbenchmark_report.StatFactory().ToLines()...)
+	}
+
 	file_dumper.WriteLines(lines)
 
+	this.PrintTimeBreakdown()
+
+	if this.microbenchmark_report != nil {
+		this.microbenchmark_report.Print()
+	}
+
+	if this.host.Verifier() != nil {
+		this.DumpVerifier()
+	}
+
+	if global.Sanitize {
+		this.DumpSanitizer()
+	}
+
+	if global.DmaCheck != "none" {
+		this.DumpDmaChecker()
+	}
+
+	if global.DetectRaces {
+		this.DumpRaceDetector()
+	}
+
+	if global.RecordDmaTrace {
+		this.DumpDmaTrace()
+	}
+
+	if global.HammerThreshold > 0 {
+		this.DumpHammerTracker()
+	}
+
+	if this.progress_monitor.HasAborted() {
+		this.DumpProgressMonitor()
+	}
+
 	CopyWramBin()
 
 }
 
+// PrintTimeBreakdown prints the end-to-end time split into CPU-DPU transfers, launches, DPU
+// kernels, completion polling and DPU-CPU transfers, in logic cycles and in milliseconds at
+// logic_frequency.
+func (this *Simulator) PrintTimeBreakdown() {
+	// NOTE: the host API server prints its own breakdown
+	if global.HostSocket != "" {
+		return
+	}
+
+	if this.script_runner != nil {
+		this.PrintScriptTimeBreakdown()
+		return
+	}
+
+	if this.async_launcher != nil {
+		this.PrintAsyncTimeBreakdown()
+		return
+	}
+
+	stat_factory := this.host.StatFactory()
+	stats := []string{"cpu_dpu_cycles", "launch_cycles", "kernel_cycles", "poll_cycles", "dpu_cpu_cycles"}
+
+	total_cycles := int64(0)
+	for _, stat := range stats {
+		total_cycles += stat_factory.Value(stat)
+	}
+
+	for _, stat := range stats {
+		cycles := stat_factory.Value(stat)
+
+		percentage := 0.0
+		if total_cycles > 0 {
+			percentage = 100 * float64(cycles) / float64(total_cycles)
+		}
+
+		fmt.Printf(
+			"%s: %d (%.4f ms, %.2f%%)\n",
+			stat,
+			cycles,
+			float64(cycles)/float64(global.LogicFrequency)/1000,
+			percentage,
+		)
+	}
+}
+
+// PrintAsyncTimeBreakdown prints the end-to-end time of asynchronous launches and the time the
+// ranks spent in each part. The parts of different ranks overlap, so they add up to more than the
+// end-to-end time.
+func (this *Simulator) PrintAsyncTimeBreakdown() {
+	stat_factory := this.async_launcher.StatFactory()
+
+	fmt.Printf(
+		"cycles: %d (%.4f ms)\n",
+		stat_factory.Value("cycles"),
+		float64(stat_factory.Value("cycles"))/float64(global.LogicFrequency)/1000,
+	)
+
+	for _, stat := range []string{"cpu_dpu_cycles", "kernel_cycles", "dpu_cpu_cycles", "sync_cycles"} {
+		fmt.Printf("%s (summed over ranks): %d\n", stat, stat_factory.Value(stat))
+	}
+}
+
+// PrintScriptTimeBreakdown prints the end-to-end time of a host script and the time the host was
+// busy transferring, computing and waiting for the DPUs. Host-side computations overlap
+// asynchronous launches, so the parts may add up to more than the end-to-end time.
+func (this *Simulator) PrintScriptTimeBreakdown() {
+	stat_factory := this.script_runner.StatFactory()
+
+	fmt.Printf(
+		"cycles: %d (%.4f ms)\n",
+		stat_factory.Value("cycles"),
+		float64(stat_factory.Value("cycles"))/float64(global.LogicFrequency)/1000,
+	)
+
+	for _, stat := range []string{"cpu_dpu_cycles", "kernel_cycles", "dpu_cpu_cycles", "compute_cycles"} {
+		fmt.Printf(
+			"%s: %d (%.4f ms)\n",
+			stat,
+			stat_factory.Value(stat),
+			float64(stat_factory.Value(stat))/float64(global.LogicFrequency)/1000,
+		)
+	}
+}
+
+func (this *Simulator) DumpSanitizer() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "sanitizer.txt"))
+
+	lines := make([]string, 0)
+	for _, dpu_ := range this.host.Dpus() {
+		lines = append(lines, dpu_.Sanitizer().Lines()...)
+	}
+
+	fmt.Printf("sanitizer found %d violation(s)\n", len(lines))
+
+	file_dumper.WriteLines(lines)
+}
+
+func (this *Simulator) DumpVerifier() {
+	verifier := this.host.Verifier()
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "verify.txt"))
+	file_dumper.WriteLines(verifier.Lines())
+
+	fmt.Printf(
+		"verifier found %d mismatching and %d incomplete output(s) out of %d\n",
+		verifier.NumFailures(),
+		verifier.NumIncomplete(),
+		verifier.NumOutputs(),
+	)
+}
+
+func (this *Simulator) DumpDmaChecker() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "dma_check.txt"))
+
+	lines := make([]string, 0)
+	num_violations := int64(0)
+	for _, dpu_ := range this.host.Dpus() {
+		lines = append(lines, dpu_.DmaChecker().Lines()...)
+		num_violations += dpu_.DmaChecker().NumViolations()
+	}
+
+	fmt.Printf("DMA checker found %d violation(s)\n", num_violations)
+
+	file_dumper.WriteLines(lines)
+}
+
+func (this *Simulator) DumpRaceDetector() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "race.txt"))
+
+	lines := make([]string, 0)
+	for _, dpu_ := range this.host.Dpus() {
+		lines = append(lines, dpu_.RaceDetector().Lines()...)
+	}
+
+	fmt.Printf("race detector found %d race(s)\n", len(lines))
+
+	file_dumper.WriteLines(lines)
+}
+
+func (this *Simulator) DumpDmaTrace() {
+	for _, dpu_ := range this.host.Dpus() {
+		dpu_.DmaTrace().Dump(filepath.Join(
+			global.BinDirpath,
+			fmt.Sprintf("dma_trace_%d_%d_%d.txt", dpu_.ChannelId(), dpu_.RankId(), dpu_.DpuId()),
+		))
+	}
+}
+
+func (this *Simulator) DumpHammerTracker() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "hammer.txt"))
+
+	lines := make([]string, 0)
+	num_hammers := int64(0)
+	for _, dpu_ := range this.host.Dpus() {
+		lines = append(lines, dpu_.HammerTracker().Lines()...)
+		num_hammers += dpu_.HammerTracker().StatFactory().Value("num_hammers")
+	}
+
+	fmt.Printf("hammer tracker found %d hammer event(s)\n", num_hammers)
+
+	file_dumper.WriteLines(lines)
+}
+
+func (this *Simulator) DumpProgressMonitor() {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "deadlock.txt"))
+
+	lines := this.progress_monitor.Lines()
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	file_dumper.WriteLines(lines)
+}
+
 func CopyWramBin() {
 	src := filepath.Join(global.BinDirpath, "wram.bin")
 
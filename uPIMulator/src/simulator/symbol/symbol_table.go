@@ -0,0 +1,171 @@
+package symbol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"uPIMulator/src/misc"
+)
+
+type Symbol struct {
+	name    string
+	address int64
+}
+
+func (this *Symbol) Init(name string, address int64) {
+	this.name = name
+	this.address = address
+}
+
+func (this *Symbol) Name() string {
+	return this.name
+}
+
+func (this *Symbol) Address() int64 {
+	return this.address
+}
+
+type SymbolTable struct {
+	addresses map[string]int64
+	values    map[string]int64
+
+	symbols []*Symbol
+}
+
+func (this *SymbolTable) Init(addresses_path string, values_path string) {
+	this.addresses = this.ReadMap(addresses_path)
+	this.values = this.ReadMap(values_path)
+
+	this.symbols = make([]*Symbol, 0)
+	for name, address := range this.addresses {
+		// compiler-generated local labels (e.g., .LBB0_1) are skipped so that an address is symbolized with the function or object that encloses it.
+		if strings.Contains(name, ".L") {
+			continue
+		}
+
+		symbol := new(Symbol)
+		symbol.Init(name, address)
+		this.symbols = append(this.symbols, symbol)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if this.symbols[i].Address() == this.symbols[j].Address() {
+			return this.symbols[i].Name() < this.symbols[j].Name()
+		}
+		return this.symbols[i].Address() < this.symbols[j].Address()
+	}
+
+	sort.Slice(this.symbols, sort_fn)
+}
+
+func (this *SymbolTable) ReadMap(path string) map[string]int64 {
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	lines := file_scanner.ReadLines()
+
+	map_ := make(map[string]int64, 0)
+
+	for _, line := range lines {
+		words := strings.Split(line, ":")
+
+		name := words[0]
+		value, err := strconv.ParseInt(words[1][1:], 10, 64)
+
+		if err != nil {
+			panic(err)
+		}
+
+		map_[name] = value
+	}
+
+	return map_
+}
+
+func (this *SymbolTable) HasAddress(name string) bool {
+	_, found := this.addresses[name]
+	return found
+}
+
+func (this *SymbolTable) Address(name string) int64 {
+	if address, found := this.addresses[name]; found {
+		return address
+	}
+
+	err_msg := fmt.Sprintf("symbol (%s) is not found", name)
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+func (this *SymbolTable) HasValue(name string) bool {
+	_, found := this.values[name]
+	return found
+}
+
+func (this *SymbolTable) Value(name string) int64 {
+	if value, found := this.values[name]; found {
+		return value
+	}
+
+	err_msg := fmt.Sprintf("linker constant (%s) is not found", name)
+	err := errors.New(err_msg)
+	panic(err)
+}
+
+func (this *SymbolTable) Addresses() map[string]int64 {
+	return this.addresses
+}
+
+func (this *SymbolTable) Values() map[string]int64 {
+	return this.values
+}
+
+// Symbols returns the symbols without the local labels, sorted by address.
+func (this *SymbolTable) Symbols() []*Symbol {
+	return this.symbols
+}
+
+// Symbolize returns the closest preceding symbol in the same memory region as address, formatted
+// as name+offset, or the address itself if no such symbol exists.
+func (this *SymbolTable) Symbolize(address int64) string {
+	begin_address, end_address := this.Region(address)
+
+	index := sort.Search(len(this.symbols), func(i int) bool {
+		return this.symbols[i].Address() > address
+	}) - 1
+
+	if index < 0 || this.symbols[index].Address() < begin_address ||
+		this.symbols[index].Address() >= end_address {
+		return strconv.FormatInt(address, 10)
+	}
+
+	symbol := this.symbols[index]
+
+	if symbol.Address() == address {
+		return symbol.Name()
+	} else {
+		return fmt.Sprintf("%s+%d", symbol.Name(), address-symbol.Address())
+	}
+}
+
+func (this *SymbolTable) Region(address int64) (int64, int64) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	regions := [][2]int64{
+		{config_loader.AtomicOffset(), config_loader.AtomicOffset() + config_loader.AtomicSize()},
+		{config_loader.IramOffset(), config_loader.IramOffset() + config_loader.IramSize()},
+		{config_loader.WramOffset(), config_loader.WramOffset() + config_loader.WramSize()},
+		{config_loader.MramOffset(), config_loader.MramOffset() + config_loader.MramSize()},
+	}
+
+	for _, region := range regions {
+		if region[0] <= address && address < region[1] {
+			return region[0], region[1]
+		}
+	}
+
+	return address, address + 1
+}
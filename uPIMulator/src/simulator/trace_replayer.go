@@ -0,0 +1,263 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/global"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu/dram"
+)
+
+// TraceReplayer drives a standalone memory controller with a DMA trace recorded by a full run, so
+// that DRAM parameters can be swept without simulating the DPU logic. In the closed-loop mode
+// (default), a tasklet issues its next DMA once its previous one has completed plus the think
+// time recorded between the two, just like a tasklet that blocks on ldma/sdma. In the open-loop
+// mode, every DMA is issued at its recorded cycle.
+type TraceReplayer struct {
+	mram              *dram.Mram
+	memory_controller *dram.MemoryController
+	hammer_tracker    *dram.HammerTracker
+
+	entries      [][]*dram.DmaTraceEntry
+	next_entries []int
+	ready_cycles []int64
+
+	issue_cycles map[*dram.DmaCommand]int64
+	dma_entries  map[*dram.DmaCommand]*dram.DmaTraceEntry
+
+	cycles int64
+
+	read_latency  *misc.Histogram
+	write_latency *misc.Histogram
+
+	stat_factory *misc.StatFactory
+}
+
+func (this *TraceReplayer) Init(path string) {
+	dma_trace := new(dram.DmaTrace)
+	dma_trace.Init()
+	dma_trace.Load(path)
+
+	this.mram = new(dram.Mram)
+	this.mram.Init()
+
+	this.memory_controller = new(dram.MemoryController)
+	this.memory_controller.Init(0, 0, 0)
+	this.memory_controller.ConnectMram(this.mram)
+
+	if global.HammerThreshold > 0 {
+		this.hammer_tracker = new(dram.HammerTracker)
+		this.hammer_tracker.Init(0, 0, 0, this.memory_controller.MemoryScheduler().AddressMapper())
+		this.hammer_tracker.ConnectMram(this.mram)
+		this.memory_controller.ConnectHammerTracker(this.hammer_tracker)
+	} else {
+		this.hammer_tracker = nil
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.entries = make([][]*dram.DmaTraceEntry, config_loader.MaxNumTasklets())
+	for _, entry := range dma_trace.Entries() {
+		this.entries[entry.ThreadId()] = append(this.entries[entry.ThreadId()], entry)
+	}
+
+	this.next_entries = make([]int, config_loader.MaxNumTasklets())
+	this.ready_cycles = make([]int64, config_loader.MaxNumTasklets())
+	for i, entries := range this.entries {
+		slices.SortStableFunc(entries, func(x *dram.DmaTraceEntry, y *dram.DmaTraceEntry) int {
+			return int(x.IssueCycle() - y.IssueCycle())
+		})
+
+		if len(entries) > 0 {
+			this.ready_cycles[i] = entries[0].IssueCycle()
+		}
+	}
+
+	this.issue_cycles = make(map[*dram.DmaCommand]int64, 0)
+	this.dma_entries = make(map[*dram.DmaCommand]*dram.DmaTraceEntry, 0)
+
+	this.cycles = 0
+
+	this.read_latency = new(misc.Histogram)
+	this.read_latency.Init("read_latency")
+
+	this.write_latency = new(misc.Histogram)
+	this.write_latency.Init("write_latency")
+
+	this.stat_factory = new(misc.StatFactory)
+	this.stat_factory.Init("TraceReplayer")
+}
+
+func (this *TraceReplayer) Fini() {
+	this.memory_controller.Fini()
+	this.mram.Fini()
+
+	if this.hammer_tracker != nil {
+		this.hammer_tracker.Fini()
+	}
+}
+
+func (this *TraceReplayer) IsFinished() bool {
+	for i, entries := range this.entries {
+		if this.next_entries[i] < len(entries) {
+			return false
+		}
+	}
+
+	return len(this.issue_cycles) == 0 && this.memory_controller.IsEmpty()
+}
+
+func (this *TraceReplayer) Run() {
+	for !this.IsFinished() {
+		this.Cycle()
+	}
+}
+
+func (this *TraceReplayer) Cycle() {
+	this.Issue()
+
+	num_memory_cycles := int(global.FrequencyRatio*float64(this.cycles) - global.FrequencyRatio*float64(this.cycles-1))
+	for i := 0; i < num_memory_cycles; i++ {
+		this.memory_controller.Cycle()
+	}
+
+	this.Complete()
+
+	this.cycles++
+}
+
+func (this *TraceReplayer) Issue() {
+	for i, entries := range this.entries {
+		for this.next_entries[i] < len(entries) && this.memory_controller.CanPush() {
+			entry := entries[this.next_entries[i]]
+
+			if global.ReplayMode == "open" {
+				if entry.IssueCycle() > this.cycles {
+					break
+				}
+			} else if this.ready_cycles[i] > this.cycles || this.HasPending(i) {
+				break
+			}
+
+			dma_command := new(dram.DmaCommand)
+			if entry.MemoryOperation() == dram.READ {
+				dma_command.InitReadFromMram(entry.MramAddress(), entry.Size())
+			} else {
+				byte_stream := new(encoding.ByteStream)
+				byte_stream.Init()
+				for j := int64(0); j < entry.Size(); j++ {
+					byte_stream.Append(0)
+				}
+
+				dma_command.InitWriteToMram(entry.MramAddress(), entry.Size(), byte_stream)
+			}
+			dma_command.SetThreadId(i)
+			dma_command.SetIssueCycle(this.memory_controller.Cycles())
+
+			this.memory_controller.Push(dma_command)
+
+			this.issue_cycles[dma_command] = this.cycles
+			this.dma_entries[dma_command] = entry
+			this.next_entries[i]++
+		}
+	}
+}
+
+func (this *TraceReplayer) HasPending(thread_id int) bool {
+	for dma_command := range this.issue_cycles {
+		if dma_command.ThreadId() == thread_id {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *TraceReplayer) Complete() {
+	for this.memory_controller.CanPop() {
+		dma_command := this.memory_controller.Pop()
+
+		issue_cycle, found := this.issue_cycles[dma_command]
+		if !found {
+			err := errors.New("DMA command is not issued by the trace replayer")
+			panic(err)
+		}
+
+		entry := this.dma_entries[dma_command]
+		latency := this.cycles - issue_cycle
+
+		if entry.MemoryOperation() == dram.READ {
+			this.read_latency.Add(latency)
+			this.stat_factory.Increment("read_bytes", entry.Size())
+		} else {
+			this.write_latency.Add(latency)
+			this.stat_factory.Increment("write_bytes", entry.Size())
+		}
+		this.stat_factory.Increment("num_requests", 1)
+
+		thread_id := dma_command.ThreadId()
+		next_entry := this.next_entries[thread_id]
+		if next_entry < len(this.entries[thread_id]) {
+			think_cycles := this.entries[thread_id][next_entry].IssueCycle() - entry.CompleteCycle()
+			if think_cycles < 0 {
+				think_cycles = 0
+			}
+
+			this.ready_cycles[thread_id] = this.cycles + think_cycles
+		}
+
+		delete(this.issue_cycles, dma_command)
+		delete(this.dma_entries, dma_command)
+	}
+}
+
+func (this *TraceReplayer) Lines() []string {
+	lines := make([]string, 0)
+
+	lines = append(lines, fmt.Sprintf("TraceReplayer_cycles: %d", this.cycles))
+	lines = append(lines, this.stat_factory.ToLines()...)
+
+	num_bytes := this.stat_factory.Value("read_bytes") + this.stat_factory.Value("write_bytes")
+	if this.cycles > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"TraceReplayer_bandwidth: %.4f bytes/cycle",
+			float64(num_bytes)/float64(this.cycles),
+		))
+	}
+
+	for _, line := range this.read_latency.ToLines() {
+		lines = append(lines, "TraceReplayer_"+line)
+	}
+	for _, line := range this.write_latency.ToLines() {
+		lines = append(lines, "TraceReplayer_"+line)
+	}
+
+	lines = append(lines, this.memory_controller.StatFactory().ToLines()...)
+	lines = append(lines, this.memory_controller.DmaProfiler().Lines(this.memory_controller.Cycles())...)
+	lines = append(lines, this.memory_controller.MemoryScheduler().StatFactory().ToLines()...)
+	for _, row_buffer := range this.memory_controller.RowBuffers() {
+		lines = append(lines, row_buffer.StatFactory().ToLines()...)
+	}
+
+	if this.hammer_tracker != nil {
+		lines = append(lines, this.hammer_tracker.StatFactory().ToLines()...)
+		lines = append(lines, this.hammer_tracker.Lines()...)
+	}
+
+	return lines
+}
+
+func (this *TraceReplayer) Dump() {
+	lines := this.Lines()
+
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(global.BinDirpath, "replay.txt"))
+	file_dumper.WriteLines(lines)
+}
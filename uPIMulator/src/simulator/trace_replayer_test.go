@@ -0,0 +1,252 @@
+package simulator_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"uPIMulator/src/global"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator"
+	"uPIMulator/src/simulator/dpu/dram"
+	"uPIMulator/src/simulator/dpu/logic"
+	"uPIMulator/src/simulator/dpu/sram"
+)
+
+// SetMemoryGlobals sets the MRAM parameters to the defaults of the command line, except for 1 KB
+// wordlines and accesses so that the MRAM is cheap to build. The MRAM writes whole wordlines, so
+// every DMA of the tests covers whole wordlines.
+func SetMemoryGlobals() {
+	global.LogicFrequency = 350
+	global.MemoryFrequency = 2400
+	global.FrequencyRatio = float64(global.MemoryFrequency) / float64(global.LogicFrequency)
+	global.WordlineSize = 1024
+	global.MinAccessGranularity = 1024
+	global.TRcd = 32
+	global.TRas = 78
+	global.TRp = 32
+	global.TCl = 32
+	global.TBl = 8
+	global.PagePolicy = "open"
+	global.PageTimeout = 64
+	global.NumBanks = 1
+	global.NumBankGroups = 1
+	global.AddressMapping = "ro_ba_co"
+	global.SchedulingPolicy = "frfcfs"
+	global.FrFcfsCap = 4
+	global.WriteHighWatermark = 64
+	global.WriteLowWatermark = 32
+	global.ReplayMode = "closed"
+	global.HammerThreshold = 0
+}
+
+type TracedDma struct {
+	thread_id    int
+	op_code      instruction.OpCode
+	mram_address int64
+	size         int64
+}
+
+func NewDmaInstruction(op_code instruction.OpCode) *instruction.Instruction {
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(0)
+
+	src_reg_descriptor := new(reg_descriptor.SrcRegDescriptor)
+	src_reg_descriptor.InitGpRegDescriptor(gp_reg_descriptor)
+
+	instruction_ := new(instruction.Instruction)
+	instruction_.InitDmaRri(op_code, src_reg_descriptor, src_reg_descriptor, 0)
+	return instruction_
+}
+
+// RecordDmaTrace runs the DMAs through the DMA engine of a DPU with its trace connected. As a
+// tasklet blocks on ldma/sdma, a tasklet issues its next DMA only after its previous one is done.
+func RecordDmaTrace(t *testing.T, dmas []TracedDma) *dram.DmaTrace {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	mram := new(dram.Mram)
+	mram.Init()
+
+	memory_controller := new(dram.MemoryController)
+	memory_controller.Init(0, 0, 0)
+	memory_controller.ConnectMram(mram)
+
+	wram := new(sram.Wram)
+	wram.Init()
+
+	operand_collector := new(logic.OperandCollector)
+	operand_collector.Init()
+	operand_collector.ConnectWram(wram)
+
+	dma_trace := new(dram.DmaTrace)
+	dma_trace.Init()
+
+	dma := new(logic.Dma)
+	dma.Init()
+	dma.ConnectMemoryController(memory_controller)
+	dma.ConnectOperandCollector(operand_collector)
+	dma.ConnectDmaTrace(dma_trace)
+
+	next_dmas := make(map[int][]TracedDma, 0)
+	for _, traced_dma := range dmas {
+		next_dmas[traced_dma.thread_id] = append(next_dmas[traced_dma.thread_id], traced_dma)
+	}
+	is_blocked := make(map[int]bool, 0)
+
+	for cycle := int64(0); len(dma_trace.Entries()) < len(dmas); cycle++ {
+		if cycle > 1000000 {
+			t.Fatalf("%d of %d DMAs are recorded", len(dma_trace.Entries()), len(dmas))
+		}
+
+		for thread_id := 0; thread_id < config_loader.MaxNumTasklets(); thread_id++ {
+			if is_blocked[thread_id] || len(next_dmas[thread_id]) == 0 || !dma.CanPush() {
+				continue
+			}
+
+			traced_dma := next_dmas[thread_id][0]
+			next_dmas[thread_id] = next_dmas[thread_id][1:]
+			is_blocked[thread_id] = true
+
+			instruction_ := NewDmaInstruction(traced_dma.op_code)
+			wram_address := config_loader.WramOffset()
+			if traced_dma.op_code == instruction.LDMA {
+				dma.TransferFromMramToWram(
+					wram_address,
+					traced_dma.mram_address,
+					traced_dma.size,
+					thread_id,
+					0,
+					instruction_,
+				)
+			} else {
+				dma.TransferFromWramToMram(
+					wram_address,
+					traced_dma.mram_address,
+					traced_dma.size,
+					thread_id,
+					0,
+					instruction_,
+				)
+			}
+		}
+
+		dma.Cycle()
+
+		num_memory_cycles := int(
+			global.FrequencyRatio*float64(cycle) - global.FrequencyRatio*float64(cycle-1),
+		)
+		for i := 0; i < num_memory_cycles; i++ {
+			memory_controller.Cycle()
+		}
+
+		for dma.CanPop() {
+			is_blocked[dma.Pop().ThreadId()] = false
+		}
+	}
+
+	return dma_trace
+}
+
+func TestTraceReplayerRoundTrip(t *testing.T) {
+	SetMemoryGlobals()
+	global.BinDirpath = t.TempDir()
+
+	mram_offset := int64(128 * 1024 * 1024)
+
+	tests := []struct {
+		name string
+		dmas []TracedDma
+	}{
+		{"single read", []TracedDma{{0, instruction.LDMA, mram_offset, 1024}}},
+		{
+			"one tasklet",
+			[]TracedDma{
+				{0, instruction.LDMA, mram_offset, 1024},
+				{0, instruction.SDMA, mram_offset + 4096, 2048},
+				{0, instruction.LDMA, mram_offset + 8192, 2048},
+			},
+		},
+		{
+			"tasklets sharing rows",
+			[]TracedDma{
+				{0, instruction.LDMA, mram_offset, 1024},
+				{1, instruction.LDMA, mram_offset, 2048},
+				{2, instruction.SDMA, mram_offset + 1024, 1024},
+				{0, instruction.SDMA, mram_offset + 2048, 1024},
+				{1, instruction.LDMA, mram_offset + 65536, 1024},
+				{23, instruction.LDMA, mram_offset + 1024*1024, 4096},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dma_trace := RecordDmaTrace(t, test.dmas)
+
+			path := filepath.Join(t.TempDir(), "dma_trace.txt")
+			dma_trace.Dump(path)
+
+			loaded := new(dram.DmaTrace)
+			loaded.Init()
+			loaded.Load(path)
+
+			if len(loaded.Entries()) != len(dma_trace.Entries()) {
+				t.Fatalf(
+					"%d entries are loaded, want %d",
+					len(loaded.Entries()),
+					len(dma_trace.Entries()),
+				)
+			}
+
+			read_bytes := int64(0)
+			write_bytes := int64(0)
+			for i, entry := range dma_trace.Entries() {
+				if *loaded.Entries()[i] != *entry {
+					t.Errorf("entry %d = %+v, want %+v", i, *loaded.Entries()[i], *entry)
+				}
+
+				if entry.CompleteCycle() <= entry.IssueCycle() {
+					t.Errorf(
+						"entry %d completes at %d, issued at %d",
+						i,
+						entry.CompleteCycle(),
+						entry.IssueCycle(),
+					)
+				}
+
+				if entry.MemoryOperation() == dram.READ {
+					read_bytes += entry.Size()
+				} else {
+					write_bytes += entry.Size()
+				}
+			}
+
+			trace_replayer := new(simulator.TraceReplayer)
+			trace_replayer.Init(path)
+			trace_replayer.Run()
+			lines := trace_replayer.Lines()
+			trace_replayer.Fini()
+
+			want_lines := []string{
+				fmt.Sprintf("TraceReplayer_num_requests: %d", len(test.dmas)),
+			}
+			if read_bytes > 0 {
+				want_line := fmt.Sprintf("TraceReplayer_read_bytes: %d", read_bytes)
+				want_lines = append(want_lines, want_line)
+			}
+			if write_bytes > 0 {
+				want_line := fmt.Sprintf("TraceReplayer_write_bytes: %d", write_bytes)
+				want_lines = append(want_lines, want_line)
+			}
+
+			for _, want_line := range want_lines {
+				if !slices.Contains(lines, want_line) {
+					t.Errorf("replay does not report %q", want_line)
+				}
+			}
+		})
+	}
+}